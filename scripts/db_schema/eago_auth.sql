@@ -15,6 +15,62 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `access_tokens`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `access_tokens`
+(
+    `id`           int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`      int(11) unsigned NOT NULL,
+    `name`         varchar(100) NOT NULL,
+    `token_hash`   char(64)     NOT NULL,
+    `token_prefix` varchar(20)  NOT NULL,
+    `scopes`       text         NOT NULL,
+    `expires_at`   datetime              DEFAULT NULL,
+    `last_used_at` datetime              DEFAULT NULL,
+    `created_by`   varchar(100) NOT NULL DEFAULT '',
+    `created_at`   datetime     NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `access_tokens_id_uindex` (`id`),
+    UNIQUE KEY `access_tokens_token_hash_uindex` (`token_hash`),
+    KEY          `access_tokens_user_id_index` (`user_id`),
+    CONSTRAINT `access_tokens_users_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `audit_logs`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `audit_logs`
+(
+    `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `service`     varchar(50)         NOT NULL,
+    `actor_id`    int(11) unsigned    NOT NULL DEFAULT '0',
+    `actor`       varchar(100)        NOT NULL DEFAULT '',
+    `action`      varchar(200)        NOT NULL,
+    `resource`    varchar(200)        NOT NULL DEFAULT '',
+    `resource_id` varchar(200)        NOT NULL DEFAULT '',
+    `before`      mediumtext,
+    `after`       mediumtext,
+    `diff`        mediumtext,
+    `ip`          varchar(50)         NOT NULL DEFAULT '',
+    `trace_id`    varchar(50)         NOT NULL DEFAULT '',
+    `succeeded`   tinyint(1)          NOT NULL DEFAULT '0',
+    `created_at`  datetime            NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY          `audit_logs_actor_id_index` (`actor_id`),
+    KEY          `audit_logs_resource_index` (`resource`, `resource_id`),
+    KEY          `audit_logs_trace_id_index` (`trace_id`),
+    KEY          `audit_logs_created_at_index` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `departments`
 --
@@ -23,14 +79,17 @@
 /*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `departments`
 (
-    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT,
-    `name`       varchar(100) NOT NULL,
-    `parent_id`  int(11) unsigned DEFAULT NULL,
-    `created_at` datetime     NOT NULL,
-    `updated_at` datetime DEFAULT NULL,
+    `id`          int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `name`        varchar(100) NOT NULL,
+    `parent_id`   int(11) unsigned DEFAULT NULL,
+    `source`      varchar(20)  DEFAULT NULL,
+    `external_id` varchar(100) DEFAULT NULL,
+    `created_at`  datetime     NOT NULL,
+    `updated_at`  datetime DEFAULT NULL,
     PRIMARY KEY (`id`),
     UNIQUE KEY `departments_id_uindex` (`id`),
-    UNIQUE KEY `departments_name_uindex` (`name`)
+    UNIQUE KEY `departments_name_uindex` (`name`),
+    UNIQUE KEY `departments_source_external_id_uindex` (`source`, `external_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -75,6 +134,28 @@ CREATE TABLE `products`
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `role_permissions`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `role_permissions`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `role_id`    int(11) unsigned NOT NULL,
+    `permission` varchar(200) NOT NULL,
+    `product_id` int(11) unsigned      DEFAULT NULL,
+    `created_at` datetime     NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `role_permissions_id_uindex` (`id`),
+    UNIQUE KEY `role_permissions_role_id_permission_product_id_uindex` (`role_id`,`permission`,`product_id`),
+    KEY          `role_permissions_product_id_index` (`product_id`),
+    CONSTRAINT `role_permissions_roles_id_fk` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `role_permissions_products_id_fk` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `roles`
 --
@@ -137,6 +218,47 @@ CREATE TABLE `user_groups`
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `user_identities`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_identities`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`    int(11) unsigned NOT NULL,
+    `provider`   varchar(20)  NOT NULL,
+    `issuer`     varchar(255) NOT NULL,
+    `subject`    varchar(255) NOT NULL,
+    `created_at` datetime     NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_identities_id_uindex` (`id`),
+    UNIQUE KEY `user_identities_issuer_subject_uindex` (`issuer`,`subject`),
+    KEY          `user_identities_user_id_index` (`user_id`),
+    CONSTRAINT `user_identities_users_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_password_histories`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_password_histories`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`    int(11) unsigned NOT NULL,
+    `password`   varchar(200) NOT NULL,
+    `created_at` datetime     NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_password_histories_id_uindex` (`id`),
+    KEY          `user_password_histories_user_id_index` (`user_id`),
+    CONSTRAINT `user_password_histories_users_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `user_products`
 --
@@ -160,6 +282,26 @@ CREATE TABLE `user_products`
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `user_recovery_codes`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_recovery_codes`
+(
+    `id`         int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `user_id`    int(11) unsigned NOT NULL,
+    `code_hash`  varchar(64) NOT NULL,
+    `used_at`    datetime             DEFAULT NULL,
+    `created_at` datetime    NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `user_recovery_codes_id_uindex` (`id`),
+    KEY          `user_recovery_codes_user_id_index` (`user_id`),
+    CONSTRAINT `user_recovery_codes_users_id_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `user_roles`
 --
@@ -196,6 +338,14 @@ CREATE TABLE `users`
     `phone`        varchar(20)  NOT NULL DEFAULT '',
     `is_superuser` tinyint(1) NOT NULL DEFAULT '0',
     `disabled`     tinyint(1) NOT NULL DEFAULT '0',
+    `is_service_account` tinyint(1) NOT NULL DEFAULT '0',
+    `must_change_password` tinyint(1) NOT NULL DEFAULT '0',
+    `failed_login_count` int(11) NOT NULL DEFAULT '0',
+    `locked_until` datetime DEFAULT NULL,
+    `password_changed_at` datetime DEFAULT NULL,
+    `totp_secret` varchar(64) NOT NULL DEFAULT '',
+    `totp_enabled` tinyint(1) NOT NULL DEFAULT '0',
+    `totp_required` tinyint(1) NOT NULL DEFAULT '0',
     `last_login`   datetime              DEFAULT NULL,
     `created_at`   datetime     NOT NULL,
     `updated_at`   datetime              DEFAULT NULL,
@@ -15,6 +15,36 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `audit_logs`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `audit_logs`
+(
+    `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `service`     varchar(50)         NOT NULL,
+    `actor_id`    int(11) unsigned    NOT NULL DEFAULT '0',
+    `actor`       varchar(100)        NOT NULL DEFAULT '',
+    `action`      varchar(200)        NOT NULL,
+    `resource`    varchar(200)        NOT NULL DEFAULT '',
+    `resource_id` varchar(200)        NOT NULL DEFAULT '',
+    `before`      mediumtext,
+    `after`       mediumtext,
+    `diff`        mediumtext,
+    `ip`          varchar(50)         NOT NULL DEFAULT '',
+    `trace_id`    varchar(50)         NOT NULL DEFAULT '',
+    `succeeded`   tinyint(1)          NOT NULL DEFAULT '0',
+    `created_at`  datetime            NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY          `audit_logs_actor_id_index` (`actor_id`),
+    KEY          `audit_logs_resource_index` (`resource`, `resource_id`),
+    KEY          `audit_logs_trace_id_index` (`trace_id`),
+    KEY          `audit_logs_created_at_index` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `categories`
 --
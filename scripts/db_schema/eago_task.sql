@@ -15,6 +15,74 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `artifacts`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `artifacts`
+(
+    `id`          int(11) unsigned NOT NULL AUTO_INCREMENT,
+    `name`        varchar(100) NOT NULL,
+    `version`     int(11) unsigned NOT NULL,
+    `sha256`      char(64)     NOT NULL,
+    `size`        bigint(20) NOT NULL DEFAULT '0',
+    `storage`     varchar(20)  NOT NULL,
+    `description` varchar(500) NOT NULL DEFAULT '',
+    `created_at`  datetime     NOT NULL,
+    `created_by`  varchar(100) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `artifacts_id_uindex` (`id`),
+    UNIQUE KEY `artifacts_name_version_uindex` (`name`, `version`),
+    KEY           `artifacts_sha256_index` (`sha256`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `artifact_blobs`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `artifact_blobs`
+(
+    `sha256`  char(64) NOT NULL,
+    `content` longblob NOT NULL,
+    PRIMARY KEY (`sha256`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `audit_logs`
+--
+
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `audit_logs`
+(
+    `id`          bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `service`     varchar(50)         NOT NULL,
+    `actor_id`    int(11) unsigned    NOT NULL DEFAULT '0',
+    `actor`       varchar(100)        NOT NULL DEFAULT '',
+    `action`      varchar(200)        NOT NULL,
+    `resource`    varchar(200)        NOT NULL DEFAULT '',
+    `resource_id` varchar(200)        NOT NULL DEFAULT '',
+    `before`      mediumtext,
+    `after`       mediumtext,
+    `diff`        mediumtext,
+    `ip`          varchar(50)         NOT NULL DEFAULT '',
+    `trace_id`    varchar(50)         NOT NULL DEFAULT '',
+    `succeeded`   tinyint(1)          NOT NULL DEFAULT '0',
+    `created_at`  datetime            NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY          `audit_logs_actor_id_index` (`actor_id`),
+    KEY          `audit_logs_resource_index` (`resource`, `resource_id`),
+    KEY          `audit_logs_trace_id_index` (`trace_id`),
+    KEY          `audit_logs_created_at_index` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `result_partitions`
 --
@@ -45,6 +113,9 @@ CREATE TABLE `schedules`
     `timeout`       bigint(20) NOT NULL DEFAULT '0',
     `arguments`     json         NOT NULL,
     `disabled`      tinyint(1) NOT NULL DEFAULT '0',
+    `product_id`    int(11) unsigned NOT NULL DEFAULT '0',
+    `group_id`      int(11) unsigned NOT NULL DEFAULT '0',
+    `role_name`     varchar(100) NOT NULL DEFAULT '',
     `description`   varchar(500) NOT NULL DEFAULT '',
     `created_at`    datetime     NOT NULL,
     `created_by`    varchar(100) NOT NULL DEFAULT '',
@@ -68,7 +139,16 @@ CREATE TABLE `tasks`
     `codename`      varchar(100) NOT NULL,
     `description`   varchar(500) NOT NULL DEFAULT '',
     `formal_params` json         NOT NULL,
+    `script`        mediumtext            DEFAULT NULL,
+    `script_env`    json                  DEFAULT NULL,
+    `artifact_name`    varchar(100) NOT NULL DEFAULT '',
+    `artifact_version` int(11) unsigned NOT NULL DEFAULT '0',
     `disabled`      tinyint(1) NOT NULL DEFAULT '0',
+    `product_id`    int(11) unsigned NOT NULL DEFAULT '0',
+    `group_id`      int(11) unsigned NOT NULL DEFAULT '0',
+    `role_name`     varchar(100) NOT NULL DEFAULT '',
+    `orphaned`      tinyint(1) NOT NULL DEFAULT '0',
+    `last_seen_at`  datetime              DEFAULT NULL,
     `created_at`    datetime     NOT NULL,
     `created_by`    varchar(100) NOT NULL DEFAULT '',
     `updated_at`    datetime              DEFAULT NULL,
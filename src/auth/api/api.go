@@ -7,7 +7,10 @@ import (
 	"eago/auth/dao"
 	"eago/common/api"
 	perm "eago/common/api/permission"
+	"eago/common/api/ratelimit"
+	"eago/common/audit"
 	"eago/common/logger"
+	"eago/common/metrics"
 	"eago/common/redis"
 	"eago/common/service"
 	"eago/common/tracer"
@@ -32,7 +35,7 @@ type authApi struct {
 	cancelFunc context.CancelFunc
 }
 
-func NewAuthApi(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
+func NewAuthApi(dao *dao.Dao, auditor audit.Auditor, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// 生成etcdRegistry
@@ -57,7 +60,7 @@ func NewAuthApi(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 	_api := web.NewService(
 		web.Name(conf.Const.ApiRegisterKey),
 		web.Address(conf.ApiListen),
-		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler)),
+		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler, auditor, redis)),
 		web.Registry(etcdReg),
 		web.RegisterTTL(conf.MicroRegisterTtl),
 		web.RegisterInterval(conf.MicroRegisterInterval),
@@ -81,6 +84,16 @@ func NewAuthApi(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 func (aa *authApi) Start() error {
 	aa.logger.Info("Starting auth api ...")
 
+	// 在内部监听地址上暴露Prometheus指标，不对外暴露
+	go func() {
+		if err := metrics.Serve(aa.ctx, aa.conf.ApiMetricsListen); err != nil {
+			aa.logger.ErrorWithFields(logger.Fields{
+				"address": aa.conf.ApiMetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
 	return aa.api.Run()
 }
 
@@ -90,23 +103,69 @@ func (aa *authApi) Stop() {
 	}
 }
 
-func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *handler.AuthHandler) *gin.Engine {
+func newGinEngine(
+	ginMode string,
+	_conf *conf.Conf,
+	logger *logger.Logger,
+	h *handler.AuthHandler,
+	auditor audit.Auditor,
+	redis *redis.RedisTool,
+) *gin.Engine {
 	gin.SetMode(ginMode)
 
 	//engine := gin.Default()
 	//engine.Use(api.OpentracingMiddleware)
 	engine := gin.New()
-	engine.Use(api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware)
+	// 客户端IP只从可信代理转发的X-Forwarded-For中获取，gin自身的解析在未调用engine.Run时会信任任意来源
+	engine.ForwardedByClientIP = false
+	engine.Use(
+		api.ClientIpMiddleware(_conf.TrustedProxies),
+		api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware, api.MetricsMiddleware,
+	)
+
+	// 支持游标分页的列表及其允许排序的字段
+	usersPaging := api.KeysetPagingQueryMiddleware("id", "username", "last_login", "created_at", "updated_at")
+	// 敏感操作按配置要求Token通过了两步验证
+	mustMfa := perm.MustMfaIf(_conf.SensitiveRequireMfa)
+
+	// 登录限流，按IP及用户名分别统计失败次数，防止暴力破解
+	loginLimitOpts := []ratelimit.Option{
+		ratelimit.Window(_conf.LoginRateWindow),
+		ratelimit.Lockout(_conf.LoginRateLockout),
+		ratelimit.Delay(_conf.LoginRateDelayAfter, _conf.LoginRateDelayBase, _conf.LoginRateDelayMax),
+		ratelimit.FailuresOnly(),
+		ratelimit.Publisher(h.GetPublisher()),
+		ratelimit.Logger(logger),
+	}
+	loginIpLimit := ratelimit.NewLimiter(
+		redis, "login_ip", append(loginLimitOpts, ratelimit.Limit(_conf.LoginRateIpLimit))...,
+	).Middleware(ratelimit.ByIp)
+	// 同一用户登录成功后清除失败次数
+	loginUserLimit := ratelimit.NewLimiter(
+		redis, "login_user", append(loginLimitOpts, ratelimit.Limit(_conf.LoginRateUserLimit), ratelimit.ResetOnSuccess())...,
+	).Middleware(ratelimit.ByJsonField("username"))
 
 	// 登录
-	engine.POST("/auth/login", h.ReadLoginForm, h.CrowdLogin, h.DatabaseLogin, h.LoginFailed)
-	engine.POST("/auth/login/eagle", h.LoginFromEagle)
+	engine.POST("/auth/login", loginIpLimit, loginUserLimit, h.ReadLoginForm, h.PasswordLogin, h.LoginFailed)
+	engine.POST("/auth/login/eagle", loginIpLimit, h.LoginFromEagle)
+	// 两步验证，使用第一步登录返回的mfa_token换取Token
+	engine.POST("/auth/login/totp", loginIpLimit, h.TotpLogin)
+	// 被要求启用两步验证但尚未绑定时，使用mfa_token绑定并换取Token
+	engine.POST("/auth/login/totp/enroll", loginIpLimit, h.TotpEnrollLogin)
+	engine.POST("/auth/login/totp/confirm", loginIpLimit, h.TotpConfirmLogin)
+	// 使用原密码修改密码，无需登录
+	engine.PUT("/auth/password", loginIpLimit, loginUserLimit, h.ChangePassword)
+	engine.GET("/auth/login/oidc", h.OidcLogin)
+	engine.GET("/auth/login/oidc/callback", h.OidcCallback)
 	engine.GET("/auth/token/content", h.GetTokenContent)
 
-	g := engine.Group("/auth", perm.MustLogin(h.GetAuthCli()))
+	g := engine.Group("/auth", perm.MustLogin(h.GetAuthCli()), auditor.Middleware())
 	{
-		g.POST("heartbeat", h.Heartbeat)
+		// 心跳无需审计
+		g.POST("heartbeat", audit.Skip, h.Heartbeat)
 		g.DELETE("logout", h.Logout)
+		// 当前登录用户关联OIDC外部身份
+		g.POST("/login/oidc/link", h.OidcLink)
 
 		// 根据当前登录用户权限列出菜单
 		g.GET("/menus", h.ListMenus)
@@ -210,22 +269,35 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 			rr.GET("", api.PagingQueryMiddleware, h.PagedListRoles)
 
 			// 添加用户至角色
-			rr.POST("/:role_id/users", perm.MustRole(_conf.Const.AdminRole), h.AddUser2Role)
+			rr.POST("/:role_id/users", perm.MustRole(_conf.Const.AdminRole), mustMfa, h.AddUser2Role)
 			// 移除角色中用户
-			rr.DELETE("/:role_id/users/:user_id", perm.MustRole(_conf.Const.AdminRole), h.RemoveRolesUser)
+			rr.DELETE("/:role_id/users/:user_id", perm.MustRole(_conf.Const.AdminRole), mustMfa, h.RemoveRolesUser)
 			// 列出角色所有用户
 			rr.GET("/:role_id/users", h.ListRolesUsers)
+
+			// 授予角色权限
+			rr.POST("/:role_id/permissions", perm.MustRole(_conf.Const.AdminRole), mustMfa, h.AddRolePermission)
+			// 移除角色指定权限
+			rr.DELETE("/:role_id/permissions/:permission_id", perm.MustRole(_conf.Const.AdminRole), mustMfa, h.RemoveRolePermission)
+			// 列出角色所有权限
+			rr.GET("/:role_id/permissions", h.ListRolePermissions)
 		}
 
 		// User模块
 		ur := g.Group("/users")
 		{
+			// 新建服务账号
+			ur.POST("/service_accounts", perm.MustRole(_conf.Const.AdminRole), h.NewServiceAccount)
+
+			// 重置用户密码，用户下次登录前需修改密码
+			ur.PUT("/:user_id/password", perm.MustRole(_conf.Const.AdminRole), h.ResetPassword)
+
 			// 更新用户
 			ur.PUT("/:user_id",
 				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
 				h.SetUser)
 			// 列出所有用户-分页
-			ur.GET("", api.PagingQueryMiddleware, h.PagedListUsers)
+			ur.GET("", usersPaging, h.PagedListUsers)
 
 			// 列出用户所有角色
 			ur.GET("/:user_id/roles",
@@ -248,11 +320,72 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
 				h.GetUsersDepartmentChain)
 
+			// 列出用户所有会话
+			ur.GET("/:user_id/sessions",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.ListUsersSessions)
+			// 注销用户所有会话
+			ur.DELETE("/:user_id/sessions",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.RevokeUsersSessions)
+			// 注销用户指定会话
+			ur.DELETE("/:user_id/sessions/:session_id",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.RevokeUsersSession)
+
+			// 列出用户关联的外部身份
+			ur.GET("/:user_id/identities",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.ListUsersIdentities)
+			// 取消用户关联的外部身份
+			ur.DELETE("/:user_id/identities/:identity_id",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.RemoveUsersIdentity)
+
+			// 生成两步验证密钥
+			ur.POST("/:user_id/totp",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.EnrollTotp)
+			// 使用验证码确认并启用两步验证，返回恢复码
+			ur.PUT("/:user_id/totp/verify",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.ConfirmTotp)
+			// 停用两步验证，用户停用自己的两步验证时需提供当前验证码或恢复码
+			ur.DELETE("/:user_id/totp",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				perm.MustMfa(),
+				h.DisableTotp)
+			// 重新生成恢复码，用户重新生成自己的恢复码时需提供当前验证码或恢复码
+			ur.POST("/:user_id/totp/recovery_codes",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				perm.MustMfa(),
+				h.RegenerateRecoveryCodes)
+			// 设置用户是否必须启用两步验证
+			ur.PUT("/:user_id/totp/required", perm.MustRole(_conf.Const.AdminRole), h.SetTotpRequired)
+
+			// 新建访问令牌，管理员只能为自己或服务账号创建，访问令牌不能再创建访问令牌
+			ur.POST("/:user_id/access_tokens",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.NewAccessToken)
+			// 列出用户所有访问令牌
+			ur.GET("/:user_id/access_tokens",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.ListUsersAccessTokens)
+			// 删除用户指定访问令牌
+			ur.DELETE("/:user_id/access_tokens/:access_token_id",
+				perm.MustCurrUserOrRole("user_id", _conf.Const.AdminRole),
+				h.RevokeUsersAccessToken)
+
 			// 用户交接
 			ur.GET("/handover/:user_id/:target_user_id",
 				perm.MustRole(_conf.Const.AdminRole),
 				h.MakeUserHandover)
 		}
+
+		// 审计日志，要求管理员权限
+		g.GET("/audit_logs", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, auditor.ListHandler)
+		// 导出审计日志，要求管理员权限
+		g.GET("/audit_logs/export", perm.MustRole(_conf.Const.AdminRole), auditor.ExportHandler)
 	}
 
 	engine.NoRoute(api.PageNotFound)
@@ -0,0 +1,95 @@
+package form
+
+import (
+	"context"
+	"eago/auth/conf/msg"
+	"eago/auth/dao"
+	perm "eago/common/api/permission"
+	cMsg "eago/common/code_msg"
+	"eago/common/orm"
+	"github.com/beego/beego/v2/core/validation"
+)
+
+type NewAccessTokenForm struct {
+	Name   string   `json:"name" valid:"Required;MinSize(1);MaxSize(100)"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays 有效天数，为0时永不过期
+	ExpiresInDays int `json:"expires_in_days" valid:"Min(0);Max(3650)"`
+}
+
+func (f *NewAccessTokenForm) Valid(v *validation.Validation) {
+	if len(f.Scopes) < 1 {
+		_ = v.SetError("Scopes", "至少需要指定一个路由范围")
+		return
+	}
+	for _, s := range f.Scopes {
+		if !perm.IsValidScope(s) {
+			_ = v.SetError("Scopes", "路由范围格式不正确: "+s)
+			return
+		}
+	}
+}
+
+// Validate currUserId为当前用户，byAccessToken为当前Token是否为访问令牌
+// 访问令牌不能再创建访问令牌，管理员只能为自己或服务账号创建，避免冒用其他用户身份
+func (f *NewAccessTokenForm) Validate(
+	ctx context.Context, dao *dao.Dao, userId, currUserId uint32, byAccessToken bool,
+) *cMsg.CodeMsg {
+	if byAccessToken {
+		return msg.MsgAccessTokenByTokenFailed
+	}
+
+	// 用户不存在
+	u, err := dao.GetUser(ctx, orm.Query{"id=?": userId})
+	if err != nil || u == nil || u.Id < 1 {
+		return cMsg.MsgNotFoundFailed.SetDetail("用户不存在")
+	}
+	if u.Id != currUserId && !u.IsServiceAccount {
+		return msg.MsgAccessTokenUserFailed
+	}
+
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type NewServiceAccountForm struct {
+	Username string `json:"username" valid:"Required;MinSize(3);MaxSize(100);Match(/^[a-zA-Z][a-zA-Z0-9_.-]{1,}$/)"`
+	Email    string `json:"email" valid:"Email;MaxSize(100)"`
+
+	dao *dao.Dao
+	ctx context.Context
+}
+
+func (f *NewServiceAccountForm) Valid(v *validation.Validation) {
+	if exist, _ := f.dao.IsUserExist(f.ctx, orm.Query{"username=?": f.Username}); exist {
+		_ = v.SetError("Username", "已有相同用户名的用户存在")
+	}
+}
+
+func (f *NewServiceAccountForm) Validate(ctx context.Context, dao *dao.Dao) *cMsg.CodeMsg {
+	f.ctx = ctx
+	f.dao = dao
+
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
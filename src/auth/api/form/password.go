@@ -0,0 +1,70 @@
+package form
+
+import (
+	"context"
+	"eago/auth/dao"
+	cMsg "eago/common/code_msg"
+	"eago/common/orm"
+	"github.com/beego/beego/v2/core/validation"
+)
+
+type ChangePasswordForm struct {
+	Username    string `json:"username" valid:"Required;MinSize(3);MaxSize(100)"`
+	OldPassword string `json:"old_password" valid:"Required;MaxSize(100)"`
+	NewPassword string `json:"new_password" valid:"Required;MaxSize(100)"`
+}
+
+func (f *ChangePasswordForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type ResetPasswordForm struct {
+	NewPassword string `json:"new_password" valid:"Required;MaxSize(100)"`
+
+	userId uint32
+
+	dao *dao.Dao
+	ctx context.Context
+}
+
+func (f *ResetPasswordForm) Valid(v *validation.Validation) {
+	// 服务账号不能设置密码
+	if exist, _ := f.dao.IsUserExist(f.ctx, orm.Query{"id=?": f.userId, "is_service_account=?": true}); exist {
+		_ = v.SetError("UserId", "服务账号不能设置密码")
+	}
+}
+
+func (f *ResetPasswordForm) Validate(ctx context.Context, dao *dao.Dao, userId uint32) *cMsg.CodeMsg {
+	// 用户不存在
+	if ct, _ := dao.GetUserCount(ctx, orm.Query{"id=?": userId}); ct < 1 {
+		return cMsg.MsgNotFoundFailed.SetDetail("用户不存在")
+	}
+
+	f.ctx = ctx
+	f.dao = dao
+	f.userId = userId
+
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
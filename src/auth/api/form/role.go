@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"eago/auth/conf/msg"
 	"eago/auth/dao"
+	perm "eago/common/api/permission"
 	cMsg "eago/common/code_msg"
 	"eago/common/orm"
 	"fmt"
@@ -171,3 +172,63 @@ func (*RemoveRolesUserForm) Validate(ctx context.Context, dao *dao.Dao, roleId,
 
 	return nil
 }
+
+type AddRolePermissionForm struct {
+	Permission string `json:"permission" valid:"Required;MaxSize(200)"`
+	// ProductId 不为空时权限仅在该产品线范围内有效
+	ProductId *uint32 `json:"product_id"`
+
+	roleId uint32
+
+	dao *dao.Dao
+	ctx context.Context
+}
+
+func (f *AddRolePermissionForm) Valid(v *validation.Validation) {
+	if !perm.IsValidPerm(f.Permission) {
+		_ = v.SetError("Permission", "权限格式不正确，应为service:resource:action")
+		return
+	}
+
+	// 全局权限的产品线ID按0处理
+	var prodId uint32
+	if f.ProductId != nil {
+		// 验证产品线是否存在
+		if exist, _ := f.dao.IsProductExist(f.ctx, orm.Query{"id=?": *f.ProductId}); !exist {
+			_ = v.SetError("ProductId", "产品线不存在")
+			return
+		}
+		prodId = *f.ProductId
+	}
+
+	// 验证角色是否已有该权限
+	q := orm.Query{"role_id=?": f.roleId, "permission=?": f.Permission, "IFNULL(product_id, 0)=?": prodId}
+	if exist, _ := f.dao.IsRolePermissionExist(f.ctx, q); exist {
+		_ = v.SetError("Permission", "角色已经拥有该权限")
+	}
+}
+
+func (f *AddRolePermissionForm) Validate(ctx context.Context, dao *dao.Dao, roleId uint32) *cMsg.CodeMsg {
+	// 验证角色是否存在
+	if exist, _ := dao.IsRoleExist(ctx, orm.Query{"id=?": roleId}); !exist {
+		return cMsg.MsgNotFoundFailed.SetDetail("角色不存在")
+	}
+
+	f.roleId = roleId
+
+	f.ctx = ctx
+	f.dao = dao
+
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package form
+
+import (
+	"eago/auth/dto"
+	cMsg "eago/common/code_msg"
+	"github.com/beego/beego/v2/core/validation"
+)
+
+type TotpLoginForm struct {
+	MfaToken     string `json:"mfa_token" valid:"Required;MaxSize(100)"`
+	Code         string `json:"code" valid:"MaxSize(10)"`
+	RecoveryCode string `json:"recovery_code" valid:"MaxSize(20)"`
+}
+
+func (f *TotpLoginForm) Valid(v *validation.Validation) {
+	// 验证码与恢复码必须且只能提供一个
+	if (f.Code == "") == (f.RecoveryCode == "") {
+		_ = v.SetError("Code", "需要提供code或recovery_code中的一个")
+	}
+}
+
+func (f *TotpLoginForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type MfaTokenForm struct {
+	MfaToken string `json:"mfa_token" valid:"Required;MaxSize(100)"`
+}
+
+func (f *MfaTokenForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type TotpConfirmLoginForm struct {
+	MfaToken string `json:"mfa_token" valid:"Required;MaxSize(100)"`
+	Code     string `json:"code" valid:"Required;Length(6);Numeric"`
+}
+
+func (f *TotpConfirmLoginForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type ConfirmTotpForm struct {
+	Code string `json:"code" valid:"Required;Length(6);Numeric"`
+}
+
+func (f *ConfirmTotpForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+type TotpProofForm struct {
+	Code         string `json:"code" valid:"MaxSize(10)"`
+	RecoveryCode string `json:"recovery_code" valid:"MaxSize(20)"`
+
+	required bool
+}
+
+func (f *TotpProofForm) Valid(v *validation.Validation) {
+	// 需要校验时验证码与恢复码必须且只能提供一个
+	if f.required && (f.Code == "") == (f.RecoveryCode == "") {
+		_ = v.SetError("Code", "需要提供code或recovery_code中的一个")
+	}
+}
+
+// Validate required为true时必须提供验证码或恢复码，用户操作自己的两步验证设置时使用
+func (f *TotpProofForm) Validate(required bool) *cMsg.CodeMsg {
+	f.required = required
+
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
+
+// Proof 获得需要校验的验证码或恢复码，不需要校验时返回nil
+func (f *TotpProofForm) Proof() *dto.TotpProof {
+	if !f.required {
+		return nil
+	}
+	return &dto.TotpProof{Code: f.Code, RecoveryCode: f.RecoveryCode}
+}
+
+type SetTotpRequiredForm struct {
+	Required *bool `json:"required" valid:"Required"`
+}
+
+func (f *SetTotpRequiredForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	return nil
+}
@@ -39,6 +39,8 @@ type PagedListUsersParamsForm struct {
 	Query       *string `form:"query"`
 	IsSuperuser *bool   `form:"is_superuser"`
 	Disabled    *bool   `form:"disabled"`
+	// IsServiceAccount 是否是服务账号
+	IsServiceAccount *bool `form:"is_service_account"`
 }
 
 func (pf *PagedListUsersParamsForm) GenQuery() orm.Query {
@@ -57,6 +59,9 @@ func (pf *PagedListUsersParamsForm) GenQuery() orm.Query {
 	if pf.IsSuperuser != nil {
 		query["is_superuser=?"] = pf.IsSuperuser
 	}
+	if pf.IsServiceAccount != nil {
+		query["is_service_account=?"] = pf.IsServiceAccount
+	}
 
 	return query
 }
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"eago/auth/api/form"
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
+	"eago/common/api/ext"
+	perm "eago/common/api/permission"
+	cMsg "eago/common/code_msg"
+	"eago/common/orm"
+	"eago/common/tracer"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// NewAccessToken 为用户新建访问令牌，令牌明文只在创建时返回
+func (ah *AuthHandler) NewAccessToken(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := new(form.NewAccessTokenForm)
+	// 序列化request body
+	if err = c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 验证数据
+	tc := perm.MustGetTokenContent(c)
+	if m := frm.Validate(ctx, ah.dao, userId, tc.UserId, perm.IsAccessToken(c)); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	var expiresAt *time.Time
+	if frm.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, frm.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	tk, at, err := ah.biz.NewAccessToken(
+		ctx, userId, frm.Name, frm.Scopes, expiresAt, tc.Username,
+	)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "access_token", gin.H{"token": tk, "detail": at})
+}
+
+// ListUsersAccessTokens 列出用户所有访问令牌
+func (ah *AuthHandler) ListUsersAccessTokens(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ats, err := ah.dao.ListAccessTokens(tracer.ExtractTraceCtxFromGin(c), orm.Query{"user_id=?": userId})
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "access_tokens", ats)
+}
+
+// RevokeUsersAccessToken 删除用户指定访问令牌
+func (ah *AuthHandler) RevokeUsersAccessToken(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	tokenId, err := ext.ParamUint32(c, "access_token_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "access_token_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	err = ah.biz.RevokeAccessToken(tracer.ExtractTraceCtxFromGin(c), userId, tokenId)
+	if errors.Is(err, dto.ErrAccessTokenNotFound) {
+		m := msg.MsgAccessTokenNotFoundFailed
+		logF := m.ToLoggerFields()
+		logF["user_id"] = userId
+		logF["access_token_id"] = tokenId
+		ah.logger.WarnWithFields(logF, m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// NewServiceAccount 新建服务账号，服务账号只能使用访问令牌
+func (ah *AuthHandler) NewServiceAccount(c *gin.Context) {
+	frm := new(form.NewServiceAccountForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 验证数据
+	if m := frm.Validate(ctx, ah.dao); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	u, err := ah.dao.NewServiceAccount(ctx, frm.Username, frm.Email)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "user", u)
+}
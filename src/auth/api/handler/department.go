@@ -289,7 +289,7 @@ func (ah *AuthHandler) RemoveDepartmentsUser(c *gin.Context) {
 		return
 	}
 
-	if err = ah.dao.RemoveDepartmentsUser(ctx, userId, deptId); err != nil {
+	if err = ah.dao.RemoveDepartmentsUser(ctx, deptId, userId); err != nil {
 		m := msg.MsgAuthDaoErr.SetError(err)
 		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
 		m.Write2GinCtx(c)
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"eago/auth/biz"
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
 	"eago/common/api/ext"
 	"eago/common/logger"
 	"eago/common/tracer"
@@ -14,8 +17,17 @@ func (ah *AuthHandler) LoginFromEagle(c *gin.Context) {
 
 	ctx := tracer.ExtractTraceCtxFromGin(c)
 
+	// 判断是否启用了Eagle登录方式
+	p, ok := ah.biz.GetLoginProvider(dto.LoginMethodEagle).(biz.CredentialLoginProvider)
+	if !ok {
+		m := msg.MsgLoginProviderDisabledFailed
+		ah.logger.Warn(m.GetMsg())
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
 	// 根据eagle token获取用户对象
-	userObj, err := ah.biz.GetUserObjectFromEagleToken(ctx, eagleTk)
+	userObj, err := p.LoginWithCredential(ctx, eagleTk)
 	if err != nil {
 		ah.logger.WarnWithFields(logger.Fields{
 			"eagle_token": eagleTk,
@@ -36,15 +48,5 @@ func (ah *AuthHandler) LoginFromEagle(c *gin.Context) {
 	}
 
 	// 生成token
-	if token := ah.biz.NewToken(ctx, userObj); len(token) > 0 {
-		ah.logger.DebugWithFields(logger.Fields{
-			"user_id":  userObj.Id,
-			"username": userObj.Username,
-			"token":    token,
-		}, "New token success.")
-		ext.WriteSuccessPayload(c, "token", token)
-		return
-	}
-
-	ext.WriteAnyAndAbort(c, http.StatusInternalServerError, "login from eagle failed, got nil token.")
+	ah.newTokenResponse(c, userObj, dto.LoginMethodEagle)
 }
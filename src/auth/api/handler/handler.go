@@ -11,7 +11,6 @@ import (
 	"eago/common/logger"
 	"eago/common/redis"
 	"fmt"
-	"github.com/jda/go-crowd"
 )
 
 type AuthHandler struct {
@@ -19,13 +18,12 @@ type AuthHandler struct {
 	redis *redis.RedisTool
 
 	biz *biz.Biz
+	pub broker.Publisher
 
 	authCli authpb.AuthService
 
 	menu *menu.Menu
 
-	crowdCli *crowd.Crowd
-
 	conf   *conf.Conf
 	logger *logger.Logger
 }
@@ -44,29 +42,22 @@ func NewAuthHandler(_dao *dao.Dao, redis *redis.RedisTool, _conf *conf.Conf, _lo
 		broker.Logger(_logger),
 	)
 
-	// 生成crowdCli
-	_crowdCli, err := crowd.New(_conf.CrowdAppName, _conf.CrowdAppPassword, _conf.CrowdAddress)
-	if err != nil {
-		_logger.WarnWithFields(logger.Fields{
-			"crowd_address":  _conf.CrowdAddress,
-			"crowd_app_name": _conf.CrowdAppName,
-		}, "An error occurred while crowd.New in NewAuthHandler, skipped it.")
-	}
+	// 生成Biz并初始化登录方式
+	_biz := biz.NewBiz(_dao, redis, _pub, _conf, _logger)
+	_biz.InitLoginProviders()
 
 	return &AuthHandler{
 		dao:   _dao,
 		redis: redis,
 
-		// 生成Biz
-		biz: biz.NewBiz(_dao, redis, _pub, _conf, _logger),
+		biz: _biz,
+		pub: _pub,
 
 		// 创建Auth客户端
 		authCli: cli.NewAuthClient(_conf.EtcdUsername, _conf.EtcdPassword, _conf.EtcdAddresses),
 
 		menu: conf.NewMenu(_conf),
 
-		crowdCli: &_crowdCli,
-
 		conf:   _conf,
 		logger: _logger,
 	}
@@ -75,3 +66,7 @@ func NewAuthHandler(_dao *dao.Dao, redis *redis.RedisTool, _conf *conf.Conf, _lo
 func (ah *AuthHandler) GetAuthCli() authpb.AuthService {
 	return ah.authCli
 }
+
+func (ah *AuthHandler) GetPublisher() broker.Publisher {
+	return ah.pub
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"eago/auth/conf/msg"
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// ListUsersIdentities 列出用户关联的外部身份
+func (ah *AuthHandler) ListUsersIdentities(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	is, err := ah.dao.ListUserIdentities(tracer.ExtractTraceCtxFromGin(c), userId)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "identities", is)
+}
+
+// RemoveUsersIdentity 取消用户关联的外部身份
+func (ah *AuthHandler) RemoveUsersIdentity(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	identityId, err := ext.ParamUint32(c, "identity_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "identity_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ok, err := ah.dao.RemoveUserIdentity(tracer.ExtractTraceCtxFromGin(c), userId, identityId)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if !ok {
+		m := cMsg.MsgNotFoundFailed
+		logF := m.ToLoggerFields()
+		logF["user_id"] = userId
+		logF["identity_id"] = identityId
+		ah.logger.WarnWithFields(logF, m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
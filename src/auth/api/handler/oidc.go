@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"eago/auth/biz"
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
+	"eago/common/api/ext"
+	perm "eago/common/api/permission"
+	"eago/common/logger"
+	"eago/common/tracer"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// oidcBindingCookieName 保存OIDC登录binding的Cookie名
+	oidcBindingCookieName = "eago_oidc_binding"
+	// oidcBindingCookiePath binding Cookie的路径，覆盖登录、关联及回调接口
+	oidcBindingCookiePath = "/auth/login/oidc"
+)
+
+// OidcLogin 跳转至OIDC身份提供方登录
+func (ah *AuthHandler) OidcLogin(c *gin.Context) {
+	p, ok := ah.getOidcLoginProvider(c)
+	if !ok {
+		return
+	}
+
+	authUrl, binding, err := p.AuthCodeURL(tracer.ExtractTraceCtxFromGin(c))
+	if err != nil {
+		m := msg.MsgLoginUnknownFailed.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.AuthCodeURL in authHandler.OidcLogin.")
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
+	ah.setOidcBindingCookie(c, binding)
+	c.Redirect(http.StatusFound, authUrl)
+}
+
+// OidcLink 当前登录用户关联OIDC外部身份，返回跳转至身份提供方的地址，由前端跳转，回调时完成关联并登录
+func (ah *AuthHandler) OidcLink(c *gin.Context) {
+	p, ok := ah.getOidcLoginProvider(c)
+	if !ok {
+		return
+	}
+	lp, ok := p.(biz.LinkLoginProvider)
+	if !ok {
+		m := msg.MsgLoginProviderDisabledFailed
+		ah.logger.Warn(m.GetMsg())
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
+	authUrl, binding, err := lp.LinkCodeURL(tracer.ExtractTraceCtxFromGin(c), perm.MustGetTokenContent(c).UserId)
+	if err != nil {
+		m := msg.MsgLoginUnknownFailed.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.LinkCodeURL in authHandler.OidcLink.")
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
+	// 前端需携带凭据(credentials)请求该接口，使浏览器保存binding Cookie
+	ah.setOidcBindingCookie(c, binding)
+
+	ext.WriteSuccessPayload(c, "url", authUrl)
+}
+
+// OidcCallback 处理OIDC身份提供方的回调，配置了前端地址时携带Token跳转至前端
+func (ah *AuthHandler) OidcCallback(c *gin.Context) {
+	p, ok := ah.getOidcLoginProvider(c)
+	if !ok {
+		return
+	}
+
+	// 身份提供方返回的错误
+	if errCode := c.Query("error"); errCode != "" {
+		m := msg.MsgLoginAuthenticationFailed
+		ah.logger.WarnWithFields(logger.Fields{
+			"error":             errCode,
+			"error_description": c.Query("error_description"),
+		}, "Identity provider returned an error in authHandler.OidcCallback.")
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
+	// binding Cookie只能使用一次
+	binding, _ := c.Cookie(oidcBindingCookieName)
+	ah.clearOidcBindingCookie(c)
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+	user, err := p.Callback(ctx, c.Query("code"), c.Query("state"), binding)
+	if err != nil {
+		m := newLoginErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "Login failed in authHandler.OidcCallback.")
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
+	if ah.conf.OidcFrontendUrl == "" {
+		ah.newTokenResponse(c, user, dto.LoginMethodOidc)
+		return
+	}
+
+	// 需要两步验证时将mfa_token交给前端，由前端调用两步验证登录，enroll_required时需先绑定
+	if ah.biz.NeedMfa(user) {
+		ch, err := ah.biz.NewMfaChallenge(ctx, user, newSessionInfo(c, dto.LoginMethodOidc))
+		if err != nil {
+			m := msg.MsgLoginNewTokenFailed.SetError(err)
+			ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.NewMfaChallenge in authHandler.OidcCallback.")
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+		c.Redirect(http.StatusFound, ah.conf.OidcFrontendUrl+"#"+url.Values{
+			"mfa_token":       {ch.MfaToken},
+			"enroll_required": {strconv.FormatBool(ch.EnrollRequired)},
+		}.Encode())
+		return
+	}
+
+	tk := ah.biz.NewToken(ctx, user, newSessionInfo(c, dto.LoginMethodOidc))
+	if tk == "" {
+		m := msg.MsgLoginNewTokenFailed
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.NewToken in authHandler.OidcCallback.")
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+	// Token放在fragment中，避免出现在访问日志及Referer中
+	c.Redirect(http.StatusFound, ah.conf.OidcFrontendUrl+"#"+url.Values{"token": {tk}}.Encode())
+}
+
+// setOidcBindingCookie 将binding保存在浏览器中，回调时校验state由该浏览器发起
+// 回调为身份提供方发起的跨站跳转，需使用SameSite=Lax才能携带Cookie
+func (ah *AuthHandler) setOidcBindingCookie(c *gin.Context, binding string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookieName, binding, 0, oidcBindingCookiePath, "", ah.oidcCookieSecure(), true)
+}
+
+// clearOidcBindingCookie 删除浏览器中保存的binding
+func (ah *AuthHandler) clearOidcBindingCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookieName, "", -1, oidcBindingCookiePath, "", ah.oidcCookieSecure(), true)
+}
+
+// oidcCookieSecure 回调地址为HTTPS时只允许通过HTTPS发送Cookie
+func (ah *AuthHandler) oidcCookieSecure() bool {
+	return strings.HasPrefix(strings.ToLower(ah.conf.OidcRedirectUrl), "https://")
+}
+
+// getOidcLoginProvider 获得OIDC登录方式，未启用时写入错误信息
+func (ah *AuthHandler) getOidcLoginProvider(c *gin.Context) (biz.RedirectLoginProvider, bool) {
+	p, ok := ah.biz.GetLoginProvider(dto.LoginMethodOidc).(biz.RedirectLoginProvider)
+	if !ok {
+		m := msg.MsgLoginProviderDisabledFailed
+		ah.logger.Warn(m.GetMsg())
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return nil, false
+	}
+	return p, true
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"eago/auth/api/form"
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/tracer"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ChangePassword 使用原密码修改密码，无需登录，管理员重置密码后也使用该接口修改
+func (ah *AuthHandler) ChangePassword(c *gin.Context) {
+	frm := new(form.ChangePasswordForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	err := ah.biz.ChangePassword(tracer.ExtractTraceCtxFromGin(c), frm.Username, frm.OldPassword, frm.NewPassword)
+	if err != nil {
+		m := newPasswordErrorMsg(err)
+		logF := m.ToLoggerFields()
+		logF["username"] = frm.Username
+		ah.logger.WarnWithFields(logF, "An error occurred while biz.ChangePassword in authHandler.ChangePassword.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// ResetPassword 管理员重置用户密码，用户下次登录前需修改密码
+func (ah *AuthHandler) ResetPassword(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := new(form.ResetPasswordForm)
+	// 序列化request body
+	if err = c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 验证数据
+	if m := frm.Validate(ctx, ah.dao, userId); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	if err = ah.biz.ResetPassword(ctx, userId, frm.NewPassword); err != nil {
+		m := newPasswordErrorMsg(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.ResetPassword in authHandler.ResetPassword.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// newPasswordErrorMsg 将修改密码的错误转换为对应的消息
+func newPasswordErrorMsg(err error) *cMsg.CodeMsg {
+	switch {
+	case errors.Is(err, dto.ErrPasswordPolicy):
+		return msg.MsgPasswordPolicyFailed.SetError(err)
+	case errors.Is(err, dto.ErrPasswordReused):
+		return msg.MsgPasswordReusedFailed
+	case errors.Is(err, dto.ErrPasswordIncorrect):
+		return msg.MsgPasswordIncorrectFailed
+	case errors.Is(err, dto.ErrLoginUserLocked):
+		return msg.MsgLoginUserLockedFailed
+	case errors.Is(err, dto.ErrLoginDisabledUser):
+		return msg.MsgLoginDisabledUserFailed
+	case errors.Is(err, dto.ErrLoginNoPasswordUser):
+		return msg.MsgLoginNoPasswordUserFailed
+	case errors.Is(err, dto.ErrLoginServiceAccount):
+		return msg.MsgLoginServiceAccountFailed
+	}
+	return msg.MsgPasswordUnknownFailed.SetError(err)
+}
@@ -4,6 +4,7 @@ import (
 	"eago/auth/api/form"
 	"eago/auth/conf/msg"
 	"eago/common/api/ext"
+	"eago/common/audit"
 	cMsg "eago/common/code_msg"
 	"eago/common/global"
 	"eago/common/logger"
@@ -257,6 +258,16 @@ func (ah *AuthHandler) SetProductsOwner(c *gin.Context) {
 		return
 	}
 
+	// 记录修改前的内容用于审计
+	if upms, err := ah.dao.ListUsersProducts(ctx, userId); err == nil {
+		for _, upm := range upms {
+			if upm.Id == prdId {
+				audit.SetBefore(c, map[string]bool{"is_owner": upm.IsOwner})
+				break
+			}
+		}
+	}
+
 	if err = ah.dao.SetProductsOwner(ctx, prdId, userId, frm.IsOwner); err != nil {
 		m := msg.MsgAuthDaoErr.SetError(err)
 		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
@@ -241,3 +241,99 @@ func (ah *AuthHandler) ListRolesUsers(c *gin.Context) {
 
 	ext.WriteSuccessPayload(c, "users", u)
 }
+
+// AddRolePermission 授予角色权限
+func (ah *AuthHandler) AddRolePermission(c *gin.Context) {
+	roleId, err := ext.ParamUint32(c, "role_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "role_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := form.AddRolePermissionForm{}
+	// 序列化request body
+	if err = c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 验证数据
+	if m := frm.Validate(ctx, ah.dao, roleId); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	rp, err := ah.dao.AddRolePermission(ctx, roleId, frm.Permission, frm.ProductId)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "permission", rp)
+}
+
+// RemoveRolePermission 移除角色指定权限
+func (ah *AuthHandler) RemoveRolePermission(c *gin.Context) {
+	roleId, err := ext.ParamUint32(c, "role_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "role_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	permId, err := ext.ParamUint32(c, "permission_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "permission_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ok, err := ah.dao.RemoveRolePermission(tracer.ExtractTraceCtxFromGin(c), roleId, permId)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if !ok {
+		m := cMsg.MsgNotFoundFailed.SetDetail("角色权限不存在")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// ListRolePermissions 列出角色所有权限
+func (ah *AuthHandler) ListRolePermissions(c *gin.Context) {
+	roleId, err := ext.ParamUint32(c, "role_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "role_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	rps, err := ah.dao.ListRolePermissions(tracer.ExtractTraceCtxFromGin(c), roleId)
+	if err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "permissions", rps)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/tracer"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ListUsersSessions 列出用户所有会话
+func (ah *AuthHandler) ListUsersSessions(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	sessions, err := ah.biz.ListUserSessions(tracer.ExtractTraceCtxFromGin(c), userId, c.GetHeader("Token"))
+	if err != nil {
+		m := msg.MsgAuthCacheErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "sessions", sessions)
+}
+
+// RevokeUsersSession 注销用户指定会话
+func (ah *AuthHandler) RevokeUsersSession(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	err = ah.biz.RevokeUserSession(tracer.ExtractTraceCtxFromGin(c), userId, c.Param("session_id"))
+	if errors.Is(err, dto.ErrSessionNotFound) {
+		m := msg.MsgSessionNotFoundFailed
+		logF := m.ToLoggerFields()
+		logF["user_id"] = userId
+		logF["session_id"] = c.Param("session_id")
+		ah.logger.WarnWithFields(logF, m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if err != nil {
+		m := msg.MsgAuthCacheErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// RevokeUsersSessions 注销用户所有会话
+func (ah *AuthHandler) RevokeUsersSessions(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	count, err := ah.biz.RevokeUserSessions(tracer.ExtractTraceCtxFromGin(c), userId)
+	if err != nil {
+		m := msg.MsgAuthCacheErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "revoked", count)
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"eago/auth/api/form"
+	"eago/auth/conf/msg"
+	"eago/auth/dto"
+	"eago/common/api/ext"
+	perm "eago/common/api/permission"
+	cMsg "eago/common/code_msg"
+	"eago/common/logger"
+	"eago/common/tracer"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+)
+
+// TotpLogin 两步验证登录，使用第一步登录返回的mfa_token及验证码或恢复码换取Token
+func (ah *AuthHandler) TotpLogin(c *gin.Context) {
+	frm := new(form.TotpLoginForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+	user, info, err := ah.biz.VerifyMfaChallenge(ctx, frm.MfaToken, frm.Code, frm.RecoveryCode)
+	if err != nil {
+		m := newLoginErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "Login failed in authHandler.TotpLogin.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	tk := ah.biz.NewToken(ctx, user, info)
+	if tk == "" {
+		m := msg.MsgLoginNewTokenFailed
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.NewToken in authHandler.TotpLogin.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ah.logger.DebugWithFields(logger.Fields{
+		"user_id":  user.Id,
+		"username": user.Username,
+	}, "New token with mfa success.")
+	ext.WriteSuccessPayload(c, "token", tk)
+}
+
+// TotpEnrollLogin 被要求启用两步验证但尚未绑定的用户，使用第一步登录返回的mfa_token生成待绑定的密钥
+func (ah *AuthHandler) TotpEnrollLogin(c *gin.Context) {
+	frm := new(form.MfaTokenForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	enrollment, err := ah.biz.EnrollMfaChallenge(tracer.ExtractTraceCtxFromGin(c), frm.MfaToken)
+	if err != nil {
+		m := newMfaEnrollErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "An error occurred while biz.EnrollMfaChallenge in authHandler.TotpEnrollLogin.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "totp", enrollment)
+}
+
+// TotpConfirmLogin 使用验证码确认登录时绑定的两步验证并换取Token，恢复码只在此时返回
+func (ah *AuthHandler) TotpConfirmLogin(c *gin.Context) {
+	frm := new(form.TotpConfirmLoginForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+	user, info, codes, err := ah.biz.ConfirmMfaChallenge(ctx, frm.MfaToken, frm.Code)
+	if err != nil {
+		m := newMfaEnrollErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "Login failed in authHandler.TotpConfirmLogin.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	tk := ah.biz.NewToken(ctx, user, info)
+	if tk == "" {
+		m := msg.MsgLoginNewTokenFailed
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while biz.NewToken in authHandler.TotpConfirmLogin.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ah.logger.DebugWithFields(logger.Fields{
+		"user_id":  user.Id,
+		"username": user.Username,
+	}, "New token with mfa enrollment success.")
+	ext.WriteSuccessPayload(c, "login", &dto.MfaEnrollLogin{Token: tk, RecoveryCodes: codes})
+}
+
+// EnrollTotp 生成两步验证密钥，返回的uri可生成二维码供身份验证器扫描
+func (ah *AuthHandler) EnrollTotp(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	enrollment, err := ah.biz.EnrollTotp(tracer.ExtractTraceCtxFromGin(c), userId)
+	if err != nil {
+		m := newTotpErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "An error occurred while biz.EnrollTotp in authHandler.EnrollTotp.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "totp", enrollment)
+}
+
+// ConfirmTotp 使用验证码确认并启用两步验证，恢复码只在此时返回
+func (ah *AuthHandler) ConfirmTotp(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := new(form.ConfirmTotpForm)
+	// 序列化request body
+	if err = c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	codes, err := ah.biz.ConfirmTotp(tracer.ExtractTraceCtxFromGin(c), userId, frm.Code)
+	if err != nil {
+		m := newTotpErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "An error occurred while biz.ConfirmTotp in authHandler.ConfirmTotp.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "recovery_codes", codes)
+}
+
+// DisableTotp 停用两步验证
+func (ah *AuthHandler) DisableTotp(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm, ok := ah.bindTotpProofForm(c, userId)
+	if !ok {
+		return
+	}
+
+	if err = ah.biz.DisableTotp(tracer.ExtractTraceCtxFromGin(c), userId, frm.Proof()); err != nil {
+		m := newTotpErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "An error occurred while biz.DisableTotp in authHandler.DisableTotp.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有恢复码全部失效
+func (ah *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm, ok := ah.bindTotpProofForm(c, userId)
+	if !ok {
+		return
+	}
+
+	codes, err := ah.biz.RegenerateRecoveryCodes(tracer.ExtractTraceCtxFromGin(c), userId, frm.Proof())
+	if err != nil {
+		m := newTotpErrorMsg(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), "An error occurred while biz.RegenerateRecoveryCodes in authHandler.RegenerateRecoveryCodes.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "recovery_codes", codes)
+}
+
+// SetTotpRequired 设置用户是否必须启用两步验证
+func (ah *AuthHandler) SetTotpRequired(c *gin.Context) {
+	userId, err := ext.ParamUint32(c, "user_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "user_id")
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := new(form.SetTotpRequiredForm)
+	// 序列化request body
+	if err = c.ShouldBindJSON(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	if err = ah.dao.SetUserTotpRequired(tracer.ExtractTraceCtxFromGin(c), userId, *frm.Required); err != nil {
+		m := msg.MsgAuthDaoErr.SetError(err)
+		ah.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
+
+// bindTotpProofForm 序列化并验证当前验证码或恢复码，用户操作自己的两步验证设置时必须提供
+// 管理员操作其他用户时不需要，请求可以没有body，验证失败时已写入响应
+func (ah *AuthHandler) bindTotpProofForm(c *gin.Context, userId uint32) (*form.TotpProofForm, bool) {
+	frm := new(form.TotpProofForm)
+	// 序列化request body
+	if err := c.ShouldBindJSON(&frm); err != nil && !errors.Is(err, io.EOF) {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return nil, false
+	}
+	// 验证数据
+	if m := frm.Validate(perm.MustGetTokenContent(c).UserId == userId); m != nil {
+		// 数据验证未通过
+		ah.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return nil, false
+	}
+
+	return frm, true
+}
+
+// newTotpErrorMsg 将两步验证的错误转换为对应的消息
+func newTotpErrorMsg(err error) *cMsg.CodeMsg {
+	switch {
+	case errors.Is(err, dto.ErrTotpAlreadyEnabled):
+		return msg.MsgTotpAlreadyEnabledFailed
+	case errors.Is(err, dto.ErrTotpNotEnrolled):
+		return msg.MsgTotpNotEnrolledFailed
+	case errors.Is(err, dto.ErrTotpCodeInvalid):
+		return msg.MsgTotpCodeInvalidFailed
+	case errors.Is(err, dto.ErrTotpRequired):
+		return msg.MsgTotpRequiredFailed
+	case errors.Is(err, dto.ErrLoginUserLocked):
+		return msg.MsgTotpUserLockedFailed
+	}
+	return msg.MsgTotpUnknownFailed.SetError(err)
+}
+
+// newMfaEnrollErrorMsg 将登录时绑定两步验证的错误转换为对应的消息
+func newMfaEnrollErrorMsg(err error) *cMsg.CodeMsg {
+	switch {
+	case errors.Is(err, dto.ErrTotpAlreadyEnabled),
+		errors.Is(err, dto.ErrTotpNotEnrolled),
+		errors.Is(err, dto.ErrTotpCodeInvalid):
+		return newTotpErrorMsg(err)
+	}
+	return newLoginErrorMsg(err)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"eago/auth/conf"
 	"eago/auth/dao"
+	"eago/common/audit"
 	"eago/common/logger"
 	"eago/common/orm"
 	"eago/common/redis"
@@ -17,15 +18,16 @@ import (
 var (
 	auth service.EagoSrv
 
-	authDao   *dao.Dao
-	authRedis *redis.RedisTool
+	authDao     *dao.Dao
+	authAuditor audit.Auditor
+	authRedis   *redis.RedisTool
 
 	authConf *conf.Conf
 	authLg   *logger.Logger
 )
 
 func main() {
-	auth = NewAuthApi(authDao, authRedis, authConf, authLg)
+	auth = NewAuthApi(authDao, authAuditor, authRedis, authConf, authLg)
 
 	e := make(chan error)
 	go func() {
@@ -84,7 +86,7 @@ func init() {
 	}
 	authLg = lg
 
-	authDao = dao.NewDao(orm.NewMysqlGorm(
+	db := orm.NewMysqlGorm(
 		authConf.MysqlAddress,
 		authConf.MysqlUser,
 		authConf.MysqlPassword,
@@ -92,7 +94,11 @@ func init() {
 		orm.MysqlMaxIdleConns(authConf.MysqlMaxIdleConns),
 		orm.MysqlMaxOpenConns(authConf.MysqlMaxOpenConns),
 		orm.UsingOpentracingPlugin(),
-	), authLg)
+		orm.UsingDbStatsMetrics("mysql"),
+	)
+	authDao = dao.NewDao(db, authLg)
+	// 审计日志与业务数据使用同一数据库
+	authAuditor = audit.NewAuditor(db, authConf.Const.ServiceName, audit.Logger(authLg))
 
 	authRedis = redis.NewRedisTool(
 		authConf.RedisAddress,
@@ -100,5 +106,6 @@ func init() {
 		authConf.Const.ServiceName,
 		authConf.RedisDb,
 		redis.UsingOpentracingHook(),
+		redis.UsingPoolStatsMetrics("redis"),
 	)
 }
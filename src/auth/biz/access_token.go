@@ -0,0 +1,208 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// accessTokenPrefix 访问令牌的前缀，用于与登录Token区分
+	accessTokenPrefix = "eagopat_"
+	// accessTokenDisplayLen 展示时保留的令牌长度
+	accessTokenDisplayLen = len(accessTokenPrefix) + 4
+	// accessTokenCacheTtl 访问令牌TokenContent的缓存时间，同时决定最近使用时间的更新频率
+	accessTokenCacheTtl = time.Minute
+)
+
+// NewAccessToken 生成访问令牌，令牌明文只在创建时返回
+func (b *Biz) NewAccessToken(
+	ctx context.Context, userId uint32, name string, scopes []string, expiresAt *time.Time, createdBy string,
+) (string, *model.AccessToken, error) {
+	b.logger.InfoWithFields(logger.Fields{
+		"user_id": userId,
+		"name":    name,
+	}, "biz.NewAccessToken called.")
+	defer b.logger.Info("biz.NewAccessToken end.")
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := accessTokenPrefix + hex.EncodeToString(buf)
+
+	at, err := b.dao.NewAccessToken(
+		ctx, userId, name, utils.GenSha256HashCode(token), token[:accessTokenDisplayLen], scopes, expiresAt, createdBy,
+	)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.NewAccessToken in biz.NewAccessToken.")
+		return "", nil, err
+	}
+	return token, at, nil
+}
+
+// RevokeAccessToken 删除用户的指定访问令牌并清除其缓存
+func (b *Biz) RevokeAccessToken(ctx context.Context, userId, id uint32) error {
+	at, err := b.dao.GetAccessToken(ctx, orm.Query{"id=?": id, "user_id=?": userId})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"id":      id,
+			"error":   err,
+		}, "An error occurred while dao.GetAccessToken in biz.RevokeAccessToken.")
+		return err
+	}
+	if at == nil || at.Id == 0 {
+		return dto.ErrAccessTokenNotFound
+	}
+
+	if _, err = b.dao.RemoveAccessToken(ctx, userId, id); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"id":      id,
+			"error":   err,
+		}, "An error occurred while dao.RemoveAccessToken in biz.RevokeAccessToken.")
+		return err
+	}
+	b.clearAccessTokenCache(ctx, at.TokenHash)
+	return nil
+}
+
+// getAccessTokenContent 通过访问令牌获得TokenContent，令牌无效、过期或用户禁用时返回nil
+func (b *Biz) getAccessTokenContent(ctx context.Context, token string) (*dto.TokenContent, error) {
+	tokenHash := utils.GenSha256HashCode(token)
+	cacheKey := genAccessTokenKey(tokenHash)
+
+	tc := new(dto.TokenContent)
+	if b.redis.Exist(ctx, cacheKey) {
+		if tcStr, err := b.redis.Get(ctx, cacheKey); err == nil && json.Unmarshal([]byte(tcStr), tc) == nil {
+			return tc, nil
+		}
+	}
+
+	at, err := b.dao.GetAccessToken(ctx, orm.Query{"token_hash=?": tokenHash})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.GetAccessToken in biz.getAccessTokenContent.")
+		return nil, err
+	}
+	if at == nil || at.Id == 0 {
+		return nil, nil
+	}
+	// 已过期
+	ttl := accessTokenCacheTtl
+	if at.ExpiresAt != nil {
+		left := time.Until(at.ExpiresAt.Time)
+		if left <= 0 {
+			return nil, nil
+		}
+		if left < ttl {
+			ttl = left
+		}
+	}
+
+	u, err := b.dao.GetUser(ctx, orm.Query{"id=?": at.UserId})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": at.UserId,
+			"error":   err,
+		}, "An error occurred while dao.GetUser in biz.getAccessTokenContent.")
+		return nil, err
+	}
+	if u == nil || u.Id == 0 || u.Disabled {
+		return nil, nil
+	}
+
+	tc = b.genTokenContent(ctx, u)
+	tc.Scopes = at.Scopes
+
+	if err = b.dao.SetAccessTokenLastUsed(ctx, at.Id); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"id":    at.Id,
+			"error": err,
+		}, "An error occurred while dao.SetAccessTokenLastUsed in biz.getAccessTokenContent, but skipped.")
+	}
+
+	content, _ := json.Marshal(tc)
+	if err = b.redis.Set(ctx, cacheKey, string(content), ttl); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"id":    at.Id,
+			"error": err,
+		}, "An error occurred while redis.Set in biz.getAccessTokenContent, but skipped.")
+		return tc, nil
+	}
+	// 记录用户已缓存的访问令牌，用户被删除或禁用后其令牌记录可能已不存在，需按索引清除缓存
+	indexKey := genUserAccessTokensKey(at.UserId)
+	if err = b.redis.SAdd(ctx, indexKey, tokenHash); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"id":    at.Id,
+			"error": err,
+		}, "An error occurred while redis.SAdd in biz.getAccessTokenContent, but skipped.")
+	}
+	if err = b.redis.Expire(ctx, indexKey, accessTokenCacheTtl); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"id":    at.Id,
+			"error": err,
+		}, "An error occurred while redis.Expire in biz.getAccessTokenContent, but skipped.")
+	}
+	return tc, nil
+}
+
+// clearUserAccessTokensCache 清除用户所有访问令牌的缓存，用户权限变更、禁用或删除时调用
+func (b *Biz) clearUserAccessTokensCache(ctx context.Context, userId uint32) {
+	indexKey := genUserAccessTokensKey(userId)
+	tokenHashes, err := b.redis.SMembers(ctx, indexKey)
+	if err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.clearUserAccessTokensCache, but skipped.")
+		return
+	}
+	for _, tokenHash := range tokenHashes {
+		b.clearAccessTokenCache(ctx, tokenHash)
+	}
+	if err = b.redis.Del(ctx, indexKey); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.Del in biz.clearUserAccessTokensCache, but skipped.")
+	}
+}
+
+// clearAccessTokenCache 清除访问令牌的缓存
+func (b *Biz) clearAccessTokenCache(ctx context.Context, tokenHash string) {
+	if err := b.redis.Del(ctx, genAccessTokenKey(tokenHash)); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while redis.Del in biz.clearAccessTokenCache, but skipped.")
+	}
+}
+
+// isAccessToken 是否是访问令牌
+func isAccessToken(token string) bool {
+	return strings.HasPrefix(token, accessTokenPrefix)
+}
+
+// genAccessTokenKey 生成访问令牌缓存的Key
+func genAccessTokenKey(tokenHash string) string {
+	return "access_token/" + tokenHash
+}
+
+// genUserAccessTokensKey 生成用户访问令牌缓存索引的Key，值为该用户已缓存令牌的sha256集合
+func genUserAccessTokensKey(userId uint32) string {
+	return fmt.Sprintf("user_access_tokens/%d", userId)
+}
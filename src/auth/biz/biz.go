@@ -6,6 +6,7 @@ import (
 	"eago/common/broker"
 	"eago/common/logger"
 	"eago/common/redis"
+	"sync"
 )
 
 type Biz struct {
@@ -13,12 +14,19 @@ type Biz struct {
 	redis *redis.RedisTool
 	pub   broker.Publisher
 
+	// loginProviders 启用的登录方式，按配置顺序排列
+	loginProviders []LoginProvider
+
+	// dummyPasswordHash 用户不存在或没有密码时用于比较的哈希，使各种失败情况耗时相近
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+
 	conf   *conf.Conf
 	logger *logger.Logger
 }
 
 func NewBiz(dao *dao.Dao, redis *redis.RedisTool, pub broker.Publisher, conf *conf.Conf, logger *logger.Logger) *Biz {
-	return &Biz{
+	b := &Biz{
 		dao:   dao,
 		redis: redis,
 		pub:   pub,
@@ -26,4 +34,11 @@ func NewBiz(dao *dao.Dao, redis *redis.RedisTool, pub broker.Publisher, conf *co
 		conf:   conf,
 		logger: logger,
 	}
+
+	// 用户权限相关数据变更后立即刷新其所有Token内容
+	if redis != nil {
+		dao.SetUsersChangedHook(b.RefreshUsersTokens)
+	}
+
+	return b
 }
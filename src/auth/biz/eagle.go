@@ -34,7 +34,7 @@ func (b *Biz) GetUserObjectFromEagleToken(ctx context.Context, tkStr string) (*m
 	}
 
 	// 查询该用户在本地数据库中的数据
-	userObj, err := b.dao.GetUser(ctx, orm.Query{"username=?": tkObj.Username, "disabled=?": 0})
+	userObj, err := b.dao.GetUser(ctx, orm.Query{"username=?": tkObj.Username, "disabled=?": 0, "is_service_account=?": 0})
 	if err != nil {
 		// 调用数据库出错
 		b.logger.ErrorWithFields(logger.Fields{
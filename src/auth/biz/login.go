@@ -0,0 +1,138 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"strings"
+)
+
+// LoginProvider 登录方式
+type LoginProvider interface {
+	// Name 登录方式名称，与配置及会话中记录的登录方式一致
+	Name() string
+}
+
+// PasswordLoginProvider 用户名密码登录方式，无法认证时返回dto.ErrLoginNotApplicable交由下一个登录方式处理
+type PasswordLoginProvider interface {
+	LoginProvider
+	Login(ctx context.Context, username, password string) (*model.User, error)
+}
+
+// CredentialLoginProvider 使用外部凭证登录的方式，如Eagle Token
+type CredentialLoginProvider interface {
+	LoginProvider
+	LoginWithCredential(ctx context.Context, credential string) (*model.User, error)
+}
+
+// RedirectLoginProvider 跳转至外部身份提供方登录的方式，如OIDC
+type RedirectLoginProvider interface {
+	LoginProvider
+	// AuthCodeURL 生成跳转至身份提供方的地址，binding需保存在发起登录的浏览器中，回调时原样传回
+	AuthCodeURL(ctx context.Context) (authUrl, binding string, err error)
+	// Callback 处理身份提供方的回调，binding与发起登录时返回的不一致时拒绝，防止登录CSRF
+	Callback(ctx context.Context, code, state, binding string) (*model.User, error)
+}
+
+// LinkLoginProvider 可将外部身份关联至已登录用户的跳转登录方式
+type LinkLoginProvider interface {
+	RedirectLoginProvider
+	// LinkCodeURL 生成跳转至身份提供方的地址，回调时将外部身份关联至userId，binding同AuthCodeURL
+	LinkCodeURL(ctx context.Context, userId uint32) (authUrl, binding string, err error)
+}
+
+// InitLoginProviders 按配置初始化登录方式
+func (b *Biz) InitLoginProviders() {
+	b.loginProviders = make([]LoginProvider, 0, len(b.conf.LoginProviders))
+	for _, name := range b.conf.LoginProviders {
+		var p LoginProvider
+		switch name {
+		case dto.LoginMethodCrowd:
+			p = newCrowdLoginProvider(b)
+		case dto.LoginMethodDatabase:
+			p = newDatabaseLoginProvider(b)
+		case dto.LoginMethodEagle:
+			p = newEagleLoginProvider(b)
+		case dto.LoginMethodOidc:
+			p = newOidcLoginProvider(b)
+		default:
+			b.logger.WarnWithFields(logger.Fields{
+				"provider": name,
+			}, "Unknown login provider in config, skipped it.")
+			continue
+		}
+		b.loginProviders = append(b.loginProviders, p)
+	}
+}
+
+// PasswordLoginProviders 获得所有用户名密码登录方式
+func (b *Biz) PasswordLoginProviders() []PasswordLoginProvider {
+	ps := make([]PasswordLoginProvider, 0)
+	for _, p := range b.loginProviders {
+		if pp, ok := p.(PasswordLoginProvider); ok {
+			ps = append(ps, pp)
+		}
+	}
+	return ps
+}
+
+// GetLoginProvider 获得指定名称的登录方式，未启用时返回nil
+func (b *Biz) GetLoginProvider(name string) LoginProvider {
+	for _, p := range b.loginProviders {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// loginOrCreateUser 外部认证通过后登录本地用户，用户不存在时创建，存在时更新最近登录时间
+func (b *Biz) loginOrCreateUser(ctx context.Context, username, email string) (*model.User, error) {
+	username = strings.ToLower(username)
+
+	user, err := b.dao.GetUser(ctx, orm.Query{"username=?": username})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.GetUser in biz.loginOrCreateUser.")
+		return nil, err
+	}
+
+	// 在本地数据库中没查找到用户则创建一个用户
+	if user == nil || user.Id == 0 {
+		user, err = b.dao.NewUser(ctx, username, email, true)
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"username": username,
+				"error":    err,
+			}, "An error occurred while dao.NewUser in biz.loginOrCreateUser.")
+			return nil, err
+		}
+		return user, nil
+	}
+
+	return b.loginUser(ctx, user)
+}
+
+// loginUser 外部认证通过后登录已存在的本地用户，更新最近登录时间
+func (b *Biz) loginUser(ctx context.Context, user *model.User) (*model.User, error) {
+	// 阻止登录，禁用的用户
+	if user.Disabled {
+		return nil, dto.ErrLoginDisabledUser
+	}
+	// 阻止登录，服务账号
+	if user.IsServiceAccount {
+		return nil, dto.ErrLoginServiceAccount
+	}
+
+	if err := b.dao.SetUserLastLogin(ctx, user.Id); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"username": user.Username,
+			"error":    err,
+		}, "An error occurred while dao.SetUserLastLogin in biz.loginUser, but skipped.")
+	}
+	return user, nil
+}
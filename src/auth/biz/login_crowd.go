@@ -0,0 +1,65 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"github.com/jda/go-crowd"
+)
+
+// crowdLoginProvider Crowd登录
+type crowdLoginProvider struct {
+	b   *Biz
+	cli crowd.Crowd
+}
+
+func newCrowdLoginProvider(b *Biz) *crowdLoginProvider {
+	cli, err := crowd.New(b.conf.CrowdAppName, b.conf.CrowdAppPassword, b.conf.CrowdAddress)
+	if err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"crowd_address":  b.conf.CrowdAddress,
+			"crowd_app_name": b.conf.CrowdAppName,
+		}, "An error occurred while crowd.New in biz.newCrowdLoginProvider, skipped it.")
+	}
+
+	return &crowdLoginProvider{b: b, cli: cli}
+}
+
+// Name 登录方式名称
+func (p *crowdLoginProvider) Name() string {
+	return dto.LoginMethodCrowd
+}
+
+// Login 通过Crowd认证，认证通过后以Crowd中的邮箱作为用户名登录
+func (p *crowdLoginProvider) Login(ctx context.Context, username, password string) (*model.User, error) {
+	crowdUser, err := p.cli.Authenticate(username, password)
+	if err == nil {
+		// 阻止登录，非启用的用户
+		if !crowdUser.Active {
+			return nil, dto.ErrLoginInactiveCrowdUser
+		}
+
+		p.b.logger.DebugWithFields(logger.Fields{
+			"username": username,
+		}, "Crowd login success.")
+		return p.b.loginOrCreateUser(ctx, crowdUser.Email, crowdUser.Email)
+	}
+
+	// TODO: 下方switch代码为临时解决方案，后续维护crowd模块解决
+	// crowd错误类型完整介绍见：https://developer.atlassian.com/server/crowd/using-the-crowd-rest-apis/
+	switch err.Error() {
+	case "INVALID_USER_AUTHENTICATION":
+		// 认证失败：返回密码错误
+		return nil, dto.ErrLoginAuthenticationFailed
+	case "INACTIVE_ACCOUNT":
+		// 用户处于被禁用状态：返回当前用户在Crowd中是禁用状态
+		return nil, dto.ErrLoginInactiveCrowdUser
+	}
+
+	p.b.logger.WarnWithFields(logger.Fields{
+		"username": username,
+		"error":    err,
+	}, "Crowd login failed.")
+	return nil, dto.ErrLoginNotApplicable
+}
@@ -0,0 +1,73 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"errors"
+)
+
+// databaseLoginProvider 本地数据库登录
+type databaseLoginProvider struct {
+	b *Biz
+}
+
+func newDatabaseLoginProvider(b *Biz) *databaseLoginProvider {
+	return &databaseLoginProvider{b: b}
+}
+
+// Name 登录方式名称
+func (p *databaseLoginProvider) Name() string {
+	return dto.LoginMethodDatabase
+}
+
+// Login 使用本地数据库中的密码认证
+func (p *databaseLoginProvider) Login(ctx context.Context, username, password string) (*model.User, error) {
+	// 查询该用户在本地数据库中的数据
+	user, err := p.b.dao.GetUser(ctx, orm.Query{"username=?": username})
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.GetUser in biz.databaseLoginProvider.Login.")
+		return nil, err
+	}
+	// 判断用户是否在DB中存在
+	if user == nil || user.Id == 0 {
+		return nil, dto.ErrLoginAuthenticationFailed
+	}
+	// 判断是否是被禁用的用户
+	if user.Disabled {
+		return nil, dto.ErrLoginDisabledUser
+	}
+	// 判断是否是密码为空的用户
+	if len(user.Password) < 1 {
+		return nil, dto.ErrLoginNoPasswordUser
+	}
+
+	if username != user.Username {
+		return nil, dto.ErrLoginNotApplicable
+	}
+
+	// 判断账号密码是否匹配，不匹配时交由下一个登录方式处理
+	if err = p.b.authenticatePassword(ctx, user, password); err != nil {
+		if errors.Is(err, dto.ErrPasswordIncorrect) {
+			return nil, dto.ErrLoginNotApplicable
+		}
+		return nil, err
+	}
+	// 管理员重置密码后需先修改密码
+	if user.MustChangePassword {
+		return nil, dto.ErrLoginPasswordMustChange
+	}
+
+	if err = p.b.dao.SetUserLastLogin(ctx, user.Id); err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.SetUserLastLogin in biz.databaseLoginProvider.Login, but skipped.")
+	}
+	return user, nil
+}
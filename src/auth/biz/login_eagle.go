@@ -0,0 +1,26 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+)
+
+// eagleLoginProvider 使用Eagle Token登录
+type eagleLoginProvider struct {
+	b *Biz
+}
+
+func newEagleLoginProvider(b *Biz) *eagleLoginProvider {
+	return &eagleLoginProvider{b: b}
+}
+
+// Name 登录方式名称
+func (p *eagleLoginProvider) Name() string {
+	return dto.LoginMethodEagle
+}
+
+// LoginWithCredential 根据Eagle Token获得本地用户
+func (p *eagleLoginProvider) LoginWithCredential(ctx context.Context, credential string) (*model.User, error) {
+	return p.b.GetUserObjectFromEagleToken(ctx, credential)
+}
@@ -0,0 +1,386 @@
+package biz
+
+import (
+	"context"
+	"crypto/subtle"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/oidc"
+	"eago/common/orm"
+	"eago/common/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// oidcStateTtl 跳转登录state的有效期
+const oidcStateTtl = 10 * time.Minute
+
+// oidcState 跳转登录时保存的state内容，LinkUserId不为0时回调将外部身份关联至该用户
+// BindingHash 为保存在发起登录的浏览器中的binding的哈希，将state绑定至该浏览器
+type oidcState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	BindingHash  string `json:"binding_hash"`
+	LinkUserId   uint32 `json:"link_user_id,omitempty"`
+}
+
+// oidcLoginProvider OpenID Connect登录，使用授权码模式及PKCE
+type oidcLoginProvider struct {
+	b   *Biz
+	cli oidc.Oidc
+}
+
+func newOidcLoginProvider(b *Biz) *oidcLoginProvider {
+	return &oidcLoginProvider{
+		b: b,
+		cli: oidc.NewOidc(
+			b.conf.OidcIssuer, b.conf.OidcClientId, b.conf.OidcClientSecret, b.conf.OidcRedirectUrl, b.conf.OidcScopes,
+			oidc.Logger(b.logger),
+		),
+	}
+}
+
+// Name 登录方式名称
+func (p *oidcLoginProvider) Name() string {
+	return dto.LoginMethodOidc
+}
+
+// AuthCodeURL 生成state、nonce及code_verifier，返回跳转至身份提供方的地址及浏览器需保存的binding
+func (p *oidcLoginProvider) AuthCodeURL(ctx context.Context) (string, string, error) {
+	return p.authCodeURL(ctx, 0)
+}
+
+// LinkCodeURL 已登录用户关联外部身份，返回跳转至身份提供方的地址及浏览器需保存的binding
+func (p *oidcLoginProvider) LinkCodeURL(ctx context.Context, userId uint32) (string, string, error) {
+	return p.authCodeURL(ctx, userId)
+}
+
+// Callback 校验state，使用授权码换取并校验ID Token，按签发方及唯一标识登录关联的本地用户
+// 外部身份未关联时创建本地用户并关联，已存在同名本地用户时需该用户登录后通过LinkCodeURL主动关联，避免冒用同名账号
+func (p *oidcLoginProvider) Callback(ctx context.Context, code, state, binding string) (*model.User, error) {
+	st, err := p.popState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	// state必须由当前浏览器发起，避免被诱导登录攻击者的账号或将身份关联至攻击者的用户
+	if binding == "" ||
+		subtle.ConstantTimeCompare([]byte(utils.GenSha256HashCode(binding)), []byte(st.BindingHash)) != 1 {
+		return nil, dto.ErrLoginStateInvalid
+	}
+
+	tk, err := p.cli.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while oidc.Exchange in biz.oidcLoginProvider.Callback.")
+		return nil, fmt.Errorf("%w: %s", dto.ErrLoginAuthenticationFailed, err)
+	}
+	claims, err := p.cli.VerifyIdToken(ctx, tk.IdToken, st.Nonce)
+	if err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while oidc.VerifyIdToken in biz.oidcLoginProvider.Callback.")
+		return nil, fmt.Errorf("%w: %s", dto.ErrLoginAuthenticationFailed, err)
+	}
+
+	issuer, subject := claims.String("iss"), claims.String("sub")
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing claim sub", dto.ErrLoginAuthenticationFailed)
+	}
+	// 只使用身份提供方验证过的邮箱
+	email := ""
+	if claims.Bool("email_verified") {
+		email = strings.ToLower(claims.String(p.b.conf.OidcEmailClaim))
+	}
+
+	var user *model.User
+	if st.LinkUserId != 0 {
+		user, err = p.linkUser(ctx, st.LinkUserId, issuer, subject)
+	} else {
+		user, err = p.loginUser(ctx, claims, issuer, subject, email)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 以身份提供方验证过的邮箱为准
+	if email != "" && user.Email != email {
+		if _, err = p.b.dao.SetUser(ctx, user.Id, email, user.Phone); err != nil {
+			p.b.logger.WarnWithFields(logger.Fields{
+				"user_id": user.Id,
+				"error":   err,
+			}, "An error occurred while dao.SetUser in biz.oidcLoginProvider.Callback, but skipped.")
+		} else {
+			user.Email = email
+		}
+	}
+
+	p.syncRoles(ctx, user, claims.Strings(p.b.conf.OidcRolesClaim))
+	p.syncGroups(ctx, user, claims.Strings(p.b.conf.OidcGroupsClaim))
+	return user, nil
+}
+
+// authCodeURL 保存state后返回跳转至身份提供方的地址及binding，linkUserId不为0时回调将外部身份关联至该用户
+func (p *oidcLoginProvider) authCodeURL(ctx context.Context, linkUserId uint32) (string, string, error) {
+	state, err := oidc.GenRandomString()
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := oidc.GenRandomString()
+	if err != nil {
+		return "", "", err
+	}
+	st := &oidcState{LinkUserId: linkUserId, BindingHash: utils.GenSha256HashCode(binding)}
+	if st.Nonce, err = oidc.GenRandomString(); err != nil {
+		return "", "", err
+	}
+	if st.CodeVerifier, err = oidc.GenRandomString(); err != nil {
+		return "", "", err
+	}
+
+	content, _ := json.Marshal(st)
+	if err = p.b.redis.Set(ctx, genOidcStateKey(state), string(content), oidcStateTtl); err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while redis.Set in biz.oidcLoginProvider.authCodeURL.")
+		return "", "", err
+	}
+
+	authUrl, err := p.cli.AuthCodeURL(ctx, state, st.Nonce, st.CodeVerifier)
+	if err != nil {
+		return "", "", err
+	}
+	return authUrl, binding, nil
+}
+
+// loginUser 登录外部身份关联的本地用户，未关联时创建本地用户并关联
+func (p *oidcLoginProvider) loginUser(
+	ctx context.Context, claims oidc.Claims, issuer, subject, email string,
+) (*model.User, error) {
+	identity, err := p.b.dao.GetUserIdentity(ctx, issuer, subject)
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"issuer":  issuer,
+			"subject": subject,
+			"error":   err,
+		}, "An error occurred while dao.GetUserIdentity in biz.oidcLoginProvider.loginUser.")
+		return nil, err
+	}
+	if identity != nil && identity.Id != 0 {
+		user, err := p.b.dao.GetUser(ctx, orm.Query{"id=?": identity.UserId})
+		if err != nil {
+			p.b.logger.ErrorWithFields(logger.Fields{
+				"user_id": identity.UserId,
+				"error":   err,
+			}, "An error occurred while dao.GetUser in biz.oidcLoginProvider.loginUser.")
+			return nil, err
+		}
+		if user == nil || user.Id == 0 {
+			return nil, dto.ErrLoginAuthenticationFailed
+		}
+		return p.b.loginUser(ctx, user)
+	}
+
+	username := strings.ToLower(claims.String(p.b.conf.OidcUsernameClaim))
+	if username == "" {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"claim": p.b.conf.OidcUsernameClaim,
+			"sub":   subject,
+		}, "Username claim not found in id token.")
+		return nil, fmt.Errorf("%w: missing claim %s", dto.ErrLoginAuthenticationFailed, p.b.conf.OidcUsernameClaim)
+	}
+
+	// 同名的本地用户可能属于其他人，不自动关联
+	exist, err := p.b.dao.IsUserExist(ctx, orm.Query{"username=?": username})
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.IsUserExist in biz.oidcLoginProvider.loginUser.")
+		return nil, err
+	}
+	if exist {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"username": username,
+			"issuer":   issuer,
+			"subject":  subject,
+		}, "Local user with the same username exists, identity must be linked explicitly.")
+		return nil, dto.ErrLoginIdentityNotLinked
+	}
+
+	user, err := p.b.dao.NewUserWithIdentity(ctx, username, email, p.Name(), issuer, subject)
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.NewUserWithIdentity in biz.oidcLoginProvider.loginUser.")
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkUser 将外部身份关联至发起关联的用户，外部身份已关联其他用户时拒绝
+func (p *oidcLoginProvider) linkUser(ctx context.Context, userId uint32, issuer, subject string) (*model.User, error) {
+	user, err := p.b.dao.GetUser(ctx, orm.Query{"id=?": userId})
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.GetUser in biz.oidcLoginProvider.linkUser.")
+		return nil, err
+	}
+	if user == nil || user.Id == 0 {
+		return nil, dto.ErrLoginStateInvalid
+	}
+
+	identity, err := p.b.dao.GetUserIdentity(ctx, issuer, subject)
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"issuer":  issuer,
+			"subject": subject,
+			"error":   err,
+		}, "An error occurred while dao.GetUserIdentity in biz.oidcLoginProvider.linkUser.")
+		return nil, err
+	}
+	if identity != nil && identity.Id != 0 {
+		if identity.UserId != userId {
+			return nil, dto.ErrLoginIdentityLinked
+		}
+		return p.b.loginUser(ctx, user)
+	}
+
+	if err = p.b.dao.NewUserIdentity(ctx, userId, p.Name(), issuer, subject); err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"issuer":  issuer,
+			"subject": subject,
+			"error":   err,
+		}, "An error occurred while dao.NewUserIdentity in biz.oidcLoginProvider.linkUser.")
+		return nil, err
+	}
+	p.b.logger.InfoWithFields(logger.Fields{
+		"user_id": userId,
+		"issuer":  issuer,
+		"subject": subject,
+	}, "Oidc identity linked to user.")
+	return p.b.loginUser(ctx, user)
+}
+
+// popState 获得并删除state，每个state只能使用一次
+func (p *oidcLoginProvider) popState(ctx context.Context, state string) (*oidcState, error) {
+	key := genOidcStateKey(state)
+	if state == "" || !p.b.redis.Exist(ctx, key) {
+		return nil, dto.ErrLoginStateInvalid
+	}
+
+	content, err := p.b.redis.Get(ctx, key)
+	if err != nil {
+		p.b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while redis.Get in biz.oidcLoginProvider.popState.")
+		return nil, err
+	}
+	if err = p.b.redis.Del(ctx, key); err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while redis.Del in biz.oidcLoginProvider.popState, but skipped.")
+	}
+
+	st := new(oidcState)
+	if err = json.Unmarshal([]byte(content), st); err != nil {
+		return nil, dto.ErrLoginStateInvalid
+	}
+	return st, nil
+}
+
+// syncRoles 将用户加入声明中同名的角色，只增加不移除，本地不存在的角色忽略
+func (p *oidcLoginProvider) syncRoles(ctx context.Context, user *model.User, names []string) {
+	if p.b.conf.OidcRolesClaim == "" || len(names) == 0 {
+		return
+	}
+
+	roles, err := p.b.dao.ListUsersRoles(ctx, user.Id)
+	if err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUsersRoles in biz.oidcLoginProvider.syncRoles, skipped it.")
+		return
+	}
+	joined := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		joined[r.Name] = true
+	}
+
+	for _, name := range names {
+		if joined[name] {
+			continue
+		}
+		role, err := p.b.dao.GetRole(ctx, orm.Query{"name=?": name})
+		if err != nil || role == nil || role.Id == 0 {
+			continue
+		}
+		if err = p.b.dao.AddUser2Role(ctx, role.Id, user.Id); err != nil {
+			p.b.logger.WarnWithFields(logger.Fields{
+				"user_id": user.Id,
+				"role":    name,
+				"error":   err,
+			}, "An error occurred while dao.AddUser2Role in biz.oidcLoginProvider.syncRoles, skipped it.")
+			continue
+		}
+		p.b.logger.InfoWithFields(logger.Fields{
+			"user_id": user.Id,
+			"role":    name,
+		}, "User added to role by oidc claim.")
+	}
+}
+
+// syncGroups 将用户加入声明中同名的组，只增加不移除，本地不存在的组忽略
+func (p *oidcLoginProvider) syncGroups(ctx context.Context, user *model.User, names []string) {
+	if p.b.conf.OidcGroupsClaim == "" || len(names) == 0 {
+		return
+	}
+
+	groups, err := p.b.dao.ListUsersGroups(ctx, user.Id)
+	if err != nil {
+		p.b.logger.WarnWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUsersGroups in biz.oidcLoginProvider.syncGroups, skipped it.")
+		return
+	}
+	joined := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		joined[g.Name] = true
+	}
+
+	for _, name := range names {
+		if joined[name] {
+			continue
+		}
+		group, err := p.b.dao.GetGroup(ctx, orm.Query{"name=?": name})
+		if err != nil || group == nil || group.Id == 0 {
+			continue
+		}
+		if err = p.b.dao.AddUser2Group(ctx, group.Id, user.Id, false); err != nil {
+			p.b.logger.WarnWithFields(logger.Fields{
+				"user_id": user.Id,
+				"group":   name,
+				"error":   err,
+			}, "An error occurred while dao.AddUser2Group in biz.oidcLoginProvider.syncGroups, skipped it.")
+			continue
+		}
+		p.b.logger.InfoWithFields(logger.Fields{
+			"user_id": user.Id,
+			"group":   name,
+		}, "User added to group by oidc claim.")
+	}
+}
+
+// genOidcStateKey 生成跳转登录state的Key
+func genOidcStateKey(state string) string {
+	return "oidc_state/" + state
+}
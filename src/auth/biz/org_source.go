@@ -0,0 +1,79 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/common/wework"
+	"strconv"
+)
+
+// OrgSourceWework 企业微信组织架构来源名称
+const OrgSourceWework = "wework"
+
+// OrgSource 组织架构来源，企业微信、LDAP等实现该接口即可接入组织架构同步
+type OrgSource interface {
+	// Name 来源名称，用于标记同步创建的部门
+	Name() string
+	// ListDepartments 列出全部部门
+	ListDepartments(ctx context.Context) ([]*dto.OrgDepartment, error)
+	// ListUsers 列出全部用户及其主部门
+	ListUsers(ctx context.Context) ([]*dto.OrgUser, error)
+}
+
+// weworkOrgSource 企业微信组织架构来源
+type weworkOrgSource struct {
+	wework wework.Wework
+}
+
+// NewWeworkOrgSource 生成企业微信组织架构来源
+func NewWeworkOrgSource(w wework.Wework) OrgSource {
+	return &weworkOrgSource{wework: w}
+}
+
+// Name 来源名称
+func (ws *weworkOrgSource) Name() string {
+	return OrgSourceWework
+}
+
+// ListDepartments 列出企业微信全部部门，根部门的父部门ID为0
+func (ws *weworkOrgSource) ListDepartments(_ context.Context) ([]*dto.OrgDepartment, error) {
+	wDepts, err := ws.wework.ListDepartments()
+	if err != nil {
+		return nil, err
+	}
+
+	depts := make([]*dto.OrgDepartment, 0, len(wDepts))
+	for _, wd := range wDepts {
+		dept := &dto.OrgDepartment{
+			ExternalId: strconv.Itoa(wd.Id),
+			Name:       wd.Name,
+			Leaders:    wd.Leaders,
+		}
+		if wd.ParentId != 0 {
+			dept.ParentExternalId = strconv.Itoa(wd.ParentId)
+		}
+		depts = append(depts, dept)
+	}
+	return depts, nil
+}
+
+// ListUsers 列出企业微信全部用户，企业微信只返回用户的第一个部门
+func (ws *weworkOrgSource) ListUsers(_ context.Context) ([]*dto.OrgUser, error) {
+	wUsers, err := ws.wework.ListUsersWithDepartment()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*dto.OrgUser, 0, len(wUsers))
+	for _, wu := range wUsers {
+		u := &dto.OrgUser{
+			Username:    wu.Username,
+			DisplayName: wu.DisplayName,
+		}
+		if wu.DepartmentId != 0 {
+			u.DepartmentExternalId = strconv.Itoa(wu.DepartmentId)
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
@@ -0,0 +1,573 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyOrgSource 组织架构来源返回空数据
+var ErrEmptyOrgSource = errors.New("org source returned no departments or users")
+
+// orgSync 单次组织架构同步的状态
+type orgSync struct {
+	b      *Biz
+	source string
+	dryRun bool
+	diff   *dto.OrgSyncDiff
+
+	// depts 本地部门，Key为部门ID，DryRun时新建的部门没有ID不在其中
+	depts map[uint32]*model.Department
+	// names 已使用的部门名称
+	names map[string]*model.Department
+	// bound 已绑定到当前来源的本地部门，Key为来源中的部门ID
+	bound map[string]*model.Department
+	// resolved 来源部门对应的本地部门，Key为来源中的部门ID
+	resolved map[string]*model.Department
+	// members 用户部门关联，Key为用户ID
+	members map[uint32]*model.UserDepartment
+}
+
+// SyncOrg 从组织架构来源同步部门、部门成员及部门Owner
+// 来源中已不存在的用户如果存在本地数据则只移除部门关联，不删除用户；dryRun为true时只计算差异
+func (b *Biz) SyncOrg(ctx context.Context, src OrgSource, dryRun bool) (*dto.OrgSyncDiff, error) {
+	b.logger.InfoWithFields(logger.Fields{
+		"source":  src.Name(),
+		"dry_run": dryRun,
+	}, "Biz.SyncOrg called.")
+	defer b.logger.Info("Biz.SyncOrg end.")
+
+	srcDepts, err := src.ListDepartments(ctx)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"source": src.Name(),
+			"error":  err,
+		}, "An error occurred while OrgSource.ListDepartments in biz.SyncOrg.")
+		return nil, err
+	}
+	srcUsers, err := src.ListUsers(ctx)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"source": src.Name(),
+			"error":  err,
+		}, "An error occurred while OrgSource.ListUsers in biz.SyncOrg.")
+		return nil, err
+	}
+	// 来源异常返回空数据时不同步，避免误删全部部门和用户
+	if len(srcDepts) == 0 || len(srcUsers) == 0 {
+		b.logger.ErrorWithFields(logger.Fields{
+			"source": src.Name(),
+			"error":  ErrEmptyOrgSource,
+		}, "Org source returned empty data in biz.SyncOrg.")
+		return nil, ErrEmptyOrgSource
+	}
+
+	s, err := b.newOrgSync(ctx, src.Name(), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.syncDepartments(ctx, srcDepts); err != nil {
+		return s.diff, err
+	}
+	if err = s.syncUsers(ctx, srcDepts, srcUsers); err != nil {
+		return s.diff, err
+	}
+	if err = s.removeDepartments(ctx, srcDepts); err != nil {
+		return s.diff, err
+	}
+
+	b.logger.InfoWithFields(logger.Fields{
+		"source":  src.Name(),
+		"dry_run": dryRun,
+		"changes": len(s.diff.Changes),
+	}, "Org sync finished.")
+	return s.diff, nil
+}
+
+// newOrgSync 加载本地部门及部门关联
+func (b *Biz) newOrgSync(ctx context.Context, source string, dryRun bool) (*orgSync, error) {
+	depts, err := b.dao.ListDepartments(ctx, orm.Query{})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListDepartments in biz.newOrgSync.")
+		return nil, err
+	}
+	uds, err := b.dao.ListUserDepartments(ctx, orm.Query{})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListUserDepartments in biz.newOrgSync.")
+		return nil, err
+	}
+
+	s := &orgSync{
+		b:      b,
+		source: source,
+		dryRun: dryRun,
+		diff: &dto.OrgSyncDiff{
+			Source:  source,
+			DryRun:  dryRun,
+			Changes: make([]*dto.OrgSyncChange, 0),
+		},
+		depts:    make(map[uint32]*model.Department, len(depts)),
+		names:    make(map[string]*model.Department, len(depts)),
+		bound:    make(map[string]*model.Department),
+		resolved: make(map[string]*model.Department),
+		members:  make(map[uint32]*model.UserDepartment, len(uds)),
+	}
+	for _, d := range depts {
+		s.depts[d.Id] = d
+		s.names[d.Name] = d
+		if s.isBound(d) && d.ExternalId != nil {
+			s.bound[*d.ExternalId] = d
+		}
+	}
+	for _, ud := range uds {
+		s.members[ud.UserId] = ud
+	}
+	return s, nil
+}
+
+// syncDepartments 按来源新建或更新部门，父部门先于子部门处理
+func (s *orgSync) syncDepartments(ctx context.Context, srcDepts []*dto.OrgDepartment) error {
+	for _, sd := range sortOrgDepartments(srcDepts) {
+		var parentId *uint32
+		// DryRun时新建的父部门没有ID，视为父部门变更
+		parentNew := false
+		if p, ok := s.resolved[sd.ParentExternalId]; ok {
+			if p.Id == 0 {
+				parentNew = true
+			} else {
+				id := p.Id
+				parentId = &id
+			}
+		}
+		parentName := "-"
+		if p, ok := s.resolved[sd.ParentExternalId]; ok {
+			parentName = p.Name
+		}
+
+		local := s.findDepartment(sd)
+		name := s.departmentName(sd, local)
+
+		if local == nil {
+			s.record(dto.OrgSyncActionCreate, dto.OrgSyncObjectDepartment, name,
+				fmt.Sprintf("external_id=%s, parent=%s", sd.ExternalId, parentName))
+
+			extId := sd.ExternalId
+			dept := &model.Department{Name: name, ParentId: parentId, Source: &s.source, ExternalId: &extId}
+			if !s.dryRun {
+				var err error
+				dept, err = s.b.dao.NewSyncedDepartment(ctx, name, parentId, s.source, sd.ExternalId)
+				if err != nil {
+					s.b.logger.ErrorWithFields(logger.Fields{
+						"name":        name,
+						"external_id": sd.ExternalId,
+						"error":       err,
+					}, "An error occurred while dao.NewSyncedDepartment in biz.syncDepartments.")
+					return err
+				}
+				s.depts[dept.Id] = dept
+			}
+			s.names[name] = dept
+			s.resolved[sd.ExternalId] = dept
+			continue
+		}
+
+		details := make([]string, 0)
+		if !s.isBound(local) {
+			details = append(details, fmt.Sprintf("bind external_id=%s", sd.ExternalId))
+		}
+		if local.Name != name {
+			details = append(details, fmt.Sprintf("name: %s -> %s", local.Name, name))
+		}
+		if parentNew || !sameDepartmentId(local.ParentId, parentId) {
+			details = append(details, fmt.Sprintf("parent: %s -> %s", s.departmentLabel(local.ParentId), parentName))
+		}
+		if len(details) > 0 {
+			s.record(dto.OrgSyncActionUpdate, dto.OrgSyncObjectDepartment, local.Name, strings.Join(details, ", "))
+
+			if !s.dryRun {
+				err := s.b.dao.SetSyncedDepartment(ctx, local.Id, name, parentId, s.source, sd.ExternalId)
+				if err != nil {
+					s.b.logger.ErrorWithFields(logger.Fields{
+						"department_id": local.Id,
+						"external_id":   sd.ExternalId,
+						"error":         err,
+					}, "An error occurred while dao.SetSyncedDepartment in biz.syncDepartments.")
+					return err
+				}
+			}
+
+			delete(s.names, local.Name)
+			extId := sd.ExternalId
+			local.Name = name
+			local.ParentId = parentId
+			local.Source = &s.source
+			local.ExternalId = &extId
+			s.names[name] = local
+		}
+		s.resolved[sd.ExternalId] = local
+	}
+
+	return nil
+}
+
+// syncUsers 按来源同步用户所在部门，来源中不存在的用户移出同步部门
+func (s *orgSync) syncUsers(ctx context.Context, srcDepts []*dto.OrgDepartment, srcUsers []*dto.OrgUser) error {
+	users, err := s.b.dao.ListUsers(ctx, orm.Query{})
+	if err != nil {
+		s.b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListUsers in biz.syncUsers.")
+		return err
+	}
+	byName := make(map[string]*model.User, len(users))
+	for _, u := range users {
+		byName[strings.ToLower(u.Username)] = u
+	}
+
+	// 部门负责人，Key为来源中的部门ID
+	leaders := make(map[string]map[string]bool, len(srcDepts))
+	for _, sd := range srcDepts {
+		leaders[sd.ExternalId] = make(map[string]bool, len(sd.Leaders))
+		for _, l := range sd.Leaders {
+			leaders[sd.ExternalId][strings.ToLower(l)] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(srcUsers))
+	for _, su := range srcUsers {
+		username := strings.ToLower(su.Username)
+		seen[username] = true
+
+		dept, ok := s.resolved[su.DepartmentExternalId]
+		if !ok {
+			s.b.logger.WarnWithFields(logger.Fields{
+				"username":    username,
+				"external_id": su.DepartmentExternalId,
+			}, "User's department not found in org source, skipped it.")
+			continue
+		}
+
+		u, ok := byName[username]
+		if !ok {
+			s.record(dto.OrgSyncActionCreate, dto.OrgSyncObjectUser, username,
+				fmt.Sprintf("display_name=%s", su.DisplayName))
+
+			u = &model.User{Username: username}
+			if !s.dryRun {
+				u, err = s.b.dao.NewUser(ctx, username, su.Email, false)
+				if err != nil {
+					s.b.logger.ErrorWithFields(logger.Fields{
+						"username": username,
+						"error":    err,
+					}, "An error occurred while dao.NewUser in biz.syncUsers.")
+					return err
+				}
+			}
+		}
+
+		if err = s.syncMembership(ctx, u, dept, leaders[su.DepartmentExternalId][username]); err != nil {
+			return err
+		}
+	}
+
+	// 来源中已不存在的用户，仅处理在同步部门中的用户
+	for _, u := range users {
+		if seen[strings.ToLower(u.Username)] {
+			continue
+		}
+		m, ok := s.members[u.Id]
+		if !ok {
+			continue
+		}
+		if d, ok := s.depts[m.DepartmentId]; !ok || !s.isBound(d) {
+			continue
+		}
+		if err = s.removeUser(ctx, u, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncMembership 同步用户所在部门及是否为部门Owner
+func (s *orgSync) syncMembership(ctx context.Context, u *model.User, dept *model.Department, isOwner bool) error {
+	var m *model.UserDepartment
+	if u.Id != 0 {
+		m = s.members[u.Id]
+	}
+
+	var err error
+	switch {
+	case m == nil:
+		s.record(dto.OrgSyncActionCreate, dto.OrgSyncObjectMembership, u.Username,
+			fmt.Sprintf("department=%s, is_owner=%t", dept.Name, isOwner))
+		if !s.dryRun {
+			err = s.b.dao.AddUser2Department(ctx, u.Id, dept.Id, isOwner)
+		}
+
+	case dept.Id == 0 || m.DepartmentId != dept.Id:
+		s.record(dto.OrgSyncActionUpdate, dto.OrgSyncObjectMembership, u.Username,
+			fmt.Sprintf("department: %s -> %s, is_owner=%t", s.departmentLabel(&m.DepartmentId), dept.Name, isOwner))
+		if !s.dryRun {
+			// 一个用户只能属于一个部门
+			if err = s.b.dao.RemoveUsersDepartments(ctx, u.Id); err == nil {
+				err = s.b.dao.AddUser2Department(ctx, u.Id, dept.Id, isOwner)
+			}
+		}
+
+	case m.IsOwner == nil || *m.IsOwner != isOwner:
+		s.record(dto.OrgSyncActionUpdate, dto.OrgSyncObjectMembership, u.Username,
+			fmt.Sprintf("department=%s, is_owner: %t -> %t", dept.Name, !isOwner, isOwner))
+		if !s.dryRun {
+			err = s.b.dao.SetDepartmentsOwner(ctx, dept.Id, u.Id, isOwner)
+		}
+
+	default:
+		return nil
+	}
+
+	if err != nil {
+		s.b.logger.ErrorWithFields(logger.Fields{
+			"user_id":       u.Id,
+			"department_id": dept.Id,
+			"error":         err,
+		}, "An error occurred while updating user's department in biz.syncMembership.")
+		return err
+	}
+
+	if u.Id != 0 {
+		s.members[u.Id] = &model.UserDepartment{UserId: u.Id, DepartmentId: dept.Id, IsOwner: &isOwner}
+	}
+	return nil
+}
+
+// removeUser 处理来源中已不存在的用户，存在本地数据的用户只移出部门
+func (s *orgSync) removeUser(ctx context.Context, u *model.User, m *model.UserDepartment) error {
+	hasLocal, err := s.b.hasUserLocalData(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	s.record(dto.OrgSyncActionDelete, dto.OrgSyncObjectMembership, u.Username,
+		fmt.Sprintf("department=%s", s.departmentLabel(&m.DepartmentId)))
+	if hasLocal {
+		s.record(dto.OrgSyncActionKeep, dto.OrgSyncObjectUser, u.Username, "user has local data")
+	} else {
+		s.record(dto.OrgSyncActionDelete, dto.OrgSyncObjectUser, u.Username, "")
+	}
+
+	delete(s.members, u.Id)
+	if s.dryRun {
+		return nil
+	}
+
+	if err = s.b.dao.RemoveUsersDepartments(ctx, u.Id); err != nil {
+		s.b.logger.ErrorWithFields(logger.Fields{
+			"user_id": u.Id,
+			"error":   err,
+		}, "An error occurred while dao.RemoveUsersDepartments in biz.removeUser.")
+		return err
+	}
+	if hasLocal {
+		return nil
+	}
+	if err = s.b.dao.RemoveUser(ctx, u.Id); err != nil {
+		s.b.logger.ErrorWithFields(logger.Fields{
+			"user_id": u.Id,
+			"error":   err,
+		}, "An error occurred while dao.RemoveUser in biz.removeUser.")
+		return err
+	}
+	return nil
+}
+
+// removeDepartments 删除来源中已不存在的部门，仍有成员或子部门的部门保留
+func (s *orgSync) removeDepartments(ctx context.Context, srcDepts []*dto.OrgDepartment) error {
+	exists := make(map[string]bool, len(srcDepts))
+	for _, sd := range srcDepts {
+		exists[sd.ExternalId] = true
+	}
+
+	stale := make(map[uint32]*model.Department)
+	for extId, d := range s.bound {
+		if !exists[extId] {
+			stale[d.Id] = d
+		}
+	}
+
+	// 由叶子部门开始逐层删除
+	for removed := true; removed; {
+		removed = false
+		for id, d := range stale {
+			if s.departmentInUse(id) {
+				continue
+			}
+
+			s.record(dto.OrgSyncActionDelete, dto.OrgSyncObjectDepartment, d.Name,
+				fmt.Sprintf("external_id=%s", *d.ExternalId))
+			if !s.dryRun {
+				if err := s.b.dao.RemoveDepartment(ctx, id); err != nil {
+					s.b.logger.ErrorWithFields(logger.Fields{
+						"department_id": id,
+						"error":         err,
+					}, "An error occurred while dao.RemoveDepartment in biz.removeDepartments.")
+					return err
+				}
+			}
+			delete(stale, id)
+			delete(s.depts, id)
+			removed = true
+		}
+	}
+
+	for _, d := range stale {
+		s.record(dto.OrgSyncActionKeep, dto.OrgSyncObjectDepartment, d.Name,
+			"department not in source but still has users or sub departments")
+	}
+	return nil
+}
+
+// departmentInUse 部门是否仍有成员或子部门
+func (s *orgSync) departmentInUse(id uint32) bool {
+	for _, m := range s.members {
+		if m.DepartmentId == id {
+			return true
+		}
+	}
+	for _, d := range s.depts {
+		if d.ParentId != nil && *d.ParentId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// findDepartment 查找来源部门对应的本地部门，未绑定来源的同名部门会被接管
+func (s *orgSync) findDepartment(sd *dto.OrgDepartment) *model.Department {
+	if d, ok := s.bound[sd.ExternalId]; ok {
+		return d
+	}
+	if d, ok := s.names[sd.Name]; ok && d.Id != 0 && d.Source == nil {
+		return d
+	}
+	return nil
+}
+
+// departmentName 获得部门名称，与其他部门重名时以来源部门ID作为后缀
+func (s *orgSync) departmentName(sd *dto.OrgDepartment, local *model.Department) string {
+	if d, ok := s.names[sd.Name]; ok && d != local {
+		return fmt.Sprintf("%s(%s)", sd.Name, sd.ExternalId)
+	}
+	return sd.Name
+}
+
+// departmentLabel 获得用于日志展示的部门名称
+func (s *orgSync) departmentLabel(id *uint32) string {
+	if id == nil {
+		return "-"
+	}
+	if d, ok := s.depts[*id]; ok {
+		return d.Name
+	}
+	return fmt.Sprintf("#%d", *id)
+}
+
+// isBound 部门是否绑定到当前来源
+func (s *orgSync) isBound(d *model.Department) bool {
+	return d.Source != nil && *d.Source == s.source
+}
+
+// record 记录并输出一条变更
+func (s *orgSync) record(action, object, name, detail string) {
+	s.diff.Changes = append(s.diff.Changes, &dto.OrgSyncChange{
+		Action: action,
+		Object: object,
+		Name:   name,
+		Detail: detail,
+	})
+
+	s.b.logger.InfoWithFields(logger.Fields{
+		"source":  s.source,
+		"dry_run": s.dryRun,
+		"action":  action,
+		"object":  object,
+		"name":    name,
+		"detail":  detail,
+	}, "Org sync change.")
+}
+
+// hasUserLocalData 用户是否存在本地数据：超级管理员、本地密码、登录记录、角色、组或产品线
+func (b *Biz) hasUserLocalData(ctx context.Context, u *model.User) (bool, error) {
+	if u.IsSuperuser || u.Password != "" || u.LastLogin != nil {
+		return true, nil
+	}
+
+	roles, err := b.dao.ListUsersRoles(ctx, u.Id)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": u.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUsersRoles in biz.hasUserLocalData.")
+		return false, err
+	}
+	groups, err := b.dao.ListUsersGroups(ctx, u.Id)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": u.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUsersGroups in biz.hasUserLocalData.")
+		return false, err
+	}
+	products, err := b.dao.ListUsersProducts(ctx, u.Id)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": u.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUsersProducts in biz.hasUserLocalData.")
+		return false, err
+	}
+
+	return len(roles) > 0 || len(groups) > 0 || len(products) > 0, nil
+}
+
+// sortOrgDepartments 按层级排序部门，父部门在前；父部门不在来源中的部门视为根部门
+func sortOrgDepartments(depts []*dto.OrgDepartment) []*dto.OrgDepartment {
+	exists := make(map[string]bool, len(depts))
+	for _, d := range depts {
+		exists[d.ExternalId] = true
+	}
+
+	sorted := make([]*dto.OrgDepartment, 0, len(depts))
+	children := make(map[string][]*dto.OrgDepartment)
+	for _, d := range depts {
+		if d.ParentExternalId == "" || !exists[d.ParentExternalId] {
+			sorted = append(sorted, d)
+			continue
+		}
+		children[d.ParentExternalId] = append(children[d.ParentExternalId], d)
+	}
+	// 存在环的部门无法到达，不会被同步
+	for i := 0; i < len(sorted); i++ {
+		sorted = append(sorted, children[sorted[i].ExternalId]...)
+	}
+	return sorted
+}
+
+// sameDepartmentId 比较两个可为空的部门ID
+func sameDepartmentId(a, b *uint32) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,272 @@
+package biz
+
+import (
+	"context"
+	"crypto/subtle"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/utils"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// bcryptHashPrefix bcrypt哈希的前缀，不带该前缀的视为旧的SHA256哈希
+	bcryptHashPrefix = "$2"
+	// passwordMaxLength bcrypt只使用密码的前72个字节
+	passwordMaxLength = 72
+)
+
+// CheckPasswordPolicy 检查密码是否符合长度及复杂度要求
+func (b *Biz) CheckPasswordPolicy(password string) error {
+	if utf8.RuneCountInString(password) < b.conf.PasswordMinLength {
+		return fmt.Errorf("%w: 长度不能少于%d位", dto.ErrPasswordPolicy, b.conf.PasswordMinLength)
+	}
+	if len(password) > passwordMaxLength {
+		return fmt.Errorf("%w: 长度不能超过%d字节", dto.ErrPasswordPolicy, passwordMaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < b.conf.PasswordMinClasses {
+		return fmt.Errorf(
+			"%w: 需包含大写字母、小写字母、数字、符号中的至少%d种", dto.ErrPasswordPolicy, b.conf.PasswordMinClasses,
+		)
+	}
+	return nil
+}
+
+// ChangePassword 用户使用原密码修改自己的密码，修改后注销该用户的所有会话
+// 无需登录即可调用，管理员重置密码后用户通过该方法修改密码
+// 用户不存在、已禁用、是服务账号或没有密码时与密码错误返回相同的错误，避免泄露用户是否存在及其状态
+func (b *Biz) ChangePassword(ctx context.Context, username, oldPassword, newPassword string) error {
+	user, err := b.dao.GetUser(ctx, orm.Query{"username=?": strings.ToLower(username)})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"username": username,
+			"error":    err,
+		}, "An error occurred while dao.GetUser in biz.ChangePassword.")
+		return err
+	}
+	// 无法校验密码时同样进行一次哈希比较，避免通过耗时区分
+	if user == nil || user.Id == 0 || user.Password == "" {
+		b.compareDummyPassword(oldPassword)
+		return dto.ErrPasswordIncorrect
+	}
+
+	if err = b.authenticatePassword(ctx, user, oldPassword); err != nil {
+		return err
+	}
+	if user.Disabled || user.IsServiceAccount {
+		return dto.ErrPasswordIncorrect
+	}
+	if err = b.CheckPasswordPolicy(newPassword); err != nil {
+		return err
+	}
+	if err = b.checkPasswordHistory(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	if err = b.setPassword(ctx, user.Id, newPassword, false); err != nil {
+		return err
+	}
+	b.revokeSessionsAfterPasswordSet(ctx, user.Id)
+	return nil
+}
+
+// ResetPassword 管理员重置用户密码，用户下次登录前需修改密码，同时注销该用户的所有会话
+func (b *Biz) ResetPassword(ctx context.Context, userId uint32, newPassword string) error {
+	if err := b.CheckPasswordPolicy(newPassword); err != nil {
+		return err
+	}
+	if err := b.setPassword(ctx, userId, newPassword, true); err != nil {
+		return err
+	}
+	b.revokeSessionsAfterPasswordSet(ctx, userId)
+	return nil
+}
+
+// authenticatePassword 校验用户密码，处理账号锁定及连续失败次数，校验通过时将旧的哈希升级为bcrypt
+func (b *Biz) authenticatePassword(ctx context.Context, user *model.User, password string) error {
+	// 判断账号是否处于锁定状态
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return dto.ErrLoginUserLocked
+	}
+
+	match, legacy := b.comparePassword(user.Password, password)
+	if !match {
+		b.recordLoginFailure(ctx, user)
+		return dto.ErrPasswordIncorrect
+	}
+
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := b.dao.SetUserLoginFailures(ctx, user.Id, 0, nil); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": user.Id,
+				"error":   err,
+			}, "An error occurred while dao.SetUserLoginFailures in biz.authenticatePassword, but skipped.")
+		}
+	}
+
+	// 透明升级旧的SHA256哈希
+	if legacy {
+		hash, err := b.hashPassword(password)
+		if err == nil {
+			err = b.dao.SetUserPasswordHash(ctx, user.Id, hash)
+		}
+		if err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": user.Id,
+				"error":   err,
+			}, "An error occurred while rehashing legacy password in biz.authenticatePassword, but skipped.")
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure 记录一次登录失败，连续失败达到上限时锁定账号并重新计数
+func (b *Biz) recordLoginFailure(ctx context.Context, user *model.User) {
+	if b.conf.PasswordMaxFailures < 1 {
+		return
+	}
+
+	lockedUntil := time.Now().Add(b.conf.PasswordLockoutDuration)
+	locked, err := b.dao.IncrUserLoginFailures(ctx, user.Id, b.conf.PasswordMaxFailures, lockedUntil)
+	if err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while dao.IncrUserLoginFailures in biz.recordLoginFailure, but skipped.")
+		return
+	}
+	if locked {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id":      user.Id,
+			"locked_until": lockedUntil,
+		}, "User locked due to too many login failures.")
+	}
+}
+
+// checkPasswordHistory 检查新密码是否与当前密码或最近使用过的密码相同
+func (b *Biz) checkPasswordHistory(ctx context.Context, user *model.User, password string) error {
+	if match, _ := b.comparePassword(user.Password, password); match {
+		return dto.ErrPasswordReused
+	}
+	if b.conf.PasswordHistory < 1 {
+		return nil
+	}
+
+	hs, err := b.dao.ListUserPasswordHistories(ctx, user.Id, b.conf.PasswordHistory)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while dao.ListUserPasswordHistories in biz.checkPasswordHistory.")
+		return err
+	}
+	for _, h := range hs {
+		if match, _ := b.comparePassword(h.Password, password); match {
+			return dto.ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// setPassword 计算哈希并保存密码
+func (b *Biz) setPassword(ctx context.Context, userId uint32, password string, mustChange bool) error {
+	hash, err := b.hashPassword(password)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while biz.hashPassword in biz.setPassword.")
+		return err
+	}
+
+	if err = b.dao.SetUserPassword(ctx, userId, hash, mustChange); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.SetUserPassword in biz.setPassword.")
+		return err
+	}
+	return nil
+}
+
+// revokeSessionsAfterPasswordSet 密码变更后注销用户的所有会话
+func (b *Biz) revokeSessionsAfterPasswordSet(ctx context.Context, userId uint32) {
+	if _, err := b.RevokeUserSessions(ctx, userId); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while biz.RevokeUserSessions after password set, but skipped.")
+	}
+}
+
+// hashPassword 使用bcrypt计算密码哈希
+func (b *Biz) hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.conf.PasswordBcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// compareDummyPassword 与固定的哈希比较密码，用于无法校验密码时消耗与正常校验相近的时间
+func (b *Biz) compareDummyPassword(password string) {
+	b.dummyPasswordHashOnce.Do(func() {
+		hash, err := b.hashPassword(utils.GenSha256HashCode(b.conf.SecretKey))
+		if err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"error": err,
+			}, "An error occurred while biz.hashPassword in biz.compareDummyPassword, but skipped.")
+			return
+		}
+		b.dummyPasswordHash = hash
+	})
+	_, _ = b.comparePassword(b.dummyPasswordHash, password)
+}
+
+// comparePassword 比较密码与哈希是否匹配，legacy表示哈希为旧的SHA256格式
+func (b *Biz) comparePassword(hash, password string) (match, legacy bool) {
+	if hash == "" {
+		return false, false
+	}
+
+	if strings.HasPrefix(hash, bcryptHashPrefix) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err != nil && !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			b.logger.WarnWithFields(logger.Fields{
+				"error": err,
+			}, "An error occurred while bcrypt.CompareHashAndPassword in biz.comparePassword.")
+		}
+		return err == nil, false
+	}
+
+	salted := utils.GenSha256HashCode(password + b.conf.SecretKey)
+	return subtle.ConstantTimeCompare([]byte(salted), []byte(hash)) == 1, true
+}
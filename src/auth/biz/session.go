@@ -0,0 +1,204 @@
+package biz
+
+import (
+	"context"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/utils"
+	"encoding/json"
+	"time"
+)
+
+// sessionIdLen 会话ID长度
+const sessionIdLen = 16
+
+// newSession 记录Token对应的会话，过期时间与Token一致
+func (b *Biz) newSession(ctx context.Context, userObj *model.User, token string, info *dto.SessionInfo) {
+	now := &utils.CustomTime{Time: time.Now()}
+	sess := &dto.Session{
+		Id:              genSessionId(token),
+		UserId:          userObj.Id,
+		Username:        userObj.Username,
+		CreatedAt:       now,
+		LastHeartbeatAt: now,
+	}
+	if info != nil {
+		sess.LoginMethod = info.LoginMethod
+		sess.Ip = info.Ip
+		sess.UserAgent = info.UserAgent
+		sess.Mfa = info.Mfa
+	}
+
+	content, _ := json.Marshal(sess)
+	if err := b.redis.Set(ctx, genSessionKey(token), string(content), b.conf.TokenTtl); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": userObj.Id,
+			"error":   err,
+		}, "An error occurred while redis.Set in biz.newSession, but skipped.")
+	}
+}
+
+// touchSession 更新会话最近心跳时间并续期
+func (b *Biz) touchSession(ctx context.Context, token string) {
+	sess, err := b.getSession(ctx, token)
+	if err != nil || sess == nil {
+		return
+	}
+
+	sess.LastHeartbeatAt = &utils.CustomTime{Time: time.Now()}
+	content, _ := json.Marshal(sess)
+	if _, err = b.redis.SetXX(ctx, genSessionKey(token), string(content), b.conf.TokenTtl); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": sess.UserId,
+			"error":   err,
+		}, "An error occurred while redis.SetXX in biz.touchSession, but skipped.")
+	}
+}
+
+// getSession 获得Token对应的会话，会话不存在时返回nil
+func (b *Biz) getSession(ctx context.Context, token string) (*dto.Session, error) {
+	if !b.redis.Exist(ctx, genSessionKey(token)) {
+		return nil, nil
+	}
+	content, err := b.redis.Get(ctx, genSessionKey(token))
+	if err != nil {
+		return nil, err
+	}
+
+	sess := new(dto.Session)
+	if err = json.Unmarshal([]byte(content), sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// ListUserSessions 列出用户所有有效会话，currToken对应的会话标记为当前会话
+func (b *Biz) ListUserSessions(ctx context.Context, userId uint32, currToken string) ([]*dto.Session, error) {
+	b.logger.InfoWithFields(logger.Fields{"user_id": userId}, "biz.ListUserSessions called.")
+	defer b.logger.Info("biz.ListUserSessions end.")
+
+	tokens, err := b.redis.SMembers(ctx, genUserTokensKey(userId))
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.ListUserSessions.")
+		return nil, err
+	}
+
+	sessions := make([]*dto.Session, 0, len(tokens))
+	for _, token := range tokens {
+		if !b.VerifyToken(ctx, token) {
+			continue
+		}
+
+		sess, err := b.getSession(ctx, token)
+		if err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while biz.getSession in biz.ListUserSessions, skipped it.")
+		}
+		// 会话记录缺失的Token仍然有效，只展示会话ID
+		if sess == nil {
+			sess = &dto.Session{Id: genSessionId(token), UserId: userId}
+		}
+		sess.Current = token == currToken
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
+// RevokeUserSession 注销用户的指定会话
+func (b *Biz) RevokeUserSession(ctx context.Context, userId uint32, sessionId string) error {
+	b.logger.InfoWithFields(logger.Fields{
+		"user_id":    userId,
+		"session_id": sessionId,
+	}, "biz.RevokeUserSession called.")
+	defer b.logger.Info("biz.RevokeUserSession end.")
+
+	tokens, err := b.redis.SMembers(ctx, genUserTokensKey(userId))
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.RevokeUserSession.")
+		return err
+	}
+
+	for _, token := range tokens {
+		if genSessionId(token) != sessionId {
+			continue
+		}
+		if !b.VerifyToken(ctx, token) {
+			break
+		}
+		b.revokeUserTokens(ctx, userId, token)
+		return nil
+	}
+	return dto.ErrSessionNotFound
+}
+
+// RevokeUserSessions 注销用户的所有会话，返回注销的会话数量
+func (b *Biz) RevokeUserSessions(ctx context.Context, userId uint32) (int, error) {
+	b.logger.InfoWithFields(logger.Fields{"user_id": userId}, "biz.RevokeUserSessions called.")
+	defer b.logger.Info("biz.RevokeUserSessions end.")
+
+	tokens, err := b.redis.SMembers(ctx, genUserTokensKey(userId))
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.RevokeUserSessions.")
+		return 0, err
+	}
+
+	count := 0
+	for _, token := range tokens {
+		if b.VerifyToken(ctx, token) {
+			count++
+		}
+	}
+	b.revokeUserTokens(ctx, userId, tokens...)
+	return count, nil
+}
+
+// revokeUserTokens 删除用户的Token及其会话，并从用户Token索引中移除
+func (b *Biz) revokeUserTokens(ctx context.Context, userId uint32, tokens ...string) {
+	for _, token := range tokens {
+		if err := b.redis.Del(ctx, genTokenKey(token)); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.Del in biz.revokeUserTokens, but skipped.")
+		}
+		if err := b.redis.Del(ctx, genSessionKey(token)); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.Del in biz.revokeUserTokens, but skipped.")
+		}
+		if err := b.redis.SRem(ctx, genUserTokensKey(userId), token); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.SRem in biz.revokeUserTokens, but skipped.")
+		}
+	}
+
+	b.logger.InfoWithFields(logger.Fields{
+		"user_id": userId,
+		"tokens":  len(tokens),
+	}, "User sessions revoked.")
+}
+
+// genSessionKey 生成会话的Key
+func genSessionKey(token string) string {
+	return "session/" + token
+}
+
+// genSessionId 由Token生成会话ID
+func genSessionId(token string) string {
+	return utils.GenSha256HashCode(token)[:sessionIdLen]
+}
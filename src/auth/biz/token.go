@@ -4,16 +4,20 @@ import (
 	"context"
 	"eago/auth/dto"
 	"eago/auth/model"
+	perm "eago/common/api/permission"
 	"eago/common/global"
 	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/redis"
 	"eago/common/utils"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 )
 
-// NewToken 生成Token
-func (b *Biz) NewToken(ctx context.Context, userObj *model.User) string {
+// NewToken 生成Token并记录会话
+func (b *Biz) NewToken(ctx context.Context, userObj *model.User, info *dto.SessionInfo) string {
 	b.logger.InfoWithFields(logger.Fields{
 		"user_id": userObj.Id,
 	}, "biz.NewToken called.")
@@ -31,7 +35,9 @@ func (b *Biz) NewToken(ctx context.Context, userObj *model.User) string {
 		"user_id":   userObj.Id,
 		"token_ket": tokenKey,
 	}, "Writing token to redis.")
-	tokenContent, _ := json.Marshal(b.genTokenContent(ctx, userObj))
+	tc := b.genTokenContent(ctx, userObj)
+	tc.Mfa = info != nil && info.Mfa
+	tokenContent, _ := json.Marshal(tc)
 	if err := b.redis.Set(ctx, tokenKey, string(tokenContent), b.conf.TokenTtl); err != nil {
 		b.logger.ErrorWithFields(logger.Fields{
 			"user_id":  userObj.Id,
@@ -40,6 +46,15 @@ func (b *Biz) NewToken(ctx context.Context, userObj *model.User) string {
 		}, "An error occurred while redis.Set in biz.NewToken.")
 		return ""
 	}
+	// 记录用户的Token，用于权限变更时刷新Token内容
+	b.pruneUserTokens(ctx, userObj.Id)
+	if err := b.redis.SAdd(ctx, genUserTokensKey(userObj.Id), token); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": userObj.Id,
+			"error":   err,
+		}, "An error occurred while redis.SAdd in biz.NewToken, but skipped.")
+	}
+	b.newSession(ctx, userObj, token, info)
 	return token
 }
 
@@ -48,12 +63,124 @@ func (b *Biz) RemoveToken(ctx context.Context, token string) {
 	b.logger.InfoWithFields(logger.Fields{"token": token}, "biz.RemoveToken called.")
 	defer b.logger.Info("biz.RemoveToken end.")
 
+	// 访问令牌只能通过接口删除
+	if isAccessToken(token) {
+		return
+	}
+
+	tc, _ := b.GetTokenContent(ctx, token)
+
 	b.logger.DebugWithFields(logger.Fields{"token": token}, "Remove token from redis.")
-	if err := b.redis.Del(ctx, genTokenKey(token)); err != nil {
+	// 无法获得TokenContent时只删除Token本身
+	if tc == nil {
+		if err := b.redis.Del(ctx, genTokenKey(token)); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"token": token,
+				"error": err,
+			}, "An error occurred while redis.Del in biz.RemoveToken, but skipped.")
+		}
+		return
+	}
+	b.revokeUserTokens(ctx, tc.UserId, token)
+}
+
+// pruneUserTokens 从用户Token索引中移除已过期的Token
+func (b *Biz) pruneUserTokens(ctx context.Context, userId uint32) {
+	indexKey := genUserTokensKey(userId)
+	tokens, err := b.redis.SMembers(ctx, indexKey)
+	if err != nil {
 		b.logger.WarnWithFields(logger.Fields{
-			"token": token,
-			"error": err,
-		}, "An error occurred while redis.Del in biz.RemoveToken, but skipped.")
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.pruneUserTokens, but skipped.")
+		return
+	}
+
+	for _, token := range tokens {
+		if b.redis.Exist(ctx, genTokenKey(token)) {
+			continue
+		}
+		if err = b.redis.SRem(ctx, indexKey, token); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.SRem in biz.pruneUserTokens, but skipped.")
+		}
+	}
+}
+
+// RefreshUsersTokens 重新生成用户所有有效Token的内容，用户不存在或已禁用时删除其所有Token
+func (b *Biz) RefreshUsersTokens(ctx context.Context, userIds ...uint32) {
+	b.logger.InfoWithFields(logger.Fields{"user_ids": userIds}, "biz.RefreshUsersTokens called.")
+	defer b.logger.Info("biz.RefreshUsersTokens end.")
+
+	for _, userId := range userIds {
+		b.refreshUserTokens(ctx, userId)
+		b.clearUserAccessTokensCache(ctx, userId)
+	}
+}
+
+// refreshUserTokens 重新生成单个用户所有有效Token的内容
+func (b *Biz) refreshUserTokens(ctx context.Context, userId uint32) {
+	indexKey := genUserTokensKey(userId)
+	tokens, err := b.redis.SMembers(ctx, indexKey)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while redis.SMembers in biz.refreshUserTokens.")
+		return
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	u, err := b.dao.GetUser(ctx, orm.Query{"id=?": userId})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.GetUser in biz.refreshUserTokens.")
+		return
+	}
+
+	// 用户已删除或禁用时注销其所有会话
+	if u == nil || u.Id == 0 || u.Disabled {
+		b.logger.InfoWithFields(logger.Fields{
+			"user_id": userId,
+		}, "User not found or disabled, revoking all sessions.")
+		b.revokeUserTokens(ctx, userId, tokens...)
+		return
+	}
+
+	tc := b.genTokenContent(ctx, u)
+	tokenContent, _ := json.Marshal(tc)
+	tc.Mfa = true
+	mfaTokenContent, _ := json.Marshal(tc)
+	for _, token := range tokens {
+		// 保持Token原有的两步验证状态
+		content := tokenContent
+		if b.isMfaToken(ctx, token) {
+			content = mfaTokenContent
+		}
+		// 仅更新仍然有效的Token并保持其过期时间，已过期的Token从索引中移除
+		ok, err := b.redis.SetXX(ctx, genTokenKey(token), string(content), redis.KeepTTL)
+		if err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.SetXX in biz.refreshUserTokens, but skipped.")
+			continue
+		}
+		if ok {
+			continue
+		}
+		if err = b.redis.SRem(ctx, indexKey, token); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"user_id": userId,
+				"error":   err,
+			}, "An error occurred while redis.SRem in biz.refreshUserTokens, but skipped.")
+		}
 	}
 }
 
@@ -62,16 +189,26 @@ func (b *Biz) RenewalToken(ctx context.Context, token string) {
 	b.logger.DebugWithFields(logger.Fields{"token": token}, "biz.RenewalToken called.")
 	defer b.logger.Debug("biz.RenewalToken end.")
 
+	// 访问令牌有独立的有效期，无需续期
+	if isAccessToken(token) {
+		return
+	}
+
 	if err := b.redis.Expire(ctx, genTokenKey(token), b.conf.TokenTtl); err != nil {
 		b.logger.WarnWithFields(logger.Fields{
 			"token": token,
 			"error": err,
 		}, "An error occurred while redis.Expire in biz.RenewalToken, but skipped.")
 	}
+	b.touchSession(ctx, token)
 }
 
 // VerifyToken 验证Token是否有效
 func (b *Biz) VerifyToken(ctx context.Context, token string) bool {
+	if isAccessToken(token) {
+		tc, err := b.getAccessTokenContent(ctx, token)
+		return err == nil && tc != nil
+	}
 	return b.redis.Exist(ctx, genTokenKey(token))
 }
 
@@ -82,6 +219,10 @@ func (b *Biz) GetTokenContent(ctx context.Context, token string) (tc *dto.TokenC
 	}, "biz.GetTokenContent called.")
 	defer b.logger.Info("biz.GetTokenContent end.")
 
+	if isAccessToken(token) {
+		return b.getAccessTokenContent(ctx, token)
+	}
+
 	if !b.redis.Exist(ctx, genTokenKey(token)) {
 		b.logger.WarnWithFields(logger.Fields{
 			"token": token,
@@ -109,6 +250,19 @@ func (b *Biz) GetTokenContent(ctx context.Context, token string) (tc *dto.TokenC
 	return tc, nil
 }
 
+// isMfaToken Token是否通过了两步验证，Token不存在时返回false
+func (b *Biz) isMfaToken(ctx context.Context, token string) bool {
+	tcStr, err := b.redis.Get(ctx, genTokenKey(token))
+	if err != nil {
+		return false
+	}
+	tc := struct {
+		Mfa bool `json:"mfa"`
+	}{}
+	_ = json.Unmarshal([]byte(tcStr), &tc)
+	return tc.Mfa
+}
+
 // genTokenContent 生成TokenContent
 func (b *Biz) genTokenContent(ctx context.Context, userObj *model.User) *dto.TokenContent {
 	b.logger.Info("biz.writeTokenContent called.")
@@ -199,6 +353,29 @@ func (b *Biz) genTokenContent(ctx context.Context, userObj *model.User) *dto.Tok
 		}
 	}(wg)
 
+	// 填入权限信息
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		b.logger.InfoWithFields(logger.Fields{"user_id": userObj.Id}, "Loading user permissions.")
+		rps, err := b.dao.ListUsersPermissions(ctx, userObj.Id)
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"user_id": userObj.Id,
+			}, "An error occurred while dao.ListUsersPermissions in biz.GetTokenContent.")
+		}
+
+		tc.Permissions = make([]string, 0, len(rps))
+		for _, rp := range rps {
+			if rp.ProductId != nil {
+				tc.Permissions = append(tc.Permissions, perm.ProductPerm(rp.Permission, *rp.ProductId))
+				continue
+			}
+			tc.Permissions = append(tc.Permissions, rp.Permission)
+		}
+	}(wg)
+
 	wg.Add(1)
 	go func(wg *sync.WaitGroup) {
 		defer wg.Done()
@@ -221,3 +398,8 @@ func (b *Biz) genTokenContent(ctx context.Context, userObj *model.User) *dto.Tok
 func genTokenKey(token string) string {
 	return "token/" + token
 }
+
+// genUserTokensKey 生成用户Token索引的Key，值为该用户所有Token的集合
+func genUserTokensKey(userId uint32) string {
+	return fmt.Sprintf("user_tokens/%d", userId)
+}
@@ -0,0 +1,483 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"eago/auth/dto"
+	"eago/auth/model"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/totp"
+	"eago/common/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// mfaChallengeTtl 第一步认证通过后完成两步验证的有效期
+	mfaChallengeTtl = 5 * time.Minute
+	// mfaMaxAttempts 每次两步验证请求允许的最大尝试次数
+	mfaMaxAttempts = 5
+	// mfaUserMaxFailures 同一用户在mfaUserLockout内允许的两步验证失败次数，跨请求累计，超过后锁定
+	mfaUserMaxFailures = 10
+	// mfaUserLockout 两步验证失败次数的统计窗口及锁定时长
+	mfaUserLockout = 15 * time.Minute
+	// totpSkew 允许前后各一个周期的时钟偏差
+	totpSkew = 1
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+// mfaChallenge 第一步认证通过后保存的两步验证请求
+type mfaChallenge struct {
+	UserId      uint32 `json:"user_id"`
+	LoginMethod string `json:"login_method"`
+	Ip          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+}
+
+// NeedMfa 用户登录是否需要两步验证，被要求启用但尚未绑定的用户需在登录时先绑定
+func (b *Biz) NeedMfa(userObj *model.User) bool {
+	return userObj.TotpEnabled || userObj.TotpRequired
+}
+
+// EnrollTotp 生成待绑定的两步验证密钥，需调用ConfirmTotp确认后才启用
+func (b *Biz) EnrollTotp(ctx context.Context, userId uint32) (*dto.TotpEnrollment, error) {
+	user, err := b.getTotpUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, dto.ErrTotpAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = b.dao.SetUserTotpSecret(ctx, userId, secret); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.SetUserTotpSecret in biz.EnrollTotp.")
+		return nil, err
+	}
+
+	return &dto.TotpEnrollment{
+		Secret: secret,
+		Uri:    totp.URI(b.conf.MfaIssuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTotp 使用验证码确认绑定并启用两步验证，返回恢复码明文，恢复码只在此时返回
+func (b *Biz) ConfirmTotp(ctx context.Context, userId uint32, code string) ([]string, error) {
+	user, err := b.getTotpUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, dto.ErrTotpAlreadyEnabled
+	}
+	if user.TotpSecret == "" {
+		return nil, dto.ErrTotpNotEnrolled
+	}
+	if !b.validateTotpCode(ctx, user, code) {
+		return nil, dto.ErrTotpCodeInvalid
+	}
+
+	codes, hashes, err := b.genRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = b.dao.EnableUserTotp(ctx, userId, hashes); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.EnableUserTotp in biz.ConfirmTotp.")
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTotp 停用两步验证，管理员要求必须启用时不允许停用
+// proof不为nil时需校验当前验证码或恢复码，用户停用自己的两步验证时使用
+func (b *Biz) DisableTotp(ctx context.Context, userId uint32, proof *dto.TotpProof) error {
+	user, err := b.getTotpUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.TotpRequired {
+		return dto.ErrTotpRequired
+	}
+	// 未启用时只清除待绑定的密钥，没有可校验的验证码
+	if proof != nil && user.TotpEnabled {
+		if err = b.verifyTotpProof(ctx, user, proof); err != nil {
+			return err
+		}
+	}
+
+	if err = b.dao.DisableUserTotp(ctx, userId); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.DisableUserTotp in biz.DisableTotp.")
+		return err
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有恢复码全部失效
+// proof不为nil时需校验当前验证码或恢复码，用户重新生成自己的恢复码时使用
+func (b *Biz) RegenerateRecoveryCodes(ctx context.Context, userId uint32, proof *dto.TotpProof) ([]string, error) {
+	user, err := b.getTotpUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, dto.ErrTotpNotEnrolled
+	}
+	if proof != nil {
+		if err = b.verifyTotpProof(ctx, user, proof); err != nil {
+			return nil, err
+		}
+	}
+
+	codes, hashes, err := b.genRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = b.dao.SetUserRecoveryCodes(ctx, userId, hashes); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.SetUserRecoveryCodes in biz.RegenerateRecoveryCodes.")
+		return nil, err
+	}
+	return codes, nil
+}
+
+// NewMfaChallenge 第一步认证通过后生成两步验证请求
+func (b *Biz) NewMfaChallenge(
+	ctx context.Context, userObj *model.User, info *dto.SessionInfo,
+) (*dto.MfaChallenge, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	challengeId := hex.EncodeToString(buf)
+
+	ch := &mfaChallenge{UserId: userObj.Id}
+	if info != nil {
+		ch.LoginMethod = info.LoginMethod
+		ch.Ip = info.Ip
+		ch.UserAgent = info.UserAgent
+	}
+	content, _ := json.Marshal(ch)
+	if err := b.redis.Set(ctx, genMfaChallengeKey(challengeId), string(content), mfaChallengeTtl); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userObj.Id,
+			"error":   err,
+		}, "An error occurred while redis.Set in biz.NewMfaChallenge.")
+		return nil, err
+	}
+	return &dto.MfaChallenge{MfaToken: challengeId, EnrollRequired: !userObj.TotpEnabled}, nil
+}
+
+// VerifyMfaChallenge 使用验证码或恢复码完成两步验证，通过后返回用户及会话信息，用于生成Token
+func (b *Biz) VerifyMfaChallenge(
+	ctx context.Context, challengeId, code, recoveryCode string,
+) (*model.User, *dto.SessionInfo, error) {
+	ch, user, err := b.attemptMfaChallenge(ctx, challengeId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, nil, dto.ErrTotpNotEnrolled
+	}
+
+	ok := false
+	if recoveryCode != "" {
+		ok, err = b.dao.UseUserRecoveryCode(ctx, user.Id, b.hashRecoveryCode(recoveryCode))
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"user_id": user.Id,
+				"error":   err,
+			}, "An error occurred while dao.UseUserRecoveryCode in biz.VerifyMfaChallenge.")
+			return nil, nil, err
+		}
+	} else {
+		ok = b.validateTotpCode(ctx, user, code)
+	}
+	if !ok {
+		return nil, nil, dto.ErrTotpCodeInvalid
+	}
+
+	return user, b.finishMfaChallenge(ctx, challengeId, ch), nil
+}
+
+// EnrollMfaChallenge 被要求启用两步验证但尚未绑定的用户，在登录时使用两步验证请求生成待绑定的密钥
+func (b *Biz) EnrollMfaChallenge(ctx context.Context, challengeId string) (*dto.TotpEnrollment, error) {
+	_, user, err := b.getMfaChallenge(ctx, challengeId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpRequired {
+		return nil, dto.ErrMfaChallengeInvalid
+	}
+
+	return b.EnrollTotp(ctx, user.Id)
+}
+
+// ConfirmMfaChallenge 使用验证码确认登录时绑定的两步验证，通过后返回用户、会话信息及恢复码
+func (b *Biz) ConfirmMfaChallenge(
+	ctx context.Context, challengeId, code string,
+) (*model.User, *dto.SessionInfo, []string, error) {
+	ch, user, err := b.attemptMfaChallenge(ctx, challengeId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !user.TotpRequired {
+		return nil, nil, nil, dto.ErrMfaChallengeInvalid
+	}
+
+	codes, err := b.ConfirmTotp(ctx, user.Id, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, b.finishMfaChallenge(ctx, challengeId, ch), codes, nil
+}
+
+// getMfaChallenge 获得两步验证请求及其用户，用户已被禁用时作废该请求
+func (b *Biz) getMfaChallenge(ctx context.Context, challengeId string) (*mfaChallenge, *model.User, error) {
+	key := genMfaChallengeKey(challengeId)
+	if challengeId == "" || !b.redis.Exist(ctx, key) {
+		return nil, nil, dto.ErrMfaChallengeInvalid
+	}
+	content, err := b.redis.Get(ctx, key)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while redis.Get in biz.getMfaChallenge.")
+		return nil, nil, err
+	}
+	ch := new(mfaChallenge)
+	if err = json.Unmarshal([]byte(content), ch); err != nil {
+		return nil, nil, dto.ErrMfaChallengeInvalid
+	}
+
+	user, err := b.getTotpUser(ctx, ch.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Disabled {
+		_ = b.redis.Del(ctx, key)
+		return nil, nil, dto.ErrLoginDisabledUser
+	}
+	return ch, user, nil
+}
+
+// attemptMfaChallenge 获得两步验证请求，并在校验验证码前原子地累计尝试次数
+// 每个请求最多尝试mfaMaxAttempts次，超过后作废该请求，需重新进行第一步认证
+// 同一用户跨请求累计的失败次数超过mfaUserMaxFailures时锁定，成功完成两步验证后清除
+func (b *Biz) attemptMfaChallenge(ctx context.Context, challengeId string) (*mfaChallenge, *model.User, error) {
+	ch, user, err := b.getMfaChallenge(ctx, challengeId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attempts, err := b.incrWithTtl(ctx, genMfaAttemptsKey(challengeId), mfaChallengeTtl)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while biz.incrWithTtl in biz.attemptMfaChallenge.")
+		return nil, nil, err
+	}
+	if attempts > mfaMaxAttempts {
+		_ = b.redis.Del(ctx, genMfaChallengeKey(challengeId))
+		return nil, nil, dto.ErrMfaChallengeInvalid
+	}
+
+	failures, err := b.incrWithTtl(ctx, genMfaFailuresKey(user.Id), mfaUserLockout)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while biz.incrWithTtl in biz.attemptMfaChallenge.")
+		return nil, nil, err
+	}
+	if failures > mfaUserMaxFailures {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id":  user.Id,
+			"username": user.Username,
+			"failures": failures,
+		}, "Too many mfa failures, user locked.")
+		return nil, nil, dto.ErrLoginUserLocked
+	}
+
+	return ch, user, nil
+}
+
+// finishMfaChallenge 两步验证通过，作废请求并清除失败次数，返回用于生成Token的会话信息
+func (b *Biz) finishMfaChallenge(ctx context.Context, challengeId string, ch *mfaChallenge) *dto.SessionInfo {
+	// 每个请求只能使用一次
+	for _, key := range []string{
+		genMfaChallengeKey(challengeId), genMfaAttemptsKey(challengeId), genMfaFailuresKey(ch.UserId),
+	} {
+		if err := b.redis.Del(ctx, key); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"key":   key,
+				"error": err,
+			}, "An error occurred while redis.Del in biz.finishMfaChallenge, but skipped.")
+		}
+	}
+
+	return &dto.SessionInfo{
+		LoginMethod: ch.LoginMethod,
+		Ip:          ch.Ip,
+		UserAgent:   ch.UserAgent,
+		Mfa:         true,
+	}
+}
+
+// verifyTotpProof 校验用户提供的验证码或恢复码，失败次数与两步验证登录共同累计，超过mfaUserMaxFailures时锁定
+func (b *Biz) verifyTotpProof(ctx context.Context, user *model.User, proof *dto.TotpProof) error {
+	failures, err := b.incrWithTtl(ctx, genMfaFailuresKey(user.Id), mfaUserLockout)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while biz.incrWithTtl in biz.verifyTotpProof.")
+		return err
+	}
+	if failures > mfaUserMaxFailures {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id":  user.Id,
+			"username": user.Username,
+			"failures": failures,
+		}, "Too many mfa failures, user locked.")
+		return dto.ErrLoginUserLocked
+	}
+
+	ok := false
+	if proof.RecoveryCode != "" {
+		ok, err = b.dao.UseUserRecoveryCode(ctx, user.Id, b.hashRecoveryCode(proof.RecoveryCode))
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"user_id": user.Id,
+				"error":   err,
+			}, "An error occurred while dao.UseUserRecoveryCode in biz.verifyTotpProof.")
+			return err
+		}
+	} else {
+		ok = b.validateTotpCode(ctx, user, proof.Code)
+	}
+	if !ok {
+		return dto.ErrTotpCodeInvalid
+	}
+
+	if err = b.redis.Del(ctx, genMfaFailuresKey(user.Id)); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while redis.Del in biz.verifyTotpProof, but skipped.")
+	}
+	return nil
+}
+
+// incrWithTtl 计数加1，首次计数时设置过期时间
+func (b *Biz) incrWithTtl(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	n, err := b.redis.Incr(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		if err = b.redis.Expire(ctx, key, ttl); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// getTotpUser 获得用户，用户不存在时返回dto.ErrTotpNotEnrolled
+func (b *Biz) getTotpUser(ctx context.Context, userId uint32) (*model.User, error) {
+	user, err := b.dao.GetUser(ctx, orm.Query{"id=?": userId})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"user_id": userId,
+			"error":   err,
+		}, "An error occurred while dao.GetUser in biz.getTotpUser.")
+		return nil, err
+	}
+	if user == nil || user.Id == 0 {
+		return nil, dto.ErrTotpNotEnrolled
+	}
+	return user, nil
+}
+
+// validateTotpCode 校验验证码，同一验证码只能使用一次
+func (b *Biz) validateTotpCode(ctx context.Context, user *model.User, code string) bool {
+	counter, ok := totp.Validate(user.TotpSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return false
+	}
+
+	// 记录已使用的验证码，有效期覆盖允许的时钟偏差范围
+	ttl := time.Duration(2*totpSkew+1) * totp.Period
+	fresh, err := b.redis.SetNX(ctx, genTotpUsedKey(user.Id, counter), 1, ttl)
+	if err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"user_id": user.Id,
+			"error":   err,
+		}, "An error occurred while redis.SetNX in biz.validateTotpCode.")
+		return false
+	}
+	return fresh
+}
+
+// genRecoveryCodes 生成恢复码明文及其哈希
+func (b *Biz) genRecoveryCodes() (codes []string, hashes []string, err error) {
+	codes = make([]string, 0, recoveryCodeCount)
+	hashes = make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err = rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		h := hex.EncodeToString(buf)
+		code := h[:5] + "-" + h[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, b.hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 计算恢复码哈希，忽略大小写及空格
+func (b *Biz) hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return utils.GenSha256HashCode(code + b.conf.SecretKey)
+}
+
+// genMfaChallengeKey 生成两步验证请求的Key
+func genMfaChallengeKey(challengeId string) string {
+	return "mfa_challenge/" + challengeId
+}
+
+// genMfaAttemptsKey 生成两步验证请求尝试次数的Key
+func genMfaAttemptsKey(challengeId string) string {
+	return "mfa_challenge/" + challengeId + "/attempts"
+}
+
+// genMfaFailuresKey 生成用户两步验证失败次数的Key
+func genMfaFailuresKey(userId uint32) string {
+	return fmt.Sprintf("mfa_failures/%d", userId)
+}
+
+// genTotpUsedKey 生成已使用验证码的Key
+func genTotpUsedKey(userId uint32, counter int64) string {
+	return fmt.Sprintf("totp_used/%d/%d", userId, counter)
+}
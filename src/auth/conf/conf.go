@@ -4,6 +4,7 @@ import (
 	"eago/common/global"
 	"fmt"
 	"github.com/Unknwon/goconfig"
+	"strings"
 	"time"
 )
 
@@ -13,7 +14,10 @@ type Conf struct {
 
 	SrvListen             string
 	ApiListen             string
+	ApiMetricsListen      string
+	SrvMetricsListen      string
 	GinMode               string
+	TrustedProxies        []string
 	MicroRegisterTtl      time.Duration
 	MicroRegisterInterval time.Duration
 	WorkerRegisterTtl     int64
@@ -27,6 +31,31 @@ type Conf struct {
 	EtcdUsername  string
 	EtcdPassword  string
 
+	// LoginProviders 启用的登录方式，用户名密码登录按顺序依次尝试
+	LoginProviders []string
+
+	// MfaIssuer 两步验证中显示的发行方名称
+	MfaIssuer string
+	// SensitiveRequireMfa 敏感操作是否要求Token通过了两步验证
+	SensitiveRequireMfa bool
+
+	// 密码策略配置
+	PasswordBcryptCost      int
+	PasswordMinLength       int
+	PasswordMinClasses      int
+	PasswordHistory         int
+	PasswordMaxFailures     int
+	PasswordLockoutDuration time.Duration
+
+	// 登录限流配置，按IP及用户名分别统计失败次数
+	LoginRateWindow     time.Duration
+	LoginRateIpLimit    int
+	LoginRateUserLimit  int
+	LoginRateLockout    time.Duration
+	LoginRateDelayAfter int
+	LoginRateDelayBase  time.Duration
+	LoginRateDelayMax   time.Duration
+
 	CrowdAddress     string
 	CrowdAppName     string
 	CrowdAppPassword string
@@ -51,6 +80,18 @@ type Conf struct {
 	WeworkCorpId     string
 	WeworkCorpSecret string
 
+	// OIDC配置
+	OidcIssuer        string
+	OidcClientId      string
+	OidcClientSecret  string
+	OidcRedirectUrl   string
+	OidcScopes        []string
+	OidcUsernameClaim string
+	OidcEmailClaim    string
+	OidcRolesClaim    string
+	OidcGroupsClaim   string
+	OidcFrontendUrl   string
+
 	// Eagle配置
 	EagleAddress  string
 	EagleDbName   string
@@ -70,9 +111,12 @@ func NewConfig(options ...Option) *Conf {
 	return &Conf{
 		Const: newConstConf(),
 
-		SrvListen: cfg.MustValue("main", "srv_listen", defaultSrvListen),
-		ApiListen: cfg.MustValue("main", "api_listen", defaultApiListen),
-		GinMode:   cfg.MustValue("main", "gin_mode", defaultGinModel),
+		SrvListen:        cfg.MustValue("main", "srv_listen", defaultSrvListen),
+		ApiListen:        cfg.MustValue("main", "api_listen", defaultApiListen),
+		ApiMetricsListen: cfg.MustValue("main", "api_metrics_listen", defaultApiMetricsListen),
+		SrvMetricsListen: cfg.MustValue("main", "srv_metrics_listen", defaultSrvMetricsListen),
+		GinMode:          cfg.MustValue("main", "gin_mode", defaultGinModel),
+		TrustedProxies:   cfg.MustValueArray("main", "trusted_proxies", global.DefaultConfigSeparator),
 		MicroRegisterTtl: time.Duration(cfg.MustInt(
 			"main", "register_ttl", defaultMicroRegisterTtl,
 		)) * time.Second,
@@ -91,6 +135,36 @@ func NewConfig(options ...Option) *Conf {
 		EtcdUsername:  cfg.MustValue("etcd", "username", defaultEtcdUsername),
 		EtcdPassword:  cfg.MustValue("etcd", "password", defaultEtcdPassword),
 
+		LoginProviders: splitConfigValue(cfg.MustValue("login", "providers", defaultLoginProviders)),
+
+		MfaIssuer:           cfg.MustValue("login", "mfa_issuer", defaultMfaIssuer),
+		SensitiveRequireMfa: cfg.MustBool("login", "sensitive_require_mfa", defaultSensitiveRequireMfa),
+
+		PasswordBcryptCost:  cfg.MustInt("password", "bcrypt_cost", defaultPasswordBcryptCost),
+		PasswordMinLength:   cfg.MustInt("password", "min_length", defaultPasswordMinLength),
+		PasswordMinClasses:  cfg.MustInt("password", "min_classes", defaultPasswordMinClasses),
+		PasswordHistory:     cfg.MustInt("password", "history", defaultPasswordHistory),
+		PasswordMaxFailures: cfg.MustInt("password", "max_failures", defaultPasswordMaxFailures),
+		PasswordLockoutDuration: time.Duration(cfg.MustInt64(
+			"password", "lockout_duration", defaultPasswordLockoutDuration,
+		)) * time.Second,
+
+		LoginRateWindow: time.Duration(cfg.MustInt64(
+			"ratelimit", "login_window", defaultLoginRateWindow,
+		)) * time.Second,
+		LoginRateIpLimit:   cfg.MustInt("ratelimit", "login_ip_limit", defaultLoginRateIpLimit),
+		LoginRateUserLimit: cfg.MustInt("ratelimit", "login_user_limit", defaultLoginRateUserLimit),
+		LoginRateLockout: time.Duration(cfg.MustInt64(
+			"ratelimit", "login_lockout", defaultLoginRateLockout,
+		)) * time.Second,
+		LoginRateDelayAfter: cfg.MustInt("ratelimit", "login_delay_after", defaultLoginRateDelayAfter),
+		LoginRateDelayBase: time.Duration(cfg.MustInt64(
+			"ratelimit", "login_delay_base", defaultLoginRateDelayBase,
+		)) * time.Second,
+		LoginRateDelayMax: time.Duration(cfg.MustInt64(
+			"ratelimit", "login_delay_max", defaultLoginRateDelayMax,
+		)) * time.Second,
+
 		CrowdAddress:     cfg.MustValue("crowd", "address", defaultCrowdAddress),
 		CrowdAppName:     cfg.MustValue("crowd", "app_name", defaultCrowdAppName),
 		CrowdAppPassword: cfg.MustValue("crowd", "app_pass", defaultCrowdAppPassword),
@@ -115,6 +189,18 @@ func NewConfig(options ...Option) *Conf {
 		WeworkCorpId:     cfg.MustValue("wework", "corp_id"),
 		WeworkCorpSecret: cfg.MustValue("wework", "corp_secret"),
 
+		// OIDC配置
+		OidcIssuer:        cfg.MustValue("oidc", "issuer"),
+		OidcClientId:      cfg.MustValue("oidc", "client_id"),
+		OidcClientSecret:  cfg.MustValue("oidc", "client_secret"),
+		OidcRedirectUrl:   cfg.MustValue("oidc", "redirect_url"),
+		OidcScopes:        splitConfigValue(cfg.MustValue("oidc", "scopes", defaultOidcScopes)),
+		OidcUsernameClaim: cfg.MustValue("oidc", "username_claim", defaultOidcUsernameClaim),
+		OidcEmailClaim:    cfg.MustValue("oidc", "email_claim", defaultOidcEmailClaim),
+		OidcRolesClaim:    cfg.MustValue("oidc", "roles_claim"),
+		OidcGroupsClaim:   cfg.MustValue("oidc", "groups_claim"),
+		OidcFrontendUrl:   cfg.MustValue("oidc", "frontend_url"),
+
 		// Eagle配置
 		EagleAddress:  cfg.MustValue("eagle", "address"),
 		EagleDbName:   cfg.MustValue("eagle", "db_name"),
@@ -122,3 +208,24 @@ func NewConfig(options ...Option) *Conf {
 		EaglePassword: cfg.MustValue("eagle", "password"),
 	}
 }
+
+// IsLoginProviderEnabled 登录方式是否启用
+func (c *Conf) IsLoginProviderEnabled(name string) bool {
+	for _, p := range c.LoginProviders {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitConfigValue 按分隔符拆分配置项，忽略空值
+func splitConfigValue(v string) []string {
+	res := make([]string, 0)
+	for _, item := range strings.Split(v, global.DefaultConfigSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
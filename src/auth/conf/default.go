@@ -3,6 +3,8 @@ package conf
 const (
 	defaultSrvListen             = "127.0.0.1:0"
 	defaultApiListen             = "127.0.0.1:0"
+	defaultApiMetricsListen      = ""
+	defaultSrvMetricsListen      = ""
 	defaultGinModel              = "release"
 	defaultMicroRegisterTtl      = 10
 	defaultMicroRegisterInterval = 3
@@ -17,6 +19,28 @@ const (
 	defaultEtcdUsername = ""
 	defaultEtcdPassword = ""
 
+	// 登录方式默认配置
+	defaultLoginProviders      = "crowd,database,eagle"
+	defaultMfaIssuer           = "eago"
+	defaultSensitiveRequireMfa = false
+
+	// 密码策略默认配置
+	defaultPasswordBcryptCost      = 10
+	defaultPasswordMinLength       = 8
+	defaultPasswordMinClasses      = 3
+	defaultPasswordHistory         = 5
+	defaultPasswordMaxFailures     = 5
+	defaultPasswordLockoutDuration = 900
+
+	// 登录限流默认配置
+	defaultLoginRateWindow     = 900
+	defaultLoginRateIpLimit    = 50
+	defaultLoginRateUserLimit  = 10
+	defaultLoginRateLockout    = 900
+	defaultLoginRateDelayAfter = 3
+	defaultLoginRateDelayBase  = 1
+	defaultLoginRateDelayMax   = 30
+
 	// Crowd默认配置
 	defaultCrowdAddress     = "https://127.0.0.1/crowd"
 	defaultCrowdAppName     = "eago"
@@ -35,6 +59,11 @@ const (
 	defaultRedisPassword = ""
 	defaultRedisDb       = 1
 
+	// OIDC默认配置
+	defaultOidcScopes        = "openid,profile,email"
+	defaultOidcUsernameClaim = "preferred_username"
+	defaultOidcEmailClaim    = "email"
+
 	// Jaeger默认配置
 	defaultJaegerAddress = "127.0.0.1:5775"
 )
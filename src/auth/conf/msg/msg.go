@@ -6,13 +6,22 @@ import (
 
 var (
 	// Login 1000xx
-	MsgLoginInactiveCrowdUserFailed = cMsg.NewCodeMsg(100001, "登录失败，当前用户在Crowd中是禁用状态")
-	MsgLoginDisabledUserFailed      = cMsg.NewCodeMsg(100002, "登录失败，用户处于禁用状态")
-	MsgLoginNoPasswordUserFailed    = cMsg.NewCodeMsg(100003, "登录失败，当前用户没有设置密码")
-	MsgLoginAuthenticationFailed    = cMsg.NewCodeMsg(100004, "登录失败，请检查用户名密码是否正确")
-	MsgLoginNewTokenFailed          = cMsg.NewCodeMsg(100005, "创建Token失败，请联系管理员")
-	MsgGetTokenContentFailed        = cMsg.NewCodeMsg(100006, "获取TokenContent失败")
-	MsgLoginUnknownFailed           = cMsg.NewCodeMsg(100099, "登录失败，请联系管理员")
+	MsgLoginInactiveCrowdUserFailed  = cMsg.NewCodeMsg(100001, "登录失败，当前用户在Crowd中是禁用状态")
+	MsgLoginDisabledUserFailed       = cMsg.NewCodeMsg(100002, "登录失败，用户处于禁用状态")
+	MsgLoginNoPasswordUserFailed     = cMsg.NewCodeMsg(100003, "登录失败，当前用户没有设置密码")
+	MsgLoginAuthenticationFailed     = cMsg.NewCodeMsg(100004, "登录失败，请检查用户名密码是否正确")
+	MsgLoginNewTokenFailed           = cMsg.NewCodeMsg(100005, "创建Token失败，请联系管理员")
+	MsgGetTokenContentFailed         = cMsg.NewCodeMsg(100006, "获取TokenContent失败")
+	MsgLoginProviderDisabledFailed   = cMsg.NewCodeMsg(100007, "登录失败，未启用该登录方式")
+	MsgLoginStateInvalidFailed       = cMsg.NewCodeMsg(100008, "登录失败，登录请求已过期，请重新登录")
+	MsgLoginServiceAccountFailed     = cMsg.NewCodeMsg(100009, "登录失败，服务账号只能使用访问令牌")
+	MsgLoginUserLockedFailed         = cMsg.NewCodeMsg(100010, "登录失败，连续登录失败次数过多，账号已被临时锁定")
+	MsgLoginPasswordMustChangeFailed = cMsg.NewCodeMsg(100011, "登录失败，密码已被管理员重置，请先修改密码")
+	MsgLoginMfaInvalidFailed         = cMsg.NewCodeMsg(100012, "登录失败，两步验证请求已过期，请重新登录")
+	MsgLoginTotpCodeFailed           = cMsg.NewCodeMsg(100013, "登录失败，验证码或恢复码不正确")
+	MsgLoginIdentityNotLinkedFailed  = cMsg.NewCodeMsg(100014, "登录失败，已存在同名的本地用户，请使用原方式登录后关联该外部账号")
+	MsgLoginIdentityLinkedFailed     = cMsg.NewCodeMsg(100015, "关联失败，该外部账号已关联其他用户")
+	MsgLoginUnknownFailed            = cMsg.NewCodeMsg(100099, "登录失败，请联系管理员")
 
 	// Department 1001xx
 	MsgAssociatedDepartmentFailed     = cMsg.NewCodeMsg(100115, "无法执行操作，仍有子部门与该部门关联")
@@ -28,7 +37,27 @@ var (
 	MsgAssociatedRoleFailed = cMsg.NewCodeMsg(100420, "无法执行操作，仍有用户与该角色关联")
 
 	// User 1005xx
-	MsgUserHandoverFailed = cMsg.NewCodeMsg(100500, "用户交接操作失败")
+	MsgUserHandoverFailed    = cMsg.NewCodeMsg(100500, "用户交接操作失败")
+	MsgSessionNotFoundFailed = cMsg.NewCodeMsg(100501, "会话不存在或已过期")
+
+	// AccessToken 1006xx
+	MsgAccessTokenNotFoundFailed = cMsg.NewCodeMsg(100601, "访问令牌不存在")
+	MsgAccessTokenUserFailed     = cMsg.NewCodeMsg(100602, "无权限，只能为自己或服务账号创建访问令牌")
+	MsgAccessTokenByTokenFailed  = cMsg.NewCodeMsg(100603, "无权限，不能使用访问令牌创建访问令牌")
+
+	// Password 1007xx
+	MsgPasswordPolicyFailed    = cMsg.NewCodeMsg(100701, "修改密码失败，新密码不符合密码策略")
+	MsgPasswordReusedFailed    = cMsg.NewCodeMsg(100702, "修改密码失败，不能使用最近使用过的密码")
+	MsgPasswordIncorrectFailed = cMsg.NewCodeMsg(100703, "修改密码失败，用户名或原密码不正确")
+	MsgPasswordUnknownFailed   = cMsg.NewCodeMsg(100799, "修改密码失败，请联系管理员")
+
+	// Totp 1008xx
+	MsgTotpAlreadyEnabledFailed = cMsg.NewCodeMsg(100801, "操作失败，两步验证已启用，请先停用")
+	MsgTotpNotEnrolledFailed    = cMsg.NewCodeMsg(100802, "操作失败，未绑定或未启用两步验证")
+	MsgTotpCodeInvalidFailed    = cMsg.NewCodeMsg(100803, "操作失败，验证码或恢复码不正确")
+	MsgTotpRequiredFailed       = cMsg.NewCodeMsg(100804, "操作失败，管理员要求该用户必须启用两步验证")
+	MsgTotpUserLockedFailed     = cMsg.NewCodeMsg(100805, "操作失败，两步验证失败次数过多，账号已被临时锁定")
+	MsgTotpUnknownFailed        = cMsg.NewCodeMsg(100899, "两步验证操作失败，请联系管理员")
 
 	// Others
 	MsgAuthDaoErr   = cMsg.NewCodeMsg(109900, "Auth服务的DAO层发生意外，请先尝试重试，若无效请联系管理员")
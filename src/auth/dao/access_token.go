@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"eago/auth/model"
+	"eago/common/orm"
+	"eago/common/utils"
+	"time"
+)
+
+// NewAccessToken 新建访问令牌，expiresAt为nil时永不过期
+func (d *Dao) NewAccessToken(
+	ctx context.Context,
+	userId uint32, name, tokenHash, tokenPrefix string, scopes []string, expiresAt *time.Time, createdBy string,
+) (*model.AccessToken, error) {
+	at := &model.AccessToken{
+		UserId:      userId,
+		Name:        name,
+		TokenHash:   tokenHash,
+		TokenPrefix: tokenPrefix,
+		Scopes:      scopes,
+		CreatedBy:   createdBy,
+		CreatedAt:   &utils.CustomTime{Time: time.Now()},
+	}
+	if expiresAt != nil {
+		at.ExpiresAt = &utils.CustomTime{Time: *expiresAt}
+	}
+
+	res := d.getDbWithCtx(ctx).Create(&at)
+	return at, res.Error
+}
+
+// RemoveAccessToken 删除用户的指定访问令牌，返回是否删除成功
+func (d *Dao) RemoveAccessToken(ctx context.Context, userId, id uint32) (bool, error) {
+	res := d.getDbWithCtx(ctx).Delete(model.AccessToken{}, "id=? AND user_id=?", id, userId)
+	return res.RowsAffected > 0, res.Error
+}
+
+// SetAccessTokenLastUsed 更新访问令牌最近使用时间，自动更新为当前时间
+func (d *Dao) SetAccessTokenLastUsed(ctx context.Context, id uint32) error {
+	res := d.getDbWithCtx(ctx).Model(&model.AccessToken{}).
+		Where("id=?", id).
+		Update("last_used_at", &utils.CustomTime{Time: time.Now()})
+	return res.Error
+}
+
+// GetAccessToken 查询单个访问令牌
+func (d *Dao) GetAccessToken(ctx context.Context, q orm.Query) (at *model.AccessToken, err error) {
+	res := q.Where(d.getDbWithCtx(ctx)).Limit(1).Find(&at)
+	return at, res.Error
+}
+
+// ListAccessTokens 查询访问令牌
+func (d *Dao) ListAccessTokens(ctx context.Context, q orm.Query) (ats []*model.AccessToken, err error) {
+	res := q.Where(d.getDbWithCtx(ctx)).Find(&ats)
+	return ats, res.Error
+}
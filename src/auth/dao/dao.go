@@ -6,9 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// UsersChangedHook 用户权限相关数据变更后的回调
+type UsersChangedHook func(ctx context.Context, userIds ...uint32)
+
 type Dao struct {
 	db *gorm.DB
 	lg *logger.Logger
+
+	usersChanged UsersChangedHook
 }
 
 func NewDao(d *gorm.DB, lg *logger.Logger) *Dao {
@@ -29,6 +34,31 @@ func (d *Dao) Close() {
 	}
 }
 
+// SetUsersChangedHook 设置用户权限相关数据变更后的回调，用于刷新用户Token内容
+func (d *Dao) SetUsersChangedHook(hook UsersChangedHook) {
+	d.usersChanged = hook
+}
+
+// notifyUsersChanged 通知用户的角色、产品线、组、部门等信息已变更
+func (d *Dao) notifyUsersChanged(ctx context.Context, userIds ...uint32) {
+	if d.usersChanged == nil || len(userIds) == 0 {
+		return
+	}
+	d.usersChanged(ctx, userIds...)
+}
+
+// listJoinedUserIds 关联表操作::列出关联表中符合条件的用户ID
+func (d *Dao) listJoinedUserIds(ctx context.Context, joinModel interface{}, query string, args ...interface{}) []uint32 {
+	ids := make([]uint32, 0)
+	res := d.getDbWithCtx(ctx).Model(joinModel).Where(query, args...).Distinct().Pluck("user_id", &ids)
+	if res.Error != nil {
+		d.lg.WarnWithFields(logger.Fields{
+			"error": res.Error,
+		}, "An error occurred while listing joined user ids in dao.listJoinedUserIds, skipped it.")
+	}
+	return ids
+}
+
 func (d *Dao) getDb() *gorm.DB {
 	return d.db
 }
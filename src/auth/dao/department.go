@@ -27,6 +27,8 @@ func (d *Dao) RemoveDepartment(ctx context.Context, deptId uint32) error {
 
 // EmptyDepartment 清空部门
 func (d *Dao) EmptyDepartment(ctx context.Context) error {
+	userIds := d.listJoinedUserIds(ctx, &model.UserDepartment{}, "1=1")
+
 	res := d.getDbWithCtx(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model.UserDepartment{})
 	if res.Error != nil {
 		d.lg.WarnWithFields(logger.Fields{
@@ -56,6 +58,7 @@ func (d *Dao) EmptyDepartment(ctx context.Context) error {
 		}, "An error occurred while 'ALTER TABLE departments AUTO_INCREMENT=1' in dao.EmptyDepartment.")
 		return res.Error
 	}
+	d.notifyUsersChanged(ctx, userIds...)
 	return nil
 }
 
@@ -69,6 +72,9 @@ func (d *Dao) SetDepartment(ctx context.Context, id uint32, name string, parentI
 		Where("id=?", id).
 		Updates(updatesMap).
 		Find(&dept)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listDepartmentTreeUserIds(ctx, id)...)
+	}
 	return dept, res.Error
 }
 
@@ -135,18 +141,27 @@ func (d *Dao) AddUser2Department(ctx context.Context, userId, deptId uint32, isO
 		DepartmentId: deptId,
 		IsOwner:      &isOwner,
 	})
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveDepartmentsUser 关联表操作::移除指定部门中用户
 func (d *Dao) RemoveDepartmentsUser(ctx context.Context, deptId, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserDepartment{}, "department_id=? AND user_id=?", deptId, userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveUsersDepartments 关联表操作::移除指定用户所有部门
 func (d *Dao) RemoveUsersDepartments(ctx context.Context, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserDepartment{}, "user_id=?", userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
@@ -191,3 +206,63 @@ func (d *Dao) ListDepartmentsUsers(
 		Find(&mbrUser)
 	return mbrUser, res.Error
 }
+
+// NewSyncedDepartment 新建由组织架构同步维护的部门
+func (d *Dao) NewSyncedDepartment(
+	ctx context.Context, name string, parentId *uint32, source, externalId string,
+) (*model.Department, error) {
+	dept := &model.Department{
+		Name:       name,
+		ParentId:   parentId,
+		Source:     &source,
+		ExternalId: &externalId,
+	}
+	res := d.getDbWithCtx(ctx).Create(&dept)
+	return dept, res.Error
+}
+
+// SetSyncedDepartment 按组织架构同步结果更新部门，parentId为nil时设置为根部门
+func (d *Dao) SetSyncedDepartment(
+	ctx context.Context, id uint32, name string, parentId *uint32, source, externalId string,
+) error {
+	res := d.getDbWithCtx(ctx).Model(&model.Department{}).
+		Where("id=?", id).
+		Updates(map[string]interface{}{
+			"name":        name,
+			"parent_id":   parentId,
+			"source":      source,
+			"external_id": externalId,
+		})
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listDepartmentTreeUserIds(ctx, id)...)
+	}
+	return res.Error
+}
+
+// ListUserDepartments 关联表操作::列出用户部门关联
+func (d *Dao) ListUserDepartments(ctx context.Context, q orm.Query) (uds []*model.UserDepartment, err error) {
+	res := q.Where(d.getDbWithCtx(ctx)).Find(&uds)
+	return uds, res.Error
+}
+
+// listDepartmentTreeUserIds 关联表操作::列出指定部门及其所有子部门中的用户ID
+func (d *Dao) listDepartmentTreeUserIds(ctx context.Context, deptId uint32) []uint32 {
+	depts, err := d.ListDepartments(ctx, orm.Query{})
+	if err != nil {
+		d.lg.WarnWithFields(logger.Fields{
+			"department_id": deptId,
+			"error":         err,
+		}, "An error occurred while dao.ListDepartments in dao.listDepartmentTreeUserIds, skipped it.")
+		return nil
+	}
+
+	deptIds := []uint32{deptId}
+	for i := 0; i < len(deptIds); i++ {
+		for _, dept := range depts {
+			if dept.ParentId != nil && *dept.ParentId == deptIds[i] {
+				deptIds = append(deptIds, dept.Id)
+			}
+		}
+	}
+	return d.listJoinedUserIds(ctx, &model.UserDepartment{}, "department_id IN ?", deptIds)
+}
@@ -31,6 +31,9 @@ func (d *Dao) SetGroup(ctx context.Context, id uint32, name, description string)
 			"description": description,
 		}).
 		Limit(1).Find(&g)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listJoinedUserIds(ctx, &model.UserGroup{}, "group_id=?", id)...)
+	}
 	return g, res.Error
 }
 
@@ -74,18 +77,27 @@ func (d *Dao) AddUser2Group(ctx context.Context, groupId, userId uint32, isOwner
 		UserId:  userId,
 		IsOwner: &isOwner,
 	})
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveGroupsUser 关联表操作::移除指定组中指定用户
 func (d *Dao) RemoveGroupsUser(ctx context.Context, groupId, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserGroup{}, "group_id=? AND user_id=?", groupId, userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveUsersGroups 关联表操作::移除指定用户所有组
 func (d *Dao) RemoveUsersGroups(ctx context.Context, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserGroup{}, "user_id=?", userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
@@ -94,6 +106,9 @@ func (d *Dao) SetGroupsOwner(ctx context.Context, groupId, userId uint32, isOwne
 	res := d.getDbWithCtx(ctx).Model(&model.UserGroup{}).
 		Where("group_id=? AND user_id=?", groupId, userId).
 		Update("is_owner", isOwner)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
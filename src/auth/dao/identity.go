@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"context"
+	"eago/auth/model"
+	"eago/common/utils"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// GetUserIdentity 按签发方及唯一标识查询关联的外部身份，不存在时Id为0
+func (d *Dao) GetUserIdentity(ctx context.Context, issuer, subject string) (i *model.UserIdentity, err error) {
+	res := d.getDbWithCtx(ctx).
+		Where("issuer=? AND subject=?", issuer, subject).
+		Limit(1).
+		Find(&i)
+	return i, res.Error
+}
+
+// NewUserIdentity 将外部身份关联至用户，同一外部身份只能关联一个用户
+func (d *Dao) NewUserIdentity(ctx context.Context, userId uint32, provider, issuer, subject string) error {
+	return d.getDbWithCtx(ctx).Create(newUserIdentity(userId, provider, issuer, subject)).Error
+}
+
+// NewUserWithIdentity 新建用户并关联外部身份
+func (d *Dao) NewUserWithIdentity(
+	ctx context.Context, username, email, provider, issuer, subject string,
+) (*model.User, error) {
+	u := &model.User{
+		Username:  strings.ToLower(username),
+		Email:     strings.ToLower(email),
+		LastLogin: &utils.CustomTime{Time: time.Now()},
+	}
+
+	err := d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(u).Error; err != nil {
+			return err
+		}
+		return tx.Create(newUserIdentity(u.Id, provider, issuer, subject)).Error
+	})
+	return u, err
+}
+
+// ListUserIdentities 列出用户关联的外部身份
+func (d *Dao) ListUserIdentities(ctx context.Context, userId uint32) (is []*model.UserIdentity, err error) {
+	res := d.getDbWithCtx(ctx).Where("user_id=?", userId).Order("id").Find(&is)
+	return is, res.Error
+}
+
+// RemoveUserIdentity 取消用户关联的外部身份
+func (d *Dao) RemoveUserIdentity(ctx context.Context, userId, identityId uint32) (bool, error) {
+	res := d.getDbWithCtx(ctx).Delete(model.UserIdentity{}, "id=? AND user_id=?", identityId, userId)
+	return res.RowsAffected > 0, res.Error
+}
+
+func newUserIdentity(userId uint32, provider, issuer, subject string) *model.UserIdentity {
+	return &model.UserIdentity{
+		UserId:    userId,
+		Provider:  provider,
+		Issuer:    issuer,
+		Subject:   subject,
+		CreatedAt: &utils.CustomTime{Time: time.Now()},
+	}
+}
@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"context"
+	"eago/auth/model"
+	"eago/common/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// SetUserPassword 更新用户密码并记录历史密码，同时清除登录失败次数及锁定状态
+// mustChange为true时用户需修改密码后才能登录
+func (d *Dao) SetUserPassword(ctx context.Context, userId uint32, password string, mustChange bool) error {
+	now := &utils.CustomTime{Time: time.Now()}
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).
+			Where("id=?", userId).
+			Updates(map[string]interface{}{
+				"password":             password,
+				"must_change_password": mustChange,
+				"password_changed_at":  now,
+				"failed_login_count":   0,
+				"locked_until":         nil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		return tx.Create(&model.UserPasswordHistory{
+			UserId:    userId,
+			Password:  password,
+			CreatedAt: now,
+		}).Error
+	})
+}
+
+// SetUserPasswordHash 只更新用户密码的哈希值，用于登录成功后升级旧的哈希算法
+func (d *Dao) SetUserPasswordHash(ctx context.Context, userId uint32, password string) error {
+	res := d.getDbWithCtx(ctx).Model(&model.User{}).
+		Where("id=?", userId).
+		Update("password", password)
+	return res.Error
+}
+
+// SetUserLoginFailures 更新用户连续登录失败次数及锁定截止时间，lockedUntil为nil时解除锁定
+func (d *Dao) SetUserLoginFailures(ctx context.Context, userId uint32, count int, lockedUntil *time.Time) error {
+	var locked *utils.CustomTime
+	if lockedUntil != nil {
+		locked = &utils.CustomTime{Time: *lockedUntil}
+	}
+
+	res := d.getDbWithCtx(ctx).Model(&model.User{}).
+		Where("id=?", userId).
+		Updates(map[string]interface{}{
+			"failed_login_count": count,
+			"locked_until":       locked,
+		})
+	return res.Error
+}
+
+// IncrUserLoginFailures 原子地将用户连续登录失败次数加1，达到maxFailures时锁定账号至lockedUntil并重新计数
+// 返回本次是否触发了锁定
+func (d *Dao) IncrUserLoginFailures(
+	ctx context.Context, userId uint32, maxFailures int, lockedUntil time.Time,
+) (bool, error) {
+	locked := false
+	err := d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).
+			Where("id=?", userId).
+			Update("failed_login_count", gorm.Expr("failed_login_count+1"))
+		if res.Error != nil {
+			return res.Error
+		}
+
+		// 更新时已加行锁，重新读取的次数包含了并发的失败
+		user := &model.User{}
+		if res = tx.Select("failed_login_count").Where("id=?", userId).Take(user); res.Error != nil {
+			return res.Error
+		}
+		if user.FailedLoginCount < maxFailures {
+			return nil
+		}
+
+		locked = true
+		return tx.Model(&model.User{}).
+			Where("id=?", userId).
+			Updates(map[string]interface{}{
+				"failed_login_count": 0,
+				"locked_until":       &utils.CustomTime{Time: lockedUntil},
+			}).Error
+	})
+	return locked, err
+}
+
+// ListUserPasswordHistories 列出用户最近使用过的limit个密码
+func (d *Dao) ListUserPasswordHistories(
+	ctx context.Context, userId uint32, limit int,
+) (hs []*model.UserPasswordHistory, err error) {
+	res := d.getDbWithCtx(ctx).
+		Where("user_id=?", userId).
+		Order("id DESC").
+		Limit(limit).
+		Find(&hs)
+	return hs, res.Error
+}
@@ -39,6 +39,9 @@ func (d *Dao) SetProduct(
 			"description": description,
 		}).
 		First(&prod)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listJoinedUserIds(ctx, &model.UserProduct{}, "product_id=?", id)...)
+	}
 	return prod, res.Error
 }
 
@@ -83,18 +86,27 @@ func (d *Dao) AddUser2Product(ctx context.Context, productId, userId uint32, isO
 		IsOwner:   &isOwner,
 	})
 
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveProductsUser 关联表操作::移除指定产品线中指定用户
 func (d *Dao) RemoveProductsUser(ctx context.Context, productId, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserProduct{}, "user_id=? AND product_id=?", userId, productId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveUsersProducts 关联表操作::移除指定用户所有产品线
 func (d *Dao) RemoveUsersProducts(ctx context.Context, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserProduct{}, "user_id=?", userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
@@ -103,6 +115,9 @@ func (d *Dao) SetProductsOwner(ctx context.Context, productId, userId uint32, is
 	res := d.getDbWithCtx(ctx).Model(&model.UserProduct{}).
 		Where("user_id=? AND product_id=?", userId, productId).
 		Update("is_owner", isOwner)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"eago/auth/model"
 	"eago/common/orm"
+	"eago/common/utils"
+	"time"
 )
 
 // NewRole 新建角色
@@ -31,6 +33,9 @@ func (d *Dao) SetRole(ctx context.Context, id uint32, name, description string)
 			"description": description,
 		}).
 		Limit(1).Find(&r)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listJoinedUserIds(ctx, &model.UserRole{}, "role_id=?", id)...)
+	}
 	return r, res.Error
 }
 
@@ -73,18 +78,27 @@ func (d *Dao) AddUser2Role(ctx context.Context, roleId, userId uint32) error {
 		RoleId: roleId,
 		UserId: userId,
 	})
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveRolesUser 关联表操作::移除指定角色中指定用户
 func (d *Dao) RemoveRolesUser(ctx context.Context, roleId, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserRole{}, "role_id=? AND user_id=?", roleId, userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
 // RemoveUsersRoles 关联表操作::移除指定用户所有角色
 func (d *Dao) RemoveUsersRoles(ctx context.Context, userId uint32) error {
 	res := d.getDbWithCtx(ctx).Delete(model.UserRole{}, "user_id=?", userId)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
 	return res.Error
 }
 
@@ -117,3 +131,52 @@ func (d *Dao) ListRolesUsers(ctx context.Context, roleId uint32) (rUsers []*mode
 		Find(&rUsers)
 	return rUsers, res.Error
 }
+
+// AddRolePermission 授予角色权限，productId为nil时为全局权限
+func (d *Dao) AddRolePermission(
+	ctx context.Context, roleId uint32, permission string, productId *uint32,
+) (*model.RolePermission, error) {
+	rp := &model.RolePermission{
+		RoleId:     roleId,
+		Permission: permission,
+		ProductId:  productId,
+		CreatedAt:  &utils.CustomTime{Time: time.Now()},
+	}
+	res := d.getDbWithCtx(ctx).Create(&rp)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, d.listJoinedUserIds(ctx, &model.UserRole{}, "role_id=?", roleId)...)
+	}
+	return rp, res.Error
+}
+
+// RemoveRolePermission 移除角色的指定权限，返回是否删除成功
+func (d *Dao) RemoveRolePermission(ctx context.Context, roleId, id uint32) (bool, error) {
+	res := d.getDbWithCtx(ctx).Delete(model.RolePermission{}, "id=? AND role_id=?", id, roleId)
+	if res.Error == nil && res.RowsAffected > 0 {
+		d.notifyUsersChanged(ctx, d.listJoinedUserIds(ctx, &model.UserRole{}, "role_id=?", roleId)...)
+	}
+	return res.RowsAffected > 0, res.Error
+}
+
+// IsRolePermissionExist 查询角色权限是否存在
+func (d *Dao) IsRolePermissionExist(ctx context.Context, q orm.Query) (bool, error) {
+	var count int64
+	res := q.Where(d.getDbWithCtx(ctx).Model(&model.RolePermission{})).Count(&count)
+	return count > 0, res.Error
+}
+
+// ListRolePermissions 列出角色所有权限
+func (d *Dao) ListRolePermissions(ctx context.Context, roleId uint32) (rps []*model.RolePermission, err error) {
+	res := d.getDbWithCtx(ctx).Where("role_id=?", roleId).Find(&rps)
+	return rps, res.Error
+}
+
+// ListUsersPermissions 关联表操作::列出用户所属角色授予的所有权限
+func (d *Dao) ListUsersPermissions(ctx context.Context, userId uint32) (rps []*model.RolePermission, err error) {
+	res := d.getDbWithCtx(ctx).Model(&model.RolePermission{}).
+		Select("role_permissions.*").
+		Joins("INNER JOIN user_roles AS ur ON role_permissions.role_id = ur.role_id").
+		Where("ur.user_id=?", userId).
+		Find(&rps)
+	return rps, res.Error
+}
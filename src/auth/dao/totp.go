@@ -0,0 +1,91 @@
+package dao
+
+import (
+	"context"
+	"eago/auth/model"
+	"eago/common/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// SetUserTotpSecret 设置用户待绑定的两步验证密钥，绑定确认前不启用
+func (d *Dao) SetUserTotpSecret(ctx context.Context, userId uint32, secret string) error {
+	res := d.getDbWithCtx(ctx).Model(&model.User{}).
+		Where("id=?", userId).
+		Updates(map[string]interface{}{
+			"totp_secret":  secret,
+			"totp_enabled": false,
+		})
+	return res.Error
+}
+
+// EnableUserTotp 启用用户两步验证，并替换用户的恢复码
+func (d *Dao) EnableUserTotp(ctx context.Context, userId uint32, codeHashes []string) error {
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).Where("id=?", userId).Update("totp_enabled", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		return setUserRecoveryCodes(tx, userId, codeHashes)
+	})
+}
+
+// DisableUserTotp 停用用户两步验证，清除密钥及恢复码
+func (d *Dao) DisableUserTotp(ctx context.Context, userId uint32) error {
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).
+			Where("id=?", userId).
+			Updates(map[string]interface{}{
+				"totp_secret":  "",
+				"totp_enabled": false,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		return tx.Delete(model.UserRecoveryCode{}, "user_id=?", userId).Error
+	})
+}
+
+// SetUserTotpRequired 设置用户是否必须启用两步验证
+func (d *Dao) SetUserTotpRequired(ctx context.Context, userId uint32, required bool) error {
+	res := d.getDbWithCtx(ctx).Model(&model.User{}).
+		Where("id=?", userId).
+		Update("totp_required", required)
+	return res.Error
+}
+
+// SetUserRecoveryCodes 替换用户的恢复码
+func (d *Dao) SetUserRecoveryCodes(ctx context.Context, userId uint32, codeHashes []string) error {
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		return setUserRecoveryCodes(tx, userId, codeHashes)
+	})
+}
+
+// UseUserRecoveryCode 使用恢复码，恢复码不存在或已使用时返回false
+func (d *Dao) UseUserRecoveryCode(ctx context.Context, userId uint32, codeHash string) (bool, error) {
+	res := d.getDbWithCtx(ctx).Model(&model.UserRecoveryCode{}).
+		Where("user_id=? AND code_hash=? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", &utils.CustomTime{Time: time.Now()})
+	return res.RowsAffected > 0, res.Error
+}
+
+// setUserRecoveryCodes 在事务中删除用户原有恢复码并写入新的恢复码
+func setUserRecoveryCodes(tx *gorm.DB, userId uint32, codeHashes []string) error {
+	if err := tx.Delete(model.UserRecoveryCode{}, "user_id=?", userId).Error; err != nil {
+		return err
+	}
+
+	now := &utils.CustomTime{Time: time.Now()}
+	codes := make([]*model.UserRecoveryCode, 0, len(codeHashes))
+	for _, h := range codeHashes {
+		codes = append(codes, &model.UserRecoveryCode{
+			UserId:    userId,
+			CodeHash:  h,
+			CreatedAt: now,
+		})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
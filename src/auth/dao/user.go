@@ -6,6 +6,7 @@ import (
 	"eago/common/logger"
 	"eago/common/orm"
 	"eago/common/utils"
+	"gorm.io/gorm"
 	"strings"
 	"time"
 )
@@ -26,10 +27,29 @@ func (d *Dao) NewUser(ctx context.Context, username, email string, login bool) (
 	return u, res.Error
 }
 
-// RemoveUser 删除用户
+// NewServiceAccount 新建服务账号
+func (d *Dao) NewServiceAccount(ctx context.Context, username, email string) (*model.User, error) {
+	u := &model.User{
+		Username:         strings.ToLower(username),
+		Email:            strings.ToLower(email),
+		IsServiceAccount: true,
+	}
+	res := d.getDbWithCtx(ctx).Create(&u)
+	return u, res.Error
+}
+
+// RemoveUser 删除用户，同时删除其所有访问令牌
 func (d *Dao) RemoveUser(ctx context.Context, userId uint32) error {
-	res := d.getDbWithCtx(ctx).Delete(model.User{}, "id=?", userId)
-	return res.Error
+	err := d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		if res := tx.Delete(model.AccessToken{}, "user_id=?", userId); res.Error != nil {
+			return res.Error
+		}
+		return tx.Delete(model.User{}, "id=?", userId).Error
+	})
+	if err == nil {
+		d.notifyUsersChanged(ctx, userId)
+	}
+	return err
 }
 
 // SetUserLastLogin 更新用户最后登录时间，自动更新为当前时间
@@ -45,6 +65,9 @@ func (d *Dao) DisableUser(ctx context.Context, id uint32) error {
 	res := d.getDbWithCtx(ctx).Model(&model.User{}).
 		Where("id=?", id).
 		Update("disabled", true)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, id)
+	}
 	return res.Error
 }
 
@@ -57,6 +80,9 @@ func (d *Dao) SetUser(ctx context.Context, id uint32, email, phone string) (u *m
 			"phone": phone,
 		}).
 		First(&u)
+	if res.Error == nil {
+		d.notifyUsersChanged(ctx, id)
+	}
 	return u, res.Error
 }
 
@@ -308,6 +334,7 @@ func (d *Dao) MakeUserHandover(
 	}
 
 	tx.Commit()
+	d.notifyUsersChanged(ctx, userId, tgtUserId)
 	return
 }
 
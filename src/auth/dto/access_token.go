@@ -0,0 +1,6 @@
+package dto
+
+import "errors"
+
+// ErrAccessTokenNotFound 访问令牌不存在
+var ErrAccessTokenNotFound = errors.New("access token not found")
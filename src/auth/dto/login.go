@@ -0,0 +1,30 @@
+package dto
+
+import "errors"
+
+var (
+	// ErrLoginNotApplicable 当前登录方式无法认证该用户，交由下一个登录方式处理
+	ErrLoginNotApplicable = errors.New("login provider not applicable")
+	// ErrLoginAuthenticationFailed 认证失败
+	ErrLoginAuthenticationFailed = errors.New("authentication failed")
+	// ErrLoginInactiveCrowdUser 用户在Crowd中是禁用状态
+	ErrLoginInactiveCrowdUser = errors.New("inactive crowd user")
+	// ErrLoginDisabledUser 用户处于禁用状态
+	ErrLoginDisabledUser = errors.New("disabled user")
+	// ErrLoginNoPasswordUser 用户没有设置密码
+	ErrLoginNoPasswordUser = errors.New("no password user")
+	// ErrLoginProviderDisabled 登录方式未启用
+	ErrLoginProviderDisabled = errors.New("login provider disabled")
+	// ErrLoginServiceAccount 服务账号不能交互式登录
+	ErrLoginServiceAccount = errors.New("service account cannot login")
+	// ErrLoginStateInvalid 跳转登录的state无效或已过期
+	ErrLoginStateInvalid = errors.New("login state invalid or expired")
+	// ErrLoginUserLocked 连续登录失败次数过多，账号被临时锁定
+	ErrLoginUserLocked = errors.New("user locked")
+	// ErrLoginPasswordMustChange 密码已被管理员重置，需修改密码后才能登录
+	ErrLoginPasswordMustChange = errors.New("password must be changed")
+	// ErrLoginIdentityNotLinked 外部身份未关联本地用户，且已存在同名的本地用户，需登录后主动关联
+	ErrLoginIdentityNotLinked = errors.New("identity not linked")
+	// ErrLoginIdentityLinked 外部身份已关联其他用户
+	ErrLoginIdentityLinked = errors.New("identity already linked to another user")
+)
@@ -0,0 +1,49 @@
+package dto
+
+// 组织架构同步变更动作
+const (
+	OrgSyncActionCreate = "create"
+	OrgSyncActionUpdate = "update"
+	OrgSyncActionDelete = "delete"
+	// OrgSyncActionKeep 来源中已不存在但因存在本地数据而保留
+	OrgSyncActionKeep = "keep"
+)
+
+// 组织架构同步变更对象
+const (
+	OrgSyncObjectDepartment = "department"
+	OrgSyncObjectUser       = "user"
+	OrgSyncObjectMembership = "membership"
+)
+
+// OrgDepartment 组织架构来源中的部门
+type OrgDepartment struct {
+	ExternalId       string
+	Name             string
+	ParentExternalId string
+	// Leaders 部门负责人用户名，同步为部门Owner
+	Leaders []string
+}
+
+// OrgUser 组织架构来源中的用户
+type OrgUser struct {
+	Username             string
+	DisplayName          string
+	Email                string
+	DepartmentExternalId string
+}
+
+// OrgSyncChange 组织架构同步的单条变更
+type OrgSyncChange struct {
+	Action string `json:"action"`
+	Object string `json:"object"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// OrgSyncDiff 组织架构同步结果，DryRun时仅包含差异不做变更
+type OrgSyncDiff struct {
+	Source  string           `json:"source"`
+	DryRun  bool             `json:"dry_run"`
+	Changes []*OrgSyncChange `json:"changes"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "errors"
+
+var (
+	// ErrPasswordPolicy 密码不符合密码策略
+	ErrPasswordPolicy = errors.New("password does not meet the policy")
+	// ErrPasswordReused 密码与最近使用过的密码相同
+	ErrPasswordReused = errors.New("password was used recently")
+	// ErrPasswordIncorrect 原密码不正确
+	ErrPasswordIncorrect = errors.New("incorrect password")
+)
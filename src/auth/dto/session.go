@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"eago/common/utils"
+	"errors"
+)
+
+// 登录方式
+const (
+	LoginMethodCrowd    = "crowd"
+	LoginMethodDatabase = "database"
+	LoginMethodEagle    = "eagle"
+	LoginMethodOidc     = "oidc"
+)
+
+// ErrSessionNotFound 会话不存在或已过期
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionInfo 登录时记录的会话信息
+type SessionInfo struct {
+	LoginMethod string
+	Ip          string
+	UserAgent   string
+	// Mfa 是否通过了第二因素认证
+	Mfa bool
+}
+
+// Session 用户会话，与Token一一对应
+type Session struct {
+	// Id 会话ID，由Token计算得出，不暴露Token本身
+	Id       string `json:"id"`
+	UserId   uint32 `json:"user_id"`
+	Username string `json:"username"`
+
+	LoginMethod string `json:"login_method"`
+	Ip          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+	Mfa         bool   `json:"mfa"`
+	// Current 是否是当前请求使用的会话
+	Current bool `json:"current"`
+
+	CreatedAt       *utils.CustomTime `json:"created_at"`
+	LastHeartbeatAt *utils.CustomTime `json:"last_heartbeat_at"`
+}
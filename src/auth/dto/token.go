@@ -31,6 +31,13 @@ type TokenContent struct {
 	OwnProducts []*ProductInToken `json:"own_products"`
 	Groups      []*GroupInToken   `json:"groups"`
 	OwnGroups   []*GroupInToken   `json:"own_groups"`
+	// Permissions 角色授予的权限，产品线范围的权限格式为<权限>@<产品线ID>
+	Permissions []string `json:"permissions"`
+
+	// Scopes 访问令牌允许访问的路由，登录Token为空，表示不限制
+	Scopes []string `json:"scopes,omitempty"`
+	// Mfa 登录时是否通过了第二因素认证，访问令牌始终为false
+	Mfa bool `json:"mfa"`
 }
 
 func (tc *TokenContent) Trans2AuthPb(in *authpb.TokenContent) {
@@ -52,6 +59,10 @@ func (tc *TokenContent) Trans2AuthPb(in *authpb.TokenContent) {
 		in.Roles[idx] = *role
 	}
 
+	in.Scopes = tc.Scopes
+	in.Permissions = tc.Permissions
+	in.Mfa = tc.Mfa
+
 	// 加载产品线
 	wg := new(sync.WaitGroup)
 	wg.Add(1)
@@ -0,0 +1,41 @@
+package dto
+
+import "errors"
+
+var (
+	// ErrTotpAlreadyEnabled 两步验证已启用
+	ErrTotpAlreadyEnabled = errors.New("totp already enabled")
+	// ErrTotpNotEnrolled 未绑定或未启用两步验证
+	ErrTotpNotEnrolled = errors.New("totp not enrolled")
+	// ErrTotpCodeInvalid 验证码或恢复码不正确
+	ErrTotpCodeInvalid = errors.New("invalid totp code")
+	// ErrTotpRequired 管理员要求必须启用两步验证，不允许停用
+	ErrTotpRequired = errors.New("totp required")
+	// ErrMfaChallengeInvalid 两步验证请求无效或已过期
+	ErrMfaChallengeInvalid = errors.New("mfa challenge invalid or expired")
+)
+
+// MfaChallenge 第一步登录通过后返回的两步验证请求
+// EnrollRequired为true时用户被要求启用两步验证但尚未绑定，需先绑定再完成登录
+type MfaChallenge struct {
+	MfaToken       string `json:"mfa_token"`
+	EnrollRequired bool   `json:"enroll_required"`
+}
+
+// MfaEnrollLogin 登录时绑定两步验证成功后返回的Token及恢复码，恢复码只在此时返回
+type MfaEnrollLogin struct {
+	Token         string   `json:"token"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TotpProof 用户操作自己的两步验证设置时提供的当前验证码或恢复码，只需提供其中一个
+type TotpProof struct {
+	Code         string
+	RecoveryCode string
+}
+
+// TotpEnrollment 绑定两步验证时返回的密钥，Uri可生成二维码供身份验证器扫描
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// AccessToken 个人访问令牌-数据库模型
+type AccessToken struct {
+	Id uint32 `json:"id"`
+
+	UserId uint32 `json:"user_id"`
+	Name   string `json:"name"`
+	// TokenHash 令牌的SHA256，不保存明文
+	TokenHash string `json:"-"`
+	// TokenPrefix 令牌的前几位，用于展示时辨认
+	TokenPrefix string   `json:"token_prefix"`
+	Scopes      []string `json:"scopes" gorm:"serializer:json"`
+
+	ExpiresAt  *utils.CustomTime `json:"expires_at"`
+	LastUsedAt *utils.CustomTime `json:"last_used_at"`
+	CreatedBy  string            `json:"created_by"`
+	CreatedAt  *utils.CustomTime `json:"created_at"`
+}
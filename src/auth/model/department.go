@@ -10,6 +10,9 @@ type Department struct {
 
 	Name     string  `json:"name"`
 	ParentId *uint32 `json:"parent_id"`
+	// Source 组织架构同步来源，手动维护的部门为空
+	Source     *string `json:"source"`
+	ExternalId *string `json:"external_id"`
 
 	CreatedAt *utils.CustomTime `json:"created_at"`
 	UpdatedAt *utils.CustomTime `json:"updated_at"`
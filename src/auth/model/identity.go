@@ -0,0 +1,16 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// UserIdentity 用户关联的外部身份-数据库模型，以签发方及其中的唯一标识确定一个外部账号
+type UserIdentity struct {
+	Id uint32 `json:"id"`
+
+	UserId    uint32            `json:"user_id"`
+	Provider  string            `json:"provider"`
+	Issuer    string            `json:"issuer"`
+	Subject   string            `json:"subject"`
+	CreatedAt *utils.CustomTime `json:"created_at"`
+}
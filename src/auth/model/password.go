@@ -0,0 +1,14 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// UserPasswordHistory 用户历史密码-数据库模型，用于禁止重复使用最近的密码
+type UserPasswordHistory struct {
+	Id uint32 `json:"id"`
+
+	UserId    uint32            `json:"user_id"`
+	Password  string            `json:"-"`
+	CreatedAt *utils.CustomTime `json:"created_at"`
+}
@@ -28,3 +28,14 @@ type RolesUser struct {
 	Username string            `json:"username"`
 	JoinedAt *utils.CustomTime `json:"joined_at"`
 }
+
+// RolePermission 角色权限-数据库模型
+type RolePermission struct {
+	Id uint32 `json:"id"`
+
+	RoleId     uint32 `json:"role_id"`
+	Permission string `json:"permission"`
+	// ProductId 不为空时权限仅在该产品线范围内有效
+	ProductId *uint32           `json:"product_id"`
+	CreatedAt *utils.CustomTime `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// UserRecoveryCode 两步验证恢复码-数据库模型，每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	Id uint32 `json:"id"`
+
+	UserId    uint32            `json:"user_id"`
+	CodeHash  string            `json:"-"`
+	UsedAt    *utils.CustomTime `json:"used_at"`
+	CreatedAt *utils.CustomTime `json:"created_at"`
+}
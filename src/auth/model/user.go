@@ -14,6 +14,19 @@ type User struct {
 	Phone       string `json:"phone"`
 	IsSuperuser bool   `json:"is_superuser"`
 	Disabled    bool   `json:"disabled"`
+	// IsServiceAccount 服务账号，只能使用个人访问令牌访问，不能交互式登录
+	IsServiceAccount bool `json:"is_service_account"`
+	// MustChangePassword 管理员重置密码后，用户需修改密码才能登录
+	MustChangePassword bool `json:"must_change_password"`
+	// FailedLoginCount 连续登录失败次数，LockedUntil 账号锁定截止时间
+	FailedLoginCount  int               `json:"failed_login_count"`
+	LockedUntil       *utils.CustomTime `json:"locked_until"`
+	PasswordChangedAt *utils.CustomTime `json:"password_changed_at"`
+	// TotpSecret 两步验证密钥，TotpEnabled 为false时表示尚未完成绑定
+	TotpSecret  string `json:"-"`
+	TotpEnabled bool   `json:"totp_enabled"`
+	// TotpRequired 管理员要求该用户必须启用两步验证
+	TotpRequired bool `json:"totp_required"`
 
 	LastLogin *utils.CustomTime `json:"last_login"`
 	CreatedAt *utils.CustomTime `json:"created_at"`
@@ -72,6 +72,9 @@ type TokenContent struct {
 	OwnProducts          []*Product `protobuf:"bytes,8,rep,name=own_products,json=ownProducts,proto3" json:"own_products,omitempty"`
 	Groups               []*Group   `protobuf:"bytes,9,rep,name=groups,proto3" json:"groups,omitempty"`
 	OwnGroups            []*Group   `protobuf:"bytes,10,rep,name=own_groups,json=ownGroups,proto3" json:"own_groups,omitempty"`
+	Scopes               []string   `protobuf:"bytes,11,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Permissions          []string   `protobuf:"bytes,12,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	Mfa                  bool       `protobuf:"varint,13,opt,name=mfa,proto3" json:"mfa,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -172,6 +175,27 @@ func (m *TokenContent) GetOwnGroups() []*Group {
 	return nil
 }
 
+func (m *TokenContent) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *TokenContent) GetPermissions() []string {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+func (m *TokenContent) GetMfa() bool {
+	if m != nil {
+		return m.Mfa
+	}
+	return false
+}
+
 // UsersDepartment 用户所在部门的信息
 type UsersDepartment struct {
 	Id                   uint32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -895,64 +919,66 @@ func init() {
 func init() { proto.RegisterFile("eago_auth.proto", fileDescriptor_a467f4fd1dd5e764) }
 
 var fileDescriptor_a467f4fd1dd5e764 = []byte{
-	// 931 bytes of a gzipped FileDescriptorProto
+	// 966 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xde, 0x4d, 0x6c, 0xc7, 0x3e, 0x4e, 0xe2, 0x66, 0xe4, 0xa6, 0x1b, 0x07, 0x50, 0x3a, 0x12,
-	0xc8, 0x12, 0x92, 0x23, 0x15, 0x01, 0x45, 0xaa, 0x90, 0x92, 0x52, 0x4c, 0x04, 0x2d, 0x61, 0xc3,
-	0x8f, 0xc4, 0x05, 0xd6, 0x3a, 0x7b, 0xba, 0x59, 0xf0, 0xee, 0xac, 0x76, 0x66, 0x1b, 0xa5, 0x8f,
-	0xd0, 0x2b, 0x2e, 0xb8, 0xe4, 0x41, 0x78, 0x15, 0xde, 0x83, 0x07, 0x40, 0x73, 0x66, 0xbd, 0x1e,
-	0x3b, 0xae, 0x09, 0x81, 0xde, 0xcd, 0xf9, 0xfb, 0xf6, 0x3b, 0x3f, 0x73, 0x66, 0xa1, 0x83, 0x41,
-	0x24, 0x46, 0x41, 0xa1, 0x2e, 0x06, 0x59, 0x2e, 0x94, 0x60, 0x2d, 0xad, 0x18, 0x68, 0x45, 0x6f,
-	0x3f, 0x12, 0x22, 0x9a, 0xe0, 0x21, 0x19, 0xc6, 0xc5, 0xf3, 0x43, 0x4c, 0x32, 0x75, 0x65, 0xfc,
-	0x7a, 0x7b, 0xe7, 0x22, 0x49, 0x44, 0x6a, 0x8c, 0x87, 0x46, 0x30, 0x26, 0xfe, 0x36, 0xd4, 0xbf,
-	0x15, 0xbf, 0x60, 0xca, 0xba, 0x50, 0x7f, 0x11, 0x4c, 0x0a, 0xf4, 0xdc, 0x03, 0xb7, 0xdf, 0xf2,
-	0x8d, 0xc0, 0xff, 0x5a, 0x83, 0x4d, 0xb2, 0x3f, 0x16, 0xa9, 0xc2, 0x54, 0xb1, 0x7b, 0xb0, 0x51,
-	0x48, 0xcc, 0x47, 0x71, 0x48, 0x8e, 0x5b, 0x7e, 0x43, 0x8b, 0x27, 0x21, 0xeb, 0x41, 0x53, 0x9f,
-	0xd2, 0x20, 0x41, 0x6f, 0x8d, 0x20, 0x2a, 0x59, 0x63, 0x67, 0x17, 0x22, 0x45, 0x6f, 0xdd, 0x60,
-	0x93, 0xc0, 0xee, 0xc3, 0x66, 0x2c, 0x47, 0xb2, 0xc8, 0x30, 0xd7, 0x9e, 0x5e, 0xed, 0xc0, 0xed,
-	0x37, 0xfd, 0x76, 0x2c, 0xcf, 0xa6, 0x2a, 0xf6, 0x0e, 0x40, 0x88, 0x59, 0x90, 0xab, 0x04, 0x53,
-	0xe5, 0xd5, 0x0f, 0xd6, 0xfb, 0x2d, 0xdf, 0xd2, 0x68, 0xe0, 0x5c, 0x4c, 0x50, 0x7a, 0x0d, 0x32,
-	0x19, 0x81, 0x0d, 0xa0, 0x99, 0xe5, 0x22, 0x2c, 0xce, 0x95, 0xf4, 0x36, 0x0e, 0xd6, 0xfb, 0xed,
-	0x07, 0x6c, 0x50, 0x55, 0x6a, 0x70, 0x6a, 0x4c, 0x7e, 0xe5, 0xc3, 0x3e, 0x84, 0x4d, 0x71, 0x99,
-	0x8e, 0xaa, 0x98, 0xe6, 0x6b, 0x63, 0xda, 0xe2, 0x32, 0x3d, 0x9d, 0x86, 0xf5, 0xa1, 0x11, 0xe5,
-	0xa2, 0xc8, 0xa4, 0xd7, 0xa2, 0x80, 0x3b, 0x56, 0xc0, 0x50, 0x1b, 0xfc, 0xd2, 0xce, 0x0e, 0x01,
-	0xf4, 0x07, 0x4a, 0x6f, 0x78, 0x8d, 0x77, 0x4b, 0x5c, 0xa6, 0x74, 0x92, 0xfc, 0x95, 0x0b, 0x9d,
-	0xef, 0x24, 0xe6, 0xf2, 0xb3, 0x59, 0xae, 0xdb, 0xb0, 0x56, 0x15, 0x7d, 0x2d, 0x0e, 0x19, 0x83,
-	0x9a, 0x55, 0x6c, 0x3a, 0xb3, 0x7d, 0x68, 0x65, 0x41, 0x8e, 0xa9, 0xd2, 0xfd, 0x59, 0x27, 0xd7,
-	0xa6, 0x51, 0x9c, 0x84, 0x6c, 0x0f, 0x9a, 0xb1, 0x1c, 0x89, 0xcb, 0xb4, 0xaa, 0xf5, 0x46, 0x2c,
-	0xbf, 0xd6, 0xa2, 0x8e, 0xfb, 0x59, 0xc4, 0x29, 0x86, 0xa3, 0x40, 0x97, 0x99, 0xba, 0x67, 0x14,
-	0x47, 0x8a, 0xff, 0xea, 0x02, 0x9c, 0x06, 0x11, 0x86, 0xc4, 0x88, 0xbd, 0x0b, 0x75, 0xdd, 0x1b,
-	0xe9, 0xb9, 0x94, 0x47, 0xc7, 0xca, 0x43, 0x3b, 0xf8, 0xc6, 0xaa, 0xe9, 0x65, 0x41, 0x64, 0xe8,
-	0x6d, 0xf9, 0x74, 0xa6, 0x39, 0x08, 0x22, 0x94, 0x25, 0x35, 0x23, 0x18, 0xd2, 0x11, 0x8e, 0x64,
-	0xfc, 0x12, 0x89, 0x18, 0x91, 0x8e, 0xf0, 0x2c, 0x7e, 0x49, 0x21, 0x4a, 0xa8, 0x60, 0x42, 0xac,
-	0xb6, 0x7c, 0x23, 0xf0, 0xdf, 0x5d, 0xd8, 0x22, 0x4a, 0x55, 0x33, 0xec, 0x9e, 0xbb, 0x37, 0xe8,
-	0xf9, 0x1b, 0xa5, 0xf7, 0x9b, 0x0b, 0x6d, 0xa2, 0x67, 0xda, 0x69, 0x4d, 0x8a, 0xfb, 0x0f, 0x93,
-	0xf2, 0x46, 0x69, 0xfd, 0xe1, 0x42, 0xfb, 0x29, 0x26, 0x63, 0xcc, 0x4d, 0x27, 0x3f, 0x9e, 0x76,
-	0xb2, 0x4e, 0xac, 0xee, 0x5b, 0xac, 0x2c, 0x37, 0xeb, 0x5c, 0xf6, 0xb6, 0x97, 0x01, 0xcc, 0x94,
-	0xd7, 0x06, 0x73, 0xd5, 0x26, 0xb0, 0x67, 0x70, 0x7d, 0xc5, 0x0c, 0xd6, 0x16, 0x66, 0xf0, 0x95,
-	0x0b, 0x77, 0x7c, 0x7d, 0xb9, 0x6d, 0xfe, 0x8f, 0xe6, 0x27, 0xf1, 0x3d, 0x8b, 0xff, 0xa2, 0xef,
-	0x92, 0x24, 0x1e, 0xde, 0x36, 0x09, 0xfe, 0x13, 0xd4, 0xfe, 0x75, 0xe2, 0x5d, 0xa8, 0x63, 0x12,
-	0xc4, 0x93, 0xe9, 0x0a, 0x24, 0x61, 0xb6, 0x18, 0x6b, 0xd6, 0x62, 0xe4, 0x23, 0xd8, 0x28, 0x07,
-	0xf6, 0x46, 0x97, 0xbe, 0x0b, 0xf5, 0x60, 0x12, 0x07, 0x72, 0x0a, 0x4d, 0x82, 0x26, 0x13, 0xc6,
-	0x32, 0x18, 0x4f, 0x30, 0x2c, 0x6f, 0x7b, 0x25, 0xf3, 0xf7, 0xa1, 0x4e, 0x63, 0x77, 0x13, 0x78,
-	0xfe, 0x14, 0xe0, 0x7f, 0xdc, 0x42, 0x0f, 0xfe, 0x6c, 0x42, 0xfb, 0xa8, 0x50, 0x17, 0x67, 0x98,
-	0xbf, 0x88, 0xcf, 0x91, 0x7d, 0x02, 0xed, 0xef, 0x31, 0x8f, 0x9f, 0x5f, 0x99, 0x67, 0xc8, 0xbe,
-	0x1a, 0xa4, 0xe9, 0xed, 0x0e, 0xcc, 0xd3, 0x36, 0x98, 0x3e, 0x6d, 0x83, 0x27, 0xfa, 0x69, 0xe3,
-	0x0e, 0xfb, 0x14, 0x3a, 0x43, 0x54, 0x73, 0xcf, 0xd3, 0xf5, 0xf0, 0x7b, 0x8b, 0x9a, 0xd2, 0x95,
-	0x3b, 0xec, 0x18, 0x76, 0x86, 0xa8, 0x66, 0xc9, 0x1d, 0x5f, 0x9d, 0x84, 0xac, 0x6b, 0xfc, 0xcb,
-	0x47, 0xf2, 0x24, 0xfc, 0xa6, 0xc0, 0xfc, 0xaa, 0x77, 0xd7, 0x42, 0x99, 0x05, 0x70, 0x87, 0x7d,
-	0x0e, 0xdd, 0xaf, 0x62, 0x69, 0x81, 0x48, 0x33, 0x9b, 0xcb, 0x61, 0x76, 0x97, 0x5f, 0x31, 0xee,
-	0xb0, 0x2f, 0x61, 0x4f, 0xe3, 0x9c, 0x52, 0x99, 0x66, 0x68, 0xb7, 0x03, 0x7b, 0x08, 0x9b, 0x43,
-	0x54, 0xd4, 0xe2, 0x15, 0x39, 0x5d, 0xdb, 0x42, 0xdc, 0x61, 0x43, 0xe8, 0xd0, 0xe2, 0xd2, 0x5c,
-	0xca, 0xe5, 0xd5, 0x9b, 0x0b, 0xa6, 0xd0, 0x1f, 0x62, 0x75, 0xa1, 0xdd, 0xe6, 0x28, 0x58, 0x0b,
-	0x8f, 0x3b, 0xec, 0x08, 0x3a, 0x33, 0x8c, 0xdb, 0x65, 0xf1, 0x08, 0xb6, 0x87, 0xa8, 0xca, 0x9b,
-	0xb0, 0x22, 0x8f, 0x25, 0x8b, 0x9e, 0x0a, 0xba, 0x53, 0x65, 0x52, 0xbd, 0x12, 0xab, 0x72, 0xf1,
-	0x16, 0x73, 0x99, 0x46, 0x71, 0x87, 0x3d, 0x86, 0x1d, 0x1b, 0xe7, 0x76, 0xf9, 0x0c, 0x61, 0x5b,
-	0x83, 0xd0, 0x6a, 0x32, 0x08, 0xbb, 0x73, 0x08, 0xcf, 0x82, 0x04, 0x0d, 0xc6, 0xfe, 0x8a, 0x4d,
-	0xc6, 0x1d, 0xf6, 0x11, 0xb4, 0x87, 0x48, 0xa3, 0xb1, 0xa2, 0x2a, 0x8b, 0xef, 0x32, 0x77, 0xd8,
-	0x13, 0xd8, 0xae, 0x4a, 0x62, 0x08, 0xac, 0xaa, 0xc7, 0xdd, 0xc5, 0x7a, 0x4c, 0x3f, 0xff, 0x05,
-	0xb0, 0xf2, 0xf3, 0xf6, 0xef, 0xc9, 0x72, 0x16, 0xbd, 0x05, 0x16, 0x72, 0xee, 0xf2, 0x3c, 0x83,
-	0xb7, 0x2a, 0x2e, 0x67, 0x41, 0x82, 0xff, 0x71, 0xee, 0x8f, 0x9b, 0x3f, 0x36, 0xb4, 0x36, 0x1b,
-	0x8f, 0x1b, 0xb4, 0x2c, 0x3e, 0xf8, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x34, 0x5c, 0x70, 0x95, 0x33,
-	0x0b, 0x00, 0x00,
+	0x14, 0xf6, 0xc6, 0x3f, 0xb1, 0xcf, 0xc6, 0x71, 0x32, 0x72, 0xd3, 0x8d, 0x03, 0xc8, 0x1d, 0x09,
+	0x64, 0x09, 0xc9, 0x91, 0x8a, 0x80, 0x22, 0x55, 0x48, 0x49, 0x29, 0x26, 0x82, 0x96, 0xb0, 0xe1,
+	0x47, 0xe2, 0x02, 0x6b, 0x9c, 0x3d, 0x71, 0x06, 0xbc, 0x3b, 0xab, 0x9d, 0x71, 0xa3, 0xf4, 0x11,
+	0x7a, 0xc5, 0x05, 0x97, 0x3c, 0x08, 0x97, 0xbc, 0x06, 0x6f, 0x83, 0x66, 0x66, 0xbd, 0x1e, 0x3b,
+	0xae, 0x09, 0x81, 0xde, 0xcd, 0xf9, 0xfb, 0xf6, 0x3b, 0x3f, 0x73, 0x66, 0xa1, 0x85, 0x6c, 0x2c,
+	0x86, 0x6c, 0xaa, 0x2e, 0xfb, 0x69, 0x26, 0x94, 0x20, 0x0d, 0xad, 0xe8, 0x6b, 0x45, 0xe7, 0x60,
+	0x2c, 0xc4, 0x78, 0x82, 0x87, 0xc6, 0x30, 0x9a, 0x5e, 0x1c, 0x62, 0x9c, 0xaa, 0x6b, 0xeb, 0xd7,
+	0xd9, 0x3f, 0x17, 0x71, 0x2c, 0x12, 0x6b, 0x3c, 0xb4, 0x82, 0x35, 0xd1, 0xb7, 0xa1, 0xfa, 0xad,
+	0xf8, 0x05, 0x13, 0xd2, 0x86, 0xea, 0x0b, 0x36, 0x99, 0x62, 0xe0, 0x75, 0xbd, 0x5e, 0x23, 0xb4,
+	0x02, 0xfd, 0xb3, 0x0c, 0x5b, 0xc6, 0xfe, 0x44, 0x24, 0x0a, 0x13, 0x45, 0xee, 0xc3, 0xe6, 0x54,
+	0x62, 0x36, 0xe4, 0x91, 0x71, 0x6c, 0x86, 0x35, 0x2d, 0x9e, 0x44, 0xa4, 0x03, 0x75, 0x7d, 0x4a,
+	0x58, 0x8c, 0xc1, 0x86, 0x81, 0x28, 0x64, 0x8d, 0x9d, 0x5e, 0x8a, 0x04, 0x83, 0xb2, 0xc5, 0x36,
+	0x02, 0x79, 0x00, 0x5b, 0x5c, 0x0e, 0xe5, 0x34, 0xc5, 0x4c, 0x7b, 0x06, 0x95, 0xae, 0xd7, 0xab,
+	0x87, 0x3e, 0x97, 0x67, 0x33, 0x15, 0x79, 0x07, 0x20, 0xc2, 0x94, 0x65, 0x2a, 0xc6, 0x44, 0x05,
+	0xd5, 0x6e, 0xb9, 0xd7, 0x08, 0x1d, 0x8d, 0x06, 0xce, 0xc4, 0x04, 0x65, 0x50, 0x33, 0x26, 0x2b,
+	0x90, 0x3e, 0xd4, 0xd3, 0x4c, 0x44, 0xd3, 0x73, 0x25, 0x83, 0xcd, 0x6e, 0xb9, 0xe7, 0x3f, 0x24,
+	0xfd, 0xa2, 0x52, 0xfd, 0x53, 0x6b, 0x0a, 0x0b, 0x1f, 0xf2, 0x21, 0x6c, 0x89, 0xab, 0x64, 0x58,
+	0xc4, 0xd4, 0x5f, 0x1b, 0xe3, 0x8b, 0xab, 0xe4, 0x74, 0x16, 0xd6, 0x83, 0xda, 0x38, 0x13, 0xd3,
+	0x54, 0x06, 0x0d, 0x13, 0xb0, 0xe3, 0x04, 0x0c, 0xb4, 0x21, 0xcc, 0xed, 0xe4, 0x10, 0x40, 0x7f,
+	0x20, 0xf7, 0x86, 0xd7, 0x78, 0x37, 0xc4, 0x55, 0x32, 0xb0, 0x01, 0x7b, 0x50, 0x93, 0xe7, 0x22,
+	0x45, 0x19, 0xf8, 0x26, 0xb1, 0x5c, 0x22, 0x5d, 0xf0, 0x53, 0xcc, 0x62, 0x2e, 0x25, 0x17, 0x89,
+	0x0c, 0xb6, 0x8c, 0xd1, 0x55, 0x91, 0x1d, 0x28, 0xc7, 0x17, 0x2c, 0x68, 0x9a, 0x5a, 0xea, 0x23,
+	0x7d, 0xe5, 0x41, 0xeb, 0x3b, 0x89, 0x99, 0xfc, 0x6c, 0x5e, 0xb7, 0x6d, 0xd8, 0x28, 0x1a, 0xb8,
+	0xc1, 0x23, 0x42, 0xa0, 0xe2, 0x34, 0xce, 0x9c, 0xc9, 0x01, 0x34, 0x52, 0x96, 0x61, 0xa2, 0x74,
+	0xaf, 0xcb, 0xc6, 0xb5, 0x6e, 0x15, 0x27, 0x11, 0xd9, 0x87, 0x3a, 0x97, 0x43, 0x71, 0x95, 0x14,
+	0x7d, 0xdb, 0xe4, 0xf2, 0x6b, 0x2d, 0xea, 0xb8, 0x9f, 0x05, 0x4f, 0x30, 0x1a, 0x32, 0xdd, 0x32,
+	0x33, 0x09, 0x56, 0x71, 0xa4, 0xe8, 0xaf, 0x1e, 0xc0, 0x29, 0x1b, 0x63, 0x64, 0x18, 0x91, 0x77,
+	0xa1, 0xaa, 0xfb, 0x2c, 0x03, 0xcf, 0xd4, 0xa4, 0xe5, 0xd4, 0x44, 0x3b, 0x84, 0xd6, 0xaa, 0xe9,
+	0xa5, 0x6c, 0x6c, 0xe9, 0x35, 0x43, 0x73, 0x36, 0x33, 0xc5, 0xc6, 0x28, 0x73, 0x6a, 0x56, 0xb0,
+	0xa4, 0xc7, 0x38, 0x94, 0xfc, 0x25, 0x06, 0x95, 0x19, 0xe9, 0x31, 0x9e, 0xf1, 0x97, 0x26, 0x44,
+	0x09, 0xc5, 0x26, 0x86, 0x55, 0x33, 0xb4, 0x02, 0xfd, 0xdd, 0x83, 0xa6, 0xa1, 0x54, 0x34, 0xd6,
+	0x9d, 0x1f, 0xef, 0x16, 0xf3, 0xf3, 0x46, 0xe9, 0xfd, 0xe6, 0x81, 0x6f, 0xe8, 0xe5, 0xa3, 0x31,
+	0x9f, 0x3a, 0xef, 0x1f, 0xa6, 0xee, 0x8d, 0xd2, 0xfa, 0xc3, 0x03, 0xff, 0x19, 0xc6, 0x23, 0xcc,
+	0x6c, 0x27, 0x3f, 0x9e, 0x75, 0xb2, 0x6a, 0x58, 0x3d, 0x70, 0x58, 0x39, 0x6e, 0xce, 0x39, 0xef,
+	0x6d, 0x27, 0x05, 0x98, 0x2b, 0x6f, 0x0c, 0xe6, 0xba, 0xad, 0xe2, 0xce, 0x60, 0x79, 0xcd, 0x0c,
+	0x56, 0x96, 0x66, 0xf0, 0x95, 0x07, 0x3b, 0xa1, 0x5e, 0x14, 0x2e, 0xff, 0xc7, 0x8b, 0x93, 0xf8,
+	0x9e, 0xc3, 0x7f, 0xd9, 0x77, 0x45, 0x12, 0x8f, 0xee, 0x9a, 0x04, 0xfd, 0x09, 0x2a, 0xff, 0x3a,
+	0xf1, 0x36, 0x54, 0x31, 0x66, 0x7c, 0x32, 0x5b, 0xa7, 0x46, 0x98, 0x2f, 0xd9, 0x8a, 0xb3, 0x64,
+	0xe9, 0x10, 0x36, 0xf3, 0x81, 0xbd, 0xd5, 0xa5, 0x6f, 0x43, 0x95, 0x4d, 0x38, 0x93, 0x33, 0x68,
+	0x23, 0x68, 0x32, 0x11, 0x97, 0x6c, 0x34, 0xc1, 0x28, 0xbf, 0xed, 0x85, 0x4c, 0xdf, 0x87, 0xaa,
+	0x19, 0xbb, 0xdb, 0xc0, 0xd3, 0x67, 0x00, 0xff, 0xe3, 0x16, 0x7a, 0xf8, 0x57, 0x1d, 0xfc, 0xa3,
+	0xa9, 0xba, 0x3c, 0xc3, 0xec, 0x05, 0x3f, 0x47, 0xf2, 0x09, 0xf8, 0xdf, 0x63, 0xc6, 0x2f, 0xae,
+	0xed, 0x93, 0xe6, 0x5e, 0x0d, 0xa3, 0xe9, 0xec, 0xf5, 0xed, 0x33, 0xd9, 0x9f, 0x3d, 0x93, 0xfd,
+	0xa7, 0xfa, 0x99, 0xa4, 0x25, 0xf2, 0x29, 0xb4, 0x06, 0xa8, 0x16, 0x9e, 0xba, 0x9b, 0xe1, 0xf7,
+	0x97, 0x35, 0xb9, 0x2b, 0x2d, 0x91, 0x63, 0xd8, 0x1d, 0xa0, 0x9a, 0x27, 0x77, 0x7c, 0x7d, 0x12,
+	0x91, 0xb6, 0xf5, 0xcf, 0x1f, 0xdc, 0x93, 0xe8, 0x9b, 0x29, 0x66, 0xd7, 0x9d, 0x7b, 0x0e, 0xca,
+	0x3c, 0x80, 0x96, 0xc8, 0xe7, 0xd0, 0xfe, 0x8a, 0x4b, 0x07, 0x44, 0xda, 0xd9, 0x5c, 0x0d, 0xb3,
+	0xb7, 0xfa, 0x8a, 0xd1, 0x12, 0xf9, 0x12, 0xf6, 0x35, 0xce, 0xa9, 0x29, 0xd3, 0x1c, 0xed, 0x6e,
+	0x60, 0x8f, 0x60, 0x6b, 0x80, 0xca, 0xb4, 0x78, 0x4d, 0x4e, 0x37, 0xb6, 0x10, 0x2d, 0x91, 0x01,
+	0xb4, 0xcc, 0xe2, 0xd2, 0x5c, 0xf2, 0xe5, 0xd5, 0x59, 0x08, 0x36, 0xa1, 0x3f, 0x70, 0x75, 0xa9,
+	0xdd, 0x16, 0x28, 0x38, 0x0b, 0x8f, 0x96, 0xc8, 0x11, 0xb4, 0xe6, 0x18, 0x77, 0xcb, 0xe2, 0x31,
+	0x6c, 0x0f, 0x50, 0xe5, 0x37, 0x61, 0x4d, 0x1e, 0x2b, 0x16, 0xbd, 0x29, 0xe8, 0x6e, 0x91, 0x49,
+	0xf1, 0x4a, 0xac, 0xcb, 0x25, 0x58, 0xce, 0x65, 0x16, 0x45, 0x4b, 0xe4, 0x09, 0xec, 0xba, 0x38,
+	0x77, 0xcb, 0x67, 0x00, 0xdb, 0x1a, 0xc4, 0xac, 0x26, 0x8b, 0xb0, 0xb7, 0x80, 0xf0, 0x9c, 0xc5,
+	0x68, 0x31, 0x0e, 0xd6, 0x6c, 0x32, 0x5a, 0x22, 0x1f, 0x81, 0x3f, 0x40, 0x33, 0x1a, 0x6b, 0xaa,
+	0xb2, 0xfc, 0x2e, 0xd3, 0x12, 0x79, 0x0a, 0xdb, 0x45, 0x49, 0x2c, 0x81, 0x75, 0xf5, 0xb8, 0xb7,
+	0x5c, 0x8f, 0xd9, 0xe7, 0xbf, 0x00, 0x92, 0x7f, 0xde, 0xfd, 0x3d, 0x59, 0xcd, 0xa2, 0xb3, 0xc4,
+	0x42, 0x2e, 0x5c, 0x9e, 0xe7, 0xf0, 0x56, 0xc1, 0xe5, 0x8c, 0xc5, 0xf8, 0x1f, 0xe7, 0xfe, 0xb8,
+	0xfe, 0x63, 0x4d, 0x6b, 0xd3, 0xd1, 0xa8, 0x66, 0x96, 0xc5, 0x07, 0x7f, 0x0f, 0x00, 0x2e, 0x25,
+	0x45, 0xf8, 0x7f, 0x0b, 0x00, 0x00,
 }
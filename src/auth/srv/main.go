@@ -92,6 +92,7 @@ func init() {
 		orm.MysqlMaxIdleConns(authConf.MysqlMaxIdleConns),
 		orm.MysqlMaxOpenConns(authConf.MysqlMaxOpenConns),
 		orm.UsingOpentracingPlugin(),
+		orm.UsingDbStatsMetrics("mysql"),
 	), authLg)
 
 	authRedis = redis.NewRedisTool(
@@ -100,5 +101,6 @@ func init() {
 		authConf.Const.ServiceName,
 		authConf.RedisDb,
 		redis.UsingOpentracingHook(),
+		redis.UsingPoolStatsMetrics("redis"),
 	)
 }
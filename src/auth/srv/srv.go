@@ -9,8 +9,11 @@ import (
 	"eago/auth/srv/service"
 	"eago/common/broker"
 	"eago/common/logger"
+	"eago/common/metrics"
 	"eago/common/redis"
 	"eago/common/tracer"
+	"eago/common/wework"
+	"eago/task/worker"
 	"fmt"
 	"github.com/micro/go-micro/v2"
 	"github.com/micro/go-micro/v2/registry"
@@ -28,6 +31,11 @@ type authSrv struct {
 
 	biz *biz.Biz
 
+	// worker 执行Auth模块任务的Worker
+	worker worker.Worker
+	// orgSources 组织架构同步来源，Key为来源名称
+	orgSources map[string]biz.OrgSource
+
 	conf   *conf.Conf
 	logger *logger.Logger
 	tracer tracer.Tracer
@@ -71,6 +79,7 @@ func NewAuthSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 		micro.RegisterInterval(conf.MicroRegisterInterval),
 		micro.Context(ctx),
 		micro.WrapHandler(opentracing.NewHandlerWrapper(_tracer.GetTracer())),
+		micro.WrapHandler(metrics.NewHandlerWrapper()),
 		micro.Broker(_broker),
 	)
 
@@ -86,7 +95,7 @@ func NewAuthSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 
 	_ = authpb.RegisterAuthServiceHandler(_srv.Server(), service.NewAuthService(dao, redis, _biz, conf, logger))
 
-	return &authSrv{
+	as := &authSrv{
 		srv: _srv,
 
 		dao:   dao,
@@ -95,6 +104,14 @@ func NewAuthSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 
 		biz: _biz,
 
+		// LDAP等来源实现biz.OrgSource后在此注册
+		orgSources: map[string]biz.OrgSource{
+			biz.OrgSourceWework: biz.NewWeworkOrgSource(wework.NewWework(
+				conf.WeworkAgentId, conf.WeworkCorpId, conf.WeworkCorpSecret,
+				wework.Logger(logger),
+			)),
+		},
+
 		conf:   conf,
 		logger: logger,
 		tracer: _tracer,
@@ -102,15 +119,40 @@ func NewAuthSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 		ctx:        ctx,
 		cancelFunc: cancel,
 	}
+	as.worker = as.newAuthWorker()
+
+	return as
 }
 
 func (as *authSrv) Start() error {
 	as.logger.Info("Starting auth srv ...")
 
+	// 暴露Prometheus指标
+	go func() {
+		if err := metrics.Serve(as.ctx, as.conf.SrvMetricsListen); err != nil {
+			as.logger.ErrorWithFields(logger.Fields{
+				"address": as.conf.SrvMetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
+	// 启动任务Worker
+	go func() {
+		if err := as.worker.Start(); err != nil {
+			as.logger.ErrorWithFields(logger.Fields{
+				"error": err,
+			}, "An error occurred while worker.Start.")
+		}
+	}()
+
 	return as.srv.Run()
 }
 
 func (as *authSrv) Stop() {
+	if as.worker != nil {
+		as.worker.Stop()
+	}
 	if as.cancelFunc != nil {
 		as.cancelFunc()
 	}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"eago/auth/biz"
+	"eago/common/logger"
+	"eago/task/worker"
+	"encoding/json"
+	"fmt"
+)
+
+// orgSyncTaskCodename 组织架构同步任务代号，在任务模块中以Worker服务名为前缀，即"eago-auth.sync_org"
+const orgSyncTaskCodename = "sync_org"
+
+// orgSyncTaskFormalParams 组织架构同步任务形参
+const orgSyncTaskFormalParams = `{
+  "type": "object",
+  "properties": {
+    "source": {"type": "string", "default": "wework"},
+    "dry_run": {"type": "boolean", "default": false}
+  },
+  "additionalProperties": false
+}`
+
+// orgSyncArgs 组织架构同步任务实参
+type orgSyncArgs struct {
+	Source string `json:"source"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// newAuthWorker 生成Auth模块的任务Worker，通过任务模块的定时任务调度组织架构同步
+func (as *authSrv) newAuthWorker() worker.Worker {
+	wk := worker.NewWorker(
+		worker.EtcdAddresses(as.conf.EtcdAddresses),
+		worker.EtcdUsername(as.conf.EtcdUsername),
+		worker.EtcdPassword(as.conf.EtcdPassword),
+		worker.ServiceName(as.conf.Const.ServiceName),
+		worker.RegisterTtl(as.conf.WorkerRegisterTtl),
+		worker.Logger(as.logger),
+	)
+
+	wk.RegTask(
+		orgSyncTaskCodename, as.syncOrgTask,
+		worker.TaskDescription("从企业微信等组织架构来源同步部门、部门成员及部门Owner，dry_run为true时只输出差异"),
+		worker.TaskFormalParams(orgSyncTaskFormalParams),
+	)
+	return wk
+}
+
+// syncOrgTask 组织架构同步任务
+func (as *authSrv) syncOrgTask(ctx context.Context, param *worker.Param) error {
+	args := &orgSyncArgs{Source: biz.OrgSourceWework}
+	if param.Arguments != "" {
+		if err := json.Unmarshal([]byte(param.Arguments), args); err != nil {
+			param.Log.Error("Invalid arguments: %s", err)
+			return err
+		}
+	}
+
+	src, ok := as.orgSources[args.Source]
+	if !ok {
+		err := fmt.Errorf("unknown org source: %s", args.Source)
+		param.Log.Error(err.Error())
+		return err
+	}
+
+	param.Log.Info("Sync org from %s, dry run: %t.", args.Source, args.DryRun)
+	diff, err := as.biz.SyncOrg(ctx, src, args.DryRun)
+	if diff != nil {
+		for _, c := range diff.Changes {
+			param.Log.Info("[%s] %s %s: %s", c.Action, c.Object, c.Name, c.Detail)
+		}
+		if e := param.SetReturnValue(diff); e != nil {
+			as.logger.WarnWithFields(logger.Fields{
+				"error": e,
+			}, "An error occurred while param.SetReturnValue in syncOrgTask, skipped it.")
+		}
+	}
+	if err != nil {
+		param.Log.Error("Sync org failed: %s", err)
+		return err
+	}
+
+	param.Log.Info("Sync org finished with %d change(s).", len(diff.Changes))
+	return nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	authpb "eago/auth/proto"
 	"eago/common/global"
+	"eago/common/metrics"
 	"github.com/micro/go-micro/v2"
 	"github.com/micro/go-micro/v2/client"
 	"github.com/micro/go-micro/v2/registry"
@@ -17,6 +18,7 @@ func NewAuthClient(etcdUname, etcdPasswd string, etcdAddrs []string, cliOpt ...c
 	)
 	cli := micro.NewService(
 		micro.Registry(etcdReg),
+		micro.WrapClient(metrics.NewClientWrapper()),
 	)
 
 	_ = cli.Client().Init(cliOpt...)
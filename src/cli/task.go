@@ -2,6 +2,7 @@ package cli
 
 import (
 	"eago/common/global"
+	"eago/common/metrics"
 	taskpb "eago/task/proto"
 	"github.com/micro/go-micro/v2"
 	"github.com/micro/go-micro/v2/client"
@@ -17,6 +18,7 @@ func NewTaskClient(etcdUname, etcdPasswd string, etcdAddrs []string, cliOpt ...c
 	)
 	cli := micro.NewService(
 		micro.Registry(etcdReg),
+		micro.WrapClient(metrics.NewClientWrapper()),
 	)
 
 	_ = cli.Client().Init(cliOpt...)
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net"
+	"strings"
+)
+
+const (
+	// clientIpKey 客户端IP在gin.Context中的Key
+	clientIpKey = "client_ip"
+	// forwardedForHeader 代理转发时记录客户端地址的Header
+	forwardedForHeader = "X-Forwarded-For"
+)
+
+// ClientIpMiddleware 解析客户端IP，需放在其他中间件之前，之后通过ClientIp获取
+// 只有直连地址属于trustedProxies时才读取X-Forwarded-For，并从右向左取第一个不可信的地址，避免客户端伪造Header
+// trustedProxies为IP或CIDR，为空时只使用直连地址，格式错误时panic
+func ClientIpMiddleware(trustedProxies []string) gin.HandlerFunc {
+	cidrs := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, p := range trustedProxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, cidr, err := net.ParseCIDR(p)
+		if err != nil {
+			panic(fmt.Sprintf("invalid trusted proxy %s: %s", p, err))
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	trusted := func(ip net.IP) bool {
+		for _, cidr := range cidrs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		remoteIp, _ := c.RemoteIP()
+		if remoteIp == nil {
+			c.Next()
+			return
+		}
+
+		clientIp := remoteIp
+		if trusted(remoteIp) {
+			clientIp = forwardedClientIp(c.Request.Header.Values(forwardedForHeader), trusted, remoteIp)
+		}
+		c.Set(clientIpKey, clientIp.String())
+
+		c.Next()
+	}
+}
+
+// ClientIp 获得ClientIpMiddleware解析的客户端IP，未使用ClientIpMiddleware时返回直连地址
+func ClientIp(c *gin.Context) string {
+	if ip := c.GetString(clientIpKey); ip != "" {
+		return ip
+	}
+
+	if ip, _ := c.RemoteIP(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// forwardedClientIp 从右向左遍历X-Forwarded-For，返回第一个不可信的地址，全部可信时返回最左侧的地址
+// 出现无法解析的地址时，说明该段已不可信，返回其右侧的地址
+func forwardedClientIp(headers []string, trusted func(net.IP) bool, remoteIp net.IP) net.IP {
+	items := make([]string, 0)
+	for _, h := range headers {
+		items = append(items, strings.Split(h, ",")...)
+	}
+
+	clientIp := remoteIp
+	for i := len(items) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(items[i]))
+		if ip == nil {
+			break
+		}
+		clientIp = ip
+		if !trusted(ip) {
+			break
+		}
+	}
+	return clientIp
+}
@@ -29,7 +29,7 @@ func GinCustomLogger(lg *logger.Logger) gin.HandlerFunc {
 			lg.DebugWithFields(logger.Fields{
 				"status_code":   c.Writer.Status(),
 				"latency":       ts.Sub(start),
-				"client_ip":     c.ClientIP(),
+				"client_ip":     ClientIp(c),
 				"method":        c.Request.Method,
 				"path":          path,
 				"body_size":     c.Writer.Size(),
@@ -6,10 +6,18 @@ import (
 )
 
 const (
-	permKeyIsRole   = "is_role"
-	permKeyIsRoleIn = "is_role_in"
+	permKeyIsRole        = "is_role"
+	permKeyIsRoleIn      = "is_role_in"
+	permKeyHasPerm       = "has_perm"
+	permKeyHasPermOrRole = "has_perm_or_role"
 )
 
+// permOrRole 拥有权限或属于角色
+type permOrRole struct {
+	perm string
+	role string
+}
+
 type itemPerm struct {
 	key string
 	val interface{}
@@ -29,12 +37,36 @@ func NewPermIsRoleIn(rolesRequired []string) *itemPerm {
 	}
 }
 
+// NewPermHasPerm 拥有指定的全局权限时可见
+func NewPermHasPerm(permRequired string) *itemPerm {
+	return &itemPerm{
+		key: permKeyHasPerm,
+		val: permRequired,
+	}
+}
+
+// NewPermHasPermOrRole 拥有指定的全局权限或属于指定角色时可见，与permission.MustPermOrRole一致
+func NewPermHasPermOrRole(permRequired, roleRequired string) *itemPerm {
+	return &itemPerm{
+		key: permKeyHasPermOrRole,
+		val: &permOrRole{perm: permRequired, role: roleRequired},
+	}
+}
+
 func (perm *itemPerm) hasPerm(c *gin.Context) (bool, error) {
 	switch perm.key {
 	case permKeyIsRole:
 		return permission.IsRole(c, perm.val.(string))
 	case permKeyIsRoleIn:
 		return permission.IsRoleIn(c, perm.val.([]string))
+	case permKeyHasPerm:
+		return permission.HasPerm(c, perm.val.(string)), nil
+	case permKeyHasPermOrRole:
+		pr := perm.val.(*permOrRole)
+		if permission.HasPerm(c, pr.perm) {
+			return true, nil
+		}
+		return permission.IsRole(c, pr.role)
 	default:
 		return false, nil
 	}
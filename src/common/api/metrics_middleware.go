@@ -0,0 +1,20 @@
+package api
+
+import (
+	"eago/common/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// MetricsMiddleware 按路由记录请求数、状态码及耗时
+func MetricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	// 未匹配路由的请求统一记录，避免路径作为标签导致指标膨胀
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	metrics.ObserveHttpRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+}
@@ -1,13 +1,54 @@
 package api
 
 import (
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
 	"eago/common/global"
+	"eago/common/orm"
+	"eago/common/tracer"
 	"github.com/gin-gonic/gin"
 	"strconv"
 	"strings"
 )
 
 func PagingQueryMiddleware(c *gin.Context) {
+	if !setPagingQuery(c) {
+		return
+	}
+
+	c.Next()
+}
+
+// KeysetPagingQueryMiddleware 支持游标分页的分页中间件，sortable为允许排序的字段白名单
+// 请求中带有cursor参数(第一页为空值)时使用游标分页，skip_total=true时跳过总数查询
+func KeysetPagingQueryMiddleware(sortable ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !setPagingQuery(c, sortable...) {
+			return
+		}
+
+		if cursor, ok := c.GetQuery(global.GinParamCursorKey); ok {
+			if err := orm.CheckKeysetCursor(cursor, c.GetStringSlice(global.GinCtxOrderByKey), sortable...); err != nil {
+				m := cMsg.MsgValidateFailed
+				ext.WriteAnyAndAbortWithError(c, m.GetCode(), m.GetMsg(), err.Error())
+				return
+			}
+
+			skipTotal, _ := strconv.ParseBool(c.Query(global.GinParamSkipTotal))
+			ctx := orm.WithKeysetPaging(tracer.ExtractTraceCtxFromGin(c), &orm.KeysetPaging{
+				Cursor:    cursor,
+				SkipTotal: skipTotal,
+				Sortable:  sortable,
+			})
+			c.Set(global.OpentracingCtxKey, ctx)
+		}
+
+		c.Next()
+	}
+}
+
+// setPagingQuery 解析分页参数并写入gin.Context，排序字段不合法时返回false
+func setPagingQuery(c *gin.Context, sortable ...string) bool {
 	page, err := strconv.Atoi(c.DefaultQuery(global.GinParamPageKey, "1"))
 	if err != nil {
 		page = 1
@@ -27,7 +68,13 @@ func PagingQueryMiddleware(c *gin.Context) {
 	c.Set(global.GinCtxQueryKey, c.Query(global.GinParamQueryKey))
 
 	orderBy := strings.Split(c.DefaultQuery(global.GinParamOrderByKey, "id desc"), ",")
+	// 只允许"column [asc|desc]"格式的排序字段，避免拼接任意SQL
+	if _, err = orm.ParseOrderBy(orderBy, sortable...); err != nil {
+		m := cMsg.MsgValidateFailed
+		ext.WriteAnyAndAbortWithError(c, m.GetCode(), m.GetMsg(), err.Error())
+		return false
+	}
 	c.Set(global.GinCtxOrderByKey, orderBy)
 
-	c.Next()
+	return true
 }
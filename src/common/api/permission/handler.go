@@ -47,6 +47,13 @@ func getTokenContentHandler(c *gin.Context, token string, authCli authpb.AuthSer
 		return
 	}
 
+	// 访问令牌只能访问其允许的路由
+	if len(tc.Scopes) > 0 && !MatchScopes(tc.Scopes, c.Request.Method, c.FullPath()) {
+		m := cMsg.MsgTokenScopeFailed.SetDetail(c.Request.Method, c.FullPath())
+		ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+		return
+	}
+
 	// 成功，将TokenContent写入gin.Context
 	c.Set(defaultTokenContentGinCtxKey, tc)
 }
@@ -84,6 +84,91 @@ func MustCurrUserInGroupOrRole(groupIdField, role string, isOwner bool) gin.Hand
 	}
 }
 
+// MustParamRoleOrRole 检测当前用户是指定字段中的角色，或当前用户是指定角色
+func MustParamRoleOrRole(roleField, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := IsRole(c, c.Param(roleField))
+		if err != nil {
+			m := cMsg.MsgCheckRoleErr.SetError(err)
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+		if ok {
+			return
+		}
+
+		isRoleHandler(c, role)
+	}
+}
+
+// MustPerm 检测当前用户拥有指定的全局权限
+func MustPerm(perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPerm(c, perm) {
+			m := cMsg.MsgUserNotPermFailed.SetDetail(perm)
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+	}
+}
+
+// MustPermOrRole 检测当前用户拥有指定的全局权限，或当前用户是指定角色
+func MustPermOrRole(perm, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if HasPerm(c, perm) {
+			return
+		}
+
+		isRoleHandler(c, role)
+	}
+}
+
+// MustCurrUserInProductOrPermOrRole 检测当前用户必须在指定产品线内，或拥有指定权限(全局权限或该产品线范围的权限均可)，或当前用户是指定角色
+func MustCurrUserInProductOrPermOrRole(prodIdField, perm, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := IsCurrUserInProduct(c, prodIdField, false)
+		if err != nil {
+			m := cMsg.MsgInvalidUriFailed.SetError(err)
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+		if ok {
+			return
+		}
+
+		ok, err = HasProductPerm(c, perm, prodIdField)
+		if err != nil {
+			m := cMsg.MsgInvalidUriFailed.SetError(err)
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+		if ok {
+			return
+		}
+
+		isRoleHandler(c, role)
+	}
+}
+
+// MustMfa 检测当前Token通过了两步验证，访问令牌不会通过两步验证
+func MustMfa() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsMfa(c) {
+			m := cMsg.MsgMfaRequiredFailed
+			ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+			return
+		}
+	}
+}
+
+// MustMfaIf enabled为true时等同于MustMfa，否则不做检测，用于按配置开启
+func MustMfaIf(enabled bool) gin.HandlerFunc {
+	if enabled {
+		return MustMfa()
+	}
+	return func(c *gin.Context) {}
+}
+
 // MustLogin 验证是否登录并装载TokenContent
 func MustLogin(authCli authpb.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
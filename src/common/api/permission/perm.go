@@ -0,0 +1,73 @@
+package permission
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// permWildcard 权限中的通配段，匹配该段的任意值
+	permWildcard = "*"
+	// permSegments 权限的段数，格式为service:resource:action
+	permSegments = 3
+	// permProductSeparator TokenContent中产品线范围权限的分隔符，格式为<权限>@<产品线ID>
+	permProductSeparator = "@"
+)
+
+var permRegexp = regexp.MustCompile(`^(\*|[a-z][a-z0-9_]*)(:(\*|[a-z][a-z0-9_]*)){2}$`)
+
+// IsValidPerm 判断权限格式是否合法，格式为service:resource:action，每段可为"*"
+func IsValidPerm(perm string) bool {
+	return permRegexp.MatchString(perm)
+}
+
+// ProductPerm 生成产品线范围的权限，用于写入TokenContent
+func ProductPerm(perm string, productId uint32) string {
+	return fmt.Sprintf("%s%s%d", perm, permProductSeparator, productId)
+}
+
+// matchPerms 判断已授予的权限中是否包含所需权限
+// productId为0时只匹配全局权限，否则同时匹配全局权限及该产品线范围的权限
+func matchPerms(granted []string, required string, productId uint32) bool {
+	for _, g := range granted {
+		perm, prodId := splitProductPerm(g)
+		if prodId != 0 && prodId != productId {
+			continue
+		}
+		if matchPerm(perm, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPerm 逐段匹配权限，已授予权限中的"*"匹配任意值
+func matchPerm(granted, required string) bool {
+	gs := strings.Split(granted, ":")
+	rs := strings.Split(required, ":")
+	if len(gs) != permSegments || len(rs) != permSegments {
+		return false
+	}
+	for i := range gs {
+		if gs[i] != permWildcard && gs[i] != rs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitProductPerm 拆分产品线范围的权限，全局权限的产品线ID为0
+func splitProductPerm(perm string) (string, uint32) {
+	idx := strings.Index(perm, permProductSeparator)
+	if idx < 0 {
+		return perm, 0
+	}
+	prodId, err := strconv.ParseUint(perm[idx+1:], 10, 32)
+	if err != nil || prodId == 0 {
+		// 无法解析的产品线范围权限不授予任何权限
+		return "", ^uint32(0)
+	}
+	return perm[:idx], uint32(prodId)
+}
@@ -0,0 +1,72 @@
+package permission
+
+import (
+	"testing"
+)
+
+func TestSplitProductPerm(t *testing.T) {
+	cases := []struct {
+		perm     string
+		wantPerm string
+		wantProd uint32
+	}{
+		{"task:schedule:read", "task:schedule:read", 0},
+		{"task:schedule:read@12", "task:schedule:read", 12},
+		{"*:*:*@4294967295", "*:*:*", 4294967295},
+		// 非法的产品线范围不授予任何权限
+		{"task:schedule:read@0", "", ^uint32(0)},
+		{"task:schedule:read@", "", ^uint32(0)},
+		{"task:schedule:read@abc", "", ^uint32(0)},
+		{"task:schedule:read@-1", "", ^uint32(0)},
+		{"task:schedule:read@4294967296", "", ^uint32(0)},
+		{"task:schedule:read@1@2", "", ^uint32(0)},
+	}
+
+	for _, tc := range cases {
+		perm, prod := splitProductPerm(tc.perm)
+		if perm != tc.wantPerm || prod != tc.wantProd {
+			t.Errorf("splitProductPerm(%q) = (%q, %d), want (%q, %d)", tc.perm, perm, prod, tc.wantPerm, tc.wantProd)
+		}
+	}
+}
+
+func TestMatchPerms(t *testing.T) {
+	const required = "task:schedule:read"
+
+	cases := []struct {
+		name      string
+		granted   []string
+		productId uint32
+		want      bool
+	}{
+		{"none", nil, 0, false},
+		{"exact", []string{required}, 0, true},
+		{"other action", []string{"task:schedule:write"}, 0, false},
+		{"all wildcard", []string{"*:*:*"}, 0, true},
+		{"resource wildcard", []string{"task:*:read"}, 0, true},
+		{"action wildcard", []string{"task:schedule:*"}, 0, true},
+		{"wildcard other service", []string{"auth:*:*"}, 0, false},
+		{"too few segments", []string{"task:schedule"}, 0, false},
+		{"too many segments", []string{"task:schedule:read:x"}, 0, false},
+		{"global matches product", []string{required}, 7, true},
+		{"product scope", []string{required + "@7"}, 7, true},
+		{"product wildcard", []string{"task:*:*@7"}, 7, true},
+		{"other product", []string{required + "@8"}, 7, false},
+		{"product scope not global", []string{required + "@7"}, 0, false},
+		{"zero product", []string{required + "@0"}, 0, false},
+		{"zero product wildcard", []string{"*:*:*@0"}, 7, false},
+		{"malformed product", []string{required + "@abc"}, 7, false},
+		{"empty product", []string{required + "@"}, 7, false},
+		{"negative product", []string{required + "@-1"}, 7, false},
+		{"malformed product max id", []string{"*:*:*@abc"}, ^uint32(0), false},
+		{"one of many", []string{"auth:user:read", required + "@8", required + "@7"}, 7, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchPerms(tc.granted, required, tc.productId); got != tc.want {
+				t.Errorf("matchPerms(%v, %q, %d) = %v, want %v", tc.granted, required, tc.productId, got, tc.want)
+			}
+		})
+	}
+}
@@ -98,6 +98,61 @@ func IsRole(c *gin.Context, roleRequired string) (bool, error) {
 	return utils.IsInSlice(tc.Roles, roleRequired)
 }
 
+// IsMfa 判断当前Token是否通过了两步验证，超级用户同样需要通过
+func IsMfa(c *gin.Context) bool {
+	val, exists := c.Get(defaultTokenContentGinCtxKey)
+	if !exists || val == nil {
+		return false
+	}
+	return val.(*authpb.TokenContent).Mfa
+}
+
+// IsAccessToken 判断当前Token是否为访问令牌，访问令牌总是带有路由范围
+func IsAccessToken(c *gin.Context) bool {
+	val, exists := c.Get(defaultTokenContentGinCtxKey)
+	if !exists || val == nil {
+		return false
+	}
+	return len(val.(*authpb.TokenContent).Scopes) > 0
+}
+
+// HasPerm 判断当前用户是否拥有指定的全局权限
+func HasPerm(c *gin.Context, permRequired string) bool {
+	val, exists := c.Get(defaultTokenContentGinCtxKey)
+	if !exists || val == nil {
+		return false
+	}
+	tc := val.(*authpb.TokenContent)
+
+	// 超级用户拥有所有权限，跳过判断
+	if tc.IsSuperuser {
+		return true
+	}
+
+	return matchPerms(tc.Permissions, permRequired, 0)
+}
+
+// HasProductPerm 判断当前用户是否拥有指定权限，全局权限或指定产品线范围的权限均可
+func HasProductPerm(c *gin.Context, permRequired string, prodIdField string) (bool, error) {
+	val, exists := c.Get(defaultTokenContentGinCtxKey)
+	if !exists || val == nil {
+		return false, nil
+	}
+	tc := val.(*authpb.TokenContent)
+
+	// 超级用户拥有所有权限，跳过判断
+	if tc.IsSuperuser {
+		return true, nil
+	}
+
+	prodId, err := ext.ParamUint32(c, prodIdField)
+	if err != nil {
+		return false, err
+	}
+
+	return matchPerms(tc.Permissions, permRequired, prodId), nil
+}
+
 // IsRoleIn 判断当前用户的角色，是否是指定角色数组中的任意一个的处理
 func IsRoleIn(c *gin.Context, rolesRequired []string) (res bool, err error) {
 	val, exists := c.Get(defaultTokenContentGinCtxKey)
@@ -0,0 +1,63 @@
+package permission
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// scopeAny 允许访问所有路由
+	scopeAny = "*"
+	// scopeWildcard 路由以该字符结尾时按前缀匹配
+	scopeWildcard = "*"
+)
+
+var scopeMethods = []string{
+	scopeAny, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// IsValidScope 判断访问令牌的路由范围是否合法
+// 格式为"*"或"<METHOD> <PATH>"，METHOD可为"*"，PATH与注册的路由一致，如"POST /task/tasks/:task_id/call"，以"*"结尾时按前缀匹配
+func IsValidScope(scope string) bool {
+	if scope == scopeAny {
+		return true
+	}
+
+	method, path, ok := splitScope(scope)
+	if !ok || !strings.HasPrefix(path, "/") {
+		return false
+	}
+	for _, m := range scopeMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchScopes 判断请求是否在访问令牌允许的路由范围内，fullPath为gin注册的路由
+func MatchScopes(scopes []string, method, fullPath string) bool {
+	for _, scope := range scopes {
+		if scope == scopeAny {
+			return true
+		}
+
+		m, p, ok := splitScope(scope)
+		if !ok || (m != scopeAny && m != method) {
+			continue
+		}
+		if p == fullPath || strings.HasSuffix(p, scopeWildcard) && strings.HasPrefix(fullPath, strings.TrimSuffix(p, scopeWildcard)) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitScope 拆分路由范围为METHOD及PATH
+func splitScope(scope string) (string, string, bool) {
+	fields := strings.Fields(scope)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	return strings.ToUpper(fields[0]), fields[1], true
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"bytes"
+	"eago/common/api"
+	"eago/common/api/ext"
+	perm "eago/common/api/permission"
+	cMsg "eago/common/code_msg"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// maxJsonBodySize ByJsonField读取的请求体最大长度
+const maxJsonBodySize = 64 * 1024
+
+// KeyFunc 从请求中获得限流的Key，返回空字符串时不限流，请求不合法时可直接返回错误并Abort
+type KeyFunc func(c *gin.Context) string
+
+// ByIp 按客户端IP限流，客户端IP由api.ClientIpMiddleware按可信代理解析
+func ByIp(c *gin.Context) string {
+	return "ip/" + api.ClientIp(c)
+}
+
+// ByUser 按当前登录用户限流，需在MustLogin之后使用
+func ByUser(c *gin.Context) string {
+	tc, ok := perm.GetTokenContent(c)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("user/%d", tc.UserId)
+}
+
+// ByJsonField 按请求体中指定字段的值限流，忽略大小写及首尾空格，如登录时按用户名限流
+// 请求体超过maxJsonBodySize时拒绝请求，避免通过超大请求体绕过限流
+func ByJsonField(field string) KeyFunc {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+		if c.Request.ContentLength > maxJsonBodySize {
+			writeTooLarge(c)
+			return ""
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, maxJsonBodySize+1))
+		// 还原请求体供后续处理函数使用
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return ""
+		}
+		if len(body) > maxJsonBodySize {
+			writeTooLarge(c)
+			return ""
+		}
+
+		obj := make(map[string]interface{})
+		if err = json.Unmarshal(body, &obj); err != nil {
+			return ""
+		}
+		val, ok := obj[field].(string)
+		if !ok {
+			return ""
+		}
+		val = strings.ToLower(strings.TrimSpace(val))
+		if val == "" {
+			return ""
+		}
+		return field + "/" + val
+	}
+}
+
+// writeTooLarge 返回请求体过大
+func writeTooLarge(c *gin.Context) {
+	m := cMsg.MsgRequestTooLargeFailed.SetDetail(strconv.Itoa(maxJsonBodySize))
+	ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+}
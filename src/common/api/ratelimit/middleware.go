@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"bytes"
+	"eago/common/api"
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/logger"
+	"eago/common/tracer"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxResponseSize 判断请求是否失败时读取的返回内容最大长度
+const maxResponseSize = 4 * 1024
+
+// Middleware 限流中间件，keyFunc返回空字符串时不限流，keyFunc中Abort时直接返回
+// 请求放行前先原子地预留本次访问，避免并发请求在处理完成前同时通过检查
+// FailuresOnly时请求成功(HTTP状态码<400且code为0)后撤销预留，只统计失败的请求
+// Redis异常时放行请求，避免限流器成为单点
+func (l *limiter) Middleware(keyFunc KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.opts.Limit < 1 {
+			c.Next()
+			return
+		}
+
+		key := keyFunc(c)
+		if c.IsAborted() {
+			return
+		}
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := tracer.ExtractTraceCtxFromGin(c)
+		res, err := l.Reserve(ctx, key)
+		if err != nil {
+			l.opts.Logger.WarnWithFields(logger.Fields{
+				"limiter": l.name,
+				"key":     key,
+				"error":   err,
+			}, "An error occurred while limiter.Reserve in limiter.Middleware, but skipped.")
+			c.Next()
+			return
+		}
+		if !res.Allowed {
+			l.writeLimited(c, key, res)
+			return
+		}
+
+		if !l.opts.FailuresOnly {
+			l.commit(c, key)
+			c.Next()
+			return
+		}
+
+		w := &resultWriter{ResponseWriter: c.Writer, buffer: &bytes.Buffer{}}
+		c.Writer = w
+
+		c.Next()
+
+		if w.failed() {
+			l.commit(c, key)
+			return
+		}
+
+		// 请求成功，撤销预留的访问，ResetOnSuccess时同时清除之前的失败记录
+		if l.opts.ResetOnSuccess && res.Count > 1 {
+			err = l.Reset(ctx, key)
+		} else {
+			err = l.Cancel(ctx, key, res)
+		}
+		if err != nil {
+			l.opts.Logger.WarnWithFields(logger.Fields{
+				"limiter": l.name,
+				"key":     key,
+				"error":   err,
+			}, "An error occurred while limiter.Cancel in limiter.Middleware, but skipped.")
+		}
+	}
+}
+
+// commit 确认预留的访问，触发锁定时发布事件
+func (l *limiter) commit(c *gin.Context, key string) {
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+	res, err := l.Commit(ctx, key)
+	if err != nil {
+		l.opts.Logger.WarnWithFields(logger.Fields{
+			"limiter": l.name,
+			"key":     key,
+			"error":   err,
+		}, "An error occurred while limiter.Commit in limiter.commit, but skipped.")
+		return
+	}
+
+	if res.Locked {
+		l.publishLockout(ctx, key, res, map[string]interface{}{
+			"ip":     api.ClientIp(c),
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+		})
+	}
+}
+
+// writeLimited 返回限流信息，并设置Retry-After
+func (l *limiter) writeLimited(c *gin.Context, key string, res *Result) {
+	secs := strconv.FormatInt(int64(math.Ceil(res.RetryAfter.Seconds())), 10)
+	c.Header("Retry-After", secs)
+
+	m := cMsg.MsgTooManyRequestsFailed
+	if res.Locked {
+		m = cMsg.MsgRateLimitLockedFailed
+	}
+	m = m.SetDetail(secs)
+
+	logF := m.ToLoggerFields()
+	logF["limiter"] = l.name
+	logF["key"] = key
+	logF["retry_after"] = res.RetryAfter.Round(time.Millisecond).String()
+	l.opts.Logger.WarnWithFields(logF, "Request rejected by limiter.Middleware.")
+	ext.WriteAnyAndAbort(c, m.GetCode(), m.GetMsg())
+}
+
+// resultWriter 记录返回内容的Writer，用于判断请求是否失败，超过maxResponseSize的部分不记录
+type resultWriter struct {
+	gin.ResponseWriter
+	buffer *bytes.Buffer
+}
+
+// Write
+func (w *resultWriter) Write(b []byte) (int, error) {
+	if w.buffer.Len()+len(b) <= maxResponseSize {
+		w.buffer.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// failed 请求是否失败，返回内容无法解析时只根据HTTP状态码判断
+func (w *resultWriter) failed() bool {
+	if w.Status() >= http.StatusBadRequest {
+		return true
+	}
+
+	resp := make(map[string]interface{})
+	_ = json.Unmarshal(w.buffer.Bytes(), &resp)
+	code, _ := resp["code"].(float64)
+	return code != 0
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"eago/common/broker"
+	"eago/common/logger"
+	"time"
+)
+
+const (
+	defaultLimit  = 10
+	defaultWindow = time.Minute
+)
+
+type Option func(o *Options)
+
+// Option struct
+type Options struct {
+	// Limit 滑动窗口内允许的最大次数，小于1时不限流
+	Limit int
+	// Window 滑动窗口长度
+	Window time.Duration
+	// Lockout 达到Limit后的锁定时长，为0时不锁定，只拒绝超出部分直到窗口滑过
+	Lockout time.Duration
+
+	// DelayAfter 窗口内达到该次数后，每次需等待的时间从DelayBase开始翻倍，最长DelayMax，为0时不延迟
+	DelayAfter int
+	DelayBase  time.Duration
+	DelayMax   time.Duration
+
+	// FailuresOnly 只统计失败的请求，用于登录等防暴力破解的场景
+	FailuresOnly bool
+	// ResetOnSuccess 请求成功时清除已统计的次数，仅在FailuresOnly时生效
+	ResetOnSuccess bool
+
+	// Publisher 不为空时在锁定时发布Lockout事件
+	Publisher broker.Publisher
+	Logger    *logger.Logger
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		Limit:  defaultLimit,
+		Window: defaultWindow,
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	if opt.Logger == nil {
+		opt.Logger = logger.NewDefaultLogger()
+	}
+
+	return opt
+}
+
+// Limit 设置Limit
+func Limit(in int) Option {
+	return func(o *Options) {
+		o.Limit = in
+	}
+}
+
+// Window 设置Window
+func Window(in time.Duration) Option {
+	return func(o *Options) {
+		o.Window = in
+	}
+}
+
+// Lockout 设置Lockout
+func Lockout(in time.Duration) Option {
+	return func(o *Options) {
+		o.Lockout = in
+	}
+}
+
+// Delay 设置渐进延迟，after为0时不延迟
+func Delay(after int, base, max time.Duration) Option {
+	return func(o *Options) {
+		o.DelayAfter = after
+		o.DelayBase = base
+		o.DelayMax = max
+	}
+}
+
+// FailuresOnly 设置FailuresOnly为true
+func FailuresOnly() Option {
+	return func(o *Options) {
+		o.FailuresOnly = true
+	}
+}
+
+// ResetOnSuccess 设置ResetOnSuccess为true
+func ResetOnSuccess() Option {
+	return func(o *Options) {
+		o.ResetOnSuccess = true
+	}
+}
+
+// Publisher 设置Publisher
+func Publisher(in broker.Publisher) Option {
+	return func(o *Options) {
+		o.Publisher = in
+	}
+}
+
+// Logger 设置Logger
+func Logger(in *logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = in
+	}
+}
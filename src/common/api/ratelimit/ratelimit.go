@@ -0,0 +1,267 @@
+package ratelimit
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/redis"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math/rand"
+	"time"
+)
+
+const (
+	keyPrefix  = "ratelimit"
+	lockSuffix = "lock"
+
+	// brokerModel 发布锁定事件时使用的模块名
+	brokerModel = "ratelimit"
+	// EventLockout 锁定事件
+	EventLockout = "Lockout"
+)
+
+// reserveScript 检查是否允许访问，允许时记录本次访问，检查与记录在同一脚本中原子执行，避免并发请求同时越过限制
+// 返回{是否允许, 是否锁定中, 需等待毫秒, 窗口内次数}
+var reserveScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local ttl = redis.call('PTTL', KEYS[2])
+if ttl > 0 then
+	return {0, 1, ttl, 0}
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count >= tonumber(ARGV[3]) then
+	local first = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	return {0, 0, tonumber(first[2]) + window - now, count}
+end
+local after = tonumber(ARGV[5])
+local delay = tonumber(ARGV[6])
+if after > 0 and delay > 0 and count >= after then
+	local max = tonumber(ARGV[7])
+	for i = after + 1, count do
+		delay = delay * 2
+		if max > 0 and delay >= max then
+			delay = max
+			break
+		end
+	end
+	local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+	local wait = tonumber(last[2]) + delay - now
+	if wait > 0 then
+		return {0, 0, wait, count}
+	end
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return {1, 0, 0, count + 1}
+`)
+
+// commitScript 窗口内次数达到限制且需要锁定时设置锁定并清空窗口
+// 返回{窗口内次数, 是否本次锁定}
+var commitScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+local count = redis.call('ZCARD', KEYS[1])
+if tonumber(ARGV[4]) > 0 and count >= tonumber(ARGV[3]) then
+	redis.call('SET', KEYS[2], count, 'PX', ARGV[4])
+	redis.call('DEL', KEYS[1])
+	return {count, 1}
+end
+return {count, 0}
+`)
+
+// cancelScript 删除Reserve记录的访问
+var cancelScript = redis.NewScript(`
+return redis.call('ZREM', KEYS[1], ARGV[1])
+`)
+
+// Limiter 基于Redis滑动窗口的限流器，多个实例共享同一Redis时限制全局生效
+type Limiter interface {
+	// Reserve 检查key当前是否允许访问，允许时原子地记录本次访问
+	Reserve(ctx context.Context, key string) (*Result, error)
+	// Commit 确认Reserve记录的访问，窗口内次数达到限制时按配置锁定
+	Commit(ctx context.Context, key string) (*Result, error)
+	// Cancel 撤销Reserve记录的访问，如只统计失败的请求时请求成功
+	Cancel(ctx context.Context, key string, res *Result) error
+	// Reset 清除key的访问记录及锁定
+	Reset(ctx context.Context, key string) error
+	// Middleware 限流中间件，keyFunc返回空字符串时不限流
+	Middleware(keyFunc KeyFunc) gin.HandlerFunc
+}
+
+// Result 限流结果
+type Result struct {
+	// Count 窗口内已记录的次数，包括本次访问
+	Count int
+	// Allowed 是否允许本次访问
+	Allowed bool
+	// Locked 是否处于锁定状态，Commit返回时表示本次触发了锁定
+	Locked bool
+	// RetryAfter 不允许访问时需等待的时间
+	RetryAfter time.Duration
+
+	// member Reserve记录的访问，用于Cancel
+	member string
+}
+
+type limiter struct {
+	name  string
+	redis *redis.RedisTool
+
+	opts Options
+	// now 当前时间，测试时可替换
+	now func() time.Time
+}
+
+// NewLimiter 创建Limiter，name用于区分不同的限流场景，如login_ip
+func NewLimiter(redis *redis.RedisTool, name string, opts ...Option) Limiter {
+	return &limiter{
+		name:  name,
+		redis: redis,
+
+		opts: newOptions(opts...),
+		now:  time.Now,
+	}
+}
+
+// Reserve 检查key当前是否允许访问，允许时原子地记录本次访问
+// 处于锁定中、窗口内已达到限制或距上次访问不足渐进延迟时不允许，并返回需等待的时间
+func (l *limiter) Reserve(ctx context.Context, key string) (*Result, error) {
+	if l.opts.Limit < 1 {
+		return &Result{Allowed: true}, nil
+	}
+
+	now := l.now()
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), rand.Int63())
+	val, err := l.redis.RunScript(ctx, reserveScript, l.genKeys(key),
+		toMillis(now), l.opts.Window.Milliseconds(), l.opts.Limit, member,
+		l.opts.DelayAfter, l.opts.DelayBase.Milliseconds(), l.opts.DelayMax.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	vals, ok := toInt64s(val, 4)
+	if !ok {
+		return nil, fmt.Errorf("unexpected script result %v", val)
+	}
+
+	res := &Result{
+		Count:      int(vals[3]),
+		Allowed:    vals[0] == 1,
+		Locked:     vals[1] == 1,
+		RetryAfter: time.Duration(vals[2]) * time.Millisecond,
+	}
+	if res.Allowed {
+		res.member = member
+	}
+	return res, nil
+}
+
+// Commit 确认Reserve记录的访问，窗口内次数达到限制时按配置锁定
+func (l *limiter) Commit(ctx context.Context, key string) (*Result, error) {
+	if l.opts.Limit < 1 {
+		return &Result{Allowed: true}, nil
+	}
+
+	val, err := l.redis.RunScript(ctx, commitScript, l.genKeys(key),
+		toMillis(l.now()), l.opts.Window.Milliseconds(), l.opts.Limit, l.opts.Lockout.Milliseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	vals, ok := toInt64s(val, 2)
+	if !ok {
+		return nil, fmt.Errorf("unexpected script result %v", val)
+	}
+
+	res := &Result{Count: int(vals[0]), Allowed: true}
+	if vals[1] == 1 {
+		res.Allowed = false
+		res.Locked = true
+		res.RetryAfter = l.opts.Lockout
+	}
+	return res, nil
+}
+
+// Cancel 撤销Reserve记录的访问，如只统计失败的请求时请求成功
+func (l *limiter) Cancel(ctx context.Context, key string, res *Result) error {
+	if res == nil || res.member == "" {
+		return nil
+	}
+
+	_, err := l.redis.RunScript(ctx, cancelScript, l.genKeys(key)[:1], res.member)
+	return err
+}
+
+// Reset 清除key的访问记录及锁定
+func (l *limiter) Reset(ctx context.Context, key string) error {
+	keys := l.genKeys(key)
+	for _, k := range keys {
+		if err := l.redis.Del(ctx, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishLockout 发布锁定事件，失败时只记录日志
+func (l *limiter) publishLockout(ctx context.Context, key string, res *Result, extra map[string]interface{}) {
+	l.opts.Logger.WarnWithFields(logger.Fields{
+		"limiter": l.name,
+		"key":     key,
+		"count":   res.Count,
+		"lockout": l.opts.Lockout.String(),
+	}, "Rate limit lockout triggered.")
+
+	if l.opts.Publisher == nil {
+		return
+	}
+
+	body := map[string]interface{}{
+		"limiter":      l.name,
+		"key":          key,
+		"count":        res.Count,
+		"lockout_secs": int64(l.opts.Lockout.Seconds()),
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+
+	srvName := l.opts.Publisher.GetServiceName()
+	if err := l.opts.Publisher.Publish(ctx, brokerModel, srvName, brokerModel, EventLockout, body); err != nil {
+		l.opts.Logger.WarnWithFields(logger.Fields{
+			"limiter": l.name,
+			"key":     key,
+			"error":   err,
+		}, "An error occurred while Publisher.Publish in limiter.publishLockout, but skipped.")
+	}
+}
+
+// genKeys 生成窗口及锁定的Key
+func (l *limiter) genKeys(key string) []string {
+	base := keyPrefix + "/" + l.name + "/" + key
+	return []string{base, base + "/" + lockSuffix}
+}
+
+// toMillis time.Time转换为毫秒时间戳
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// toInt64s 转换脚本返回的数组
+func toInt64s(val interface{}, n int) ([]int64, bool) {
+	arr, ok := val.([]interface{})
+	if !ok || len(arr) != n {
+		return nil, false
+	}
+
+	res := make([]int64, 0, n)
+	for _, v := range arr {
+		i, ok := v.(int64)
+		if !ok {
+			return nil, false
+		}
+		res = append(res, i)
+	}
+	return res, true
+}
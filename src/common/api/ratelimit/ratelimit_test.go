@@ -0,0 +1,334 @@
+package ratelimit
+
+import (
+	"context"
+	cMsg "eago/common/code_msg"
+	"eago/common/redis"
+	"encoding/json"
+	"github.com/alicebob/miniredis"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClock 测试用时钟，同时推进miniredis中Key的过期时间
+type testClock struct {
+	mr  *miniredis.Miniredis
+	now time.Time
+}
+
+func (tc *testClock) Now() time.Time {
+	return tc.now
+}
+
+func (tc *testClock) Advance(d time.Duration) {
+	tc.now = tc.now.Add(d)
+	tc.mr.FastForward(d)
+}
+
+// newTestLimiter 创建使用miniredis及测试时钟的limiter
+func newTestLimiter(t *testing.T, opts ...Option) (*limiter, *testClock) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := redis.NewRedisTool(mr.Addr(), "", "test", 0)
+	t.Cleanup(func() {
+		rt.Close()
+		mr.Close()
+	})
+
+	clock := &testClock{mr: mr, now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLimiter(rt, "test", opts...).(*limiter)
+	l.now = clock.Now
+	return l, clock
+}
+
+func mustReserve(t *testing.T, l *limiter) *Result {
+	t.Helper()
+	res, err := l.Reserve(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func mustCommit(t *testing.T, l *limiter) *Result {
+	t.Helper()
+	res, err := l.Commit(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestReserveWindowLimit(t *testing.T) {
+	l, clock := newTestLimiter(t, Limit(3), Window(time.Minute))
+
+	for i := 1; i <= 3; i++ {
+		if res := mustReserve(t, l); !res.Allowed || res.Count != i {
+			t.Fatalf("reserve %d: %+v, want allowed with count %d", i, res, i)
+		}
+		clock.Advance(10 * time.Second)
+	}
+
+	// 最早的访问在30s后滑出窗口
+	res := mustReserve(t, l)
+	if res.Allowed || res.Locked || res.RetryAfter != 30*time.Second || res.Count != 3 {
+		t.Fatalf("reserve over limit: %+v, want rejected for 30s", res)
+	}
+
+	clock.Advance(30 * time.Second)
+	if res = mustReserve(t, l); !res.Allowed || res.Count != 3 {
+		t.Fatalf("reserve after window slid: %+v, want allowed with count 3", res)
+	}
+}
+
+func TestCommitLockout(t *testing.T) {
+	l, clock := newTestLimiter(t, Limit(2), Window(time.Minute), Lockout(5*time.Minute))
+
+	mustReserve(t, l)
+	if res := mustCommit(t, l); res.Locked || !res.Allowed || res.Count != 1 {
+		t.Fatalf("first commit: %+v, want not locked", res)
+	}
+
+	mustReserve(t, l)
+	res := mustCommit(t, l)
+	if !res.Locked || res.Allowed || res.RetryAfter != 5*time.Minute || res.Count != 2 {
+		t.Fatalf("second commit: %+v, want locked for 5m", res)
+	}
+
+	res = mustReserve(t, l)
+	if res.Allowed || !res.Locked || res.RetryAfter != 5*time.Minute {
+		t.Fatalf("reserve while locked: %+v, want rejected for 5m", res)
+	}
+
+	clock.Advance(4 * time.Minute)
+	if res = mustReserve(t, l); res.Allowed || !res.Locked || res.RetryAfter != time.Minute {
+		t.Fatalf("reserve while locked: %+v, want rejected for 1m", res)
+	}
+
+	// 锁定时已清空窗口，解锁后重新计数
+	clock.Advance(time.Minute)
+	if res = mustReserve(t, l); !res.Allowed || res.Count != 1 {
+		t.Fatalf("reserve after lockout: %+v, want allowed with count 1", res)
+	}
+}
+
+func TestCommitWithoutLockout(t *testing.T) {
+	l, _ := newTestLimiter(t, Limit(2), Window(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		mustReserve(t, l)
+		if res := mustCommit(t, l); res.Locked {
+			t.Fatalf("commit %d: %+v, want not locked", i, res)
+		}
+	}
+
+	if res := mustReserve(t, l); res.Allowed || res.Locked || res.RetryAfter != time.Minute {
+		t.Fatalf("reserve over limit: %+v, want rejected by window only", res)
+	}
+}
+
+func TestReserveDelay(t *testing.T) {
+	l, clock := newTestLimiter(t, Limit(10), Window(time.Minute), Delay(2, time.Second, 3*time.Second))
+
+	// 达到DelayAfter前不延迟
+	for i := 0; i < 2; i++ {
+		if res := mustReserve(t, l); !res.Allowed {
+			t.Fatalf("reserve %d: %+v, want allowed without delay", i, res)
+		}
+	}
+
+	// 从DelayBase开始翻倍，最长DelayMax
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		res := mustReserve(t, l)
+		if res.Allowed || res.Locked || res.RetryAfter != want {
+			t.Fatalf("reserve before delay: %+v, want rejected for %s", res, want)
+		}
+
+		clock.Advance(want - time.Millisecond)
+		if res = mustReserve(t, l); res.Allowed || res.RetryAfter != time.Millisecond {
+			t.Fatalf("reserve just before delay: %+v, want rejected for 1ms", res)
+		}
+
+		clock.Advance(time.Millisecond)
+		if res = mustReserve(t, l); !res.Allowed {
+			t.Fatalf("reserve after delay %s: %+v, want allowed", want, res)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	l, _ := newTestLimiter(t, Limit(2), Window(time.Minute))
+	ctx := context.Background()
+
+	res := mustReserve(t, l)
+	if err := l.Cancel(ctx, "k", res); err != nil {
+		t.Fatal(err)
+	}
+
+	// 撤销后不占用次数
+	for i := 1; i <= 2; i++ {
+		if res = mustReserve(t, l); !res.Allowed || res.Count != i {
+			t.Fatalf("reserve %d after cancel: %+v, want allowed with count %d", i, res, i)
+		}
+	}
+
+	// 未被允许的访问没有记录，撤销时不影响已记录的访问
+	res = mustReserve(t, l)
+	if res.Allowed {
+		t.Fatalf("reserve over limit: %+v, want rejected", res)
+	}
+	if err := l.Cancel(ctx, "k", res); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Cancel(ctx, "k", nil); err != nil {
+		t.Fatal(err)
+	}
+	if res = mustReserve(t, l); res.Allowed {
+		t.Fatalf("reserve after cancelling a rejected result: %+v, want rejected", res)
+	}
+}
+
+// doRequest 发起请求，fail为true时处理函数返回失败，返回响应及处理函数是否被调用
+func doRequest(t *testing.T, engine *gin.Engine, fail bool) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+
+	path := "/ok"
+	if fail {
+		path = "/fail"
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+	rsp := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	return w, rsp.Code == 0 || rsp.Code == 1
+}
+
+// newTestEngine 创建使用限流中间件的gin.Engine，/ok返回成功，/fail返回失败
+func newTestEngine(l *limiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+
+	mw := l.Middleware(func(c *gin.Context) string { return "k" })
+	engine.POST("/ok", mw, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": 0})
+	})
+	engine.POST("/fail", mw, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": 1})
+	})
+	return engine
+}
+
+func TestMiddlewareFailuresOnly(t *testing.T) {
+	l, _ := newTestLimiter(t, Limit(2), Window(time.Minute), FailuresOnly())
+	engine := newTestEngine(l)
+
+	// 成功的请求撤销预留，不计入次数
+	for i := 0; i < 5; i++ {
+		if _, handled := doRequest(t, engine, false); !handled {
+			t.Fatalf("success request %d should not be limited", i)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, handled := doRequest(t, engine, true); !handled {
+			t.Fatalf("failed request %d should not be limited", i)
+		}
+	}
+
+	w, handled := doRequest(t, engine, false)
+	if handled {
+		t.Fatal("request over limit should be rejected")
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want 60", got)
+	}
+	if !strings.Contains(w.Body.String(), cMsg.MsgTooManyRequestsFailed.GetMsg()) {
+		t.Errorf("unexpected response %s", w.Body.String())
+	}
+}
+
+func TestMiddlewareLockout(t *testing.T) {
+	l, clock := newTestLimiter(t, Limit(2), Window(time.Minute), Lockout(10*time.Minute), FailuresOnly())
+	engine := newTestEngine(l)
+
+	doRequest(t, engine, true)
+	doRequest(t, engine, true)
+
+	// 锁定期间成功的请求同样被拒绝
+	clock.Advance(2 * time.Minute)
+	w, handled := doRequest(t, engine, false)
+	if handled {
+		t.Fatal("request should be rejected while locked")
+	}
+	if got := w.Header().Get("Retry-After"); got != "480" {
+		t.Errorf("Retry-After = %q, want 480", got)
+	}
+	if !strings.Contains(w.Body.String(), cMsg.MsgRateLimitLockedFailed.GetMsg()) {
+		t.Errorf("unexpected response %s", w.Body.String())
+	}
+
+	clock.Advance(8 * time.Minute)
+	if _, handled = doRequest(t, engine, false); !handled {
+		t.Fatal("request should be allowed after lockout")
+	}
+}
+
+func TestMiddlewareResetOnSuccess(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []Option
+		// 成功一次后还允许的失败次数
+		want int
+	}{
+		{"cancel only", []Option{Limit(3), Window(time.Minute), FailuresOnly()}, 1},
+		{"reset", []Option{Limit(3), Window(time.Minute), FailuresOnly(), ResetOnSuccess()}, 3},
+		// ResetOnSuccess仅在FailuresOnly时生效
+		{"reset without failures only", []Option{Limit(3), Window(time.Minute), ResetOnSuccess()}, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, _ := newTestLimiter(t, tc.opts...)
+			engine := newTestEngine(l)
+
+			doRequest(t, engine, true)
+			doRequest(t, engine, true)
+			if _, handled := doRequest(t, engine, false); !handled {
+				t.Fatal("success request should not be limited")
+			}
+
+			got := 0
+			for ; got < 5; got++ {
+				if _, handled := doRequest(t, engine, true); !handled {
+					break
+				}
+			}
+			if got != tc.want {
+				t.Errorf("failed requests allowed after success = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareDisabled(t *testing.T) {
+	l, _ := newTestLimiter(t, Limit(0), FailuresOnly())
+	engine := newTestEngine(l)
+
+	for i := 0; i < 5; i++ {
+		if _, handled := doRequest(t, engine, true); !handled {
+			t.Fatalf("request %d should not be limited when Limit < 1", i)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/tracer"
+	"eago/common/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Auditor 审计日志，记录谁在什么时候对什么资源做了什么修改
+type Auditor interface {
+	// Middleware 记录所有修改类请求的gin中间件，需放在登录校验之后
+	Middleware() gin.HandlerFunc
+	// Record 显式记录一条审计日志，用于非HTTP请求触发的修改
+	Record(ctx context.Context, l *AuditLog) error
+
+	// PagedList 分页查询审计日志
+	PagedList(ctx context.Context, q orm.Query, page, pageSize int, orderBy ...string) (*orm.Paginator, error)
+	// List 查询审计日志，最多返回limit条
+	List(ctx context.Context, q orm.Query, limit int) ([]*AuditLog, error)
+
+	// ListHandler 分页列出审计日志的gin处理函数
+	ListHandler(c *gin.Context)
+	// ExportHandler 以CSV格式导出审计日志的gin处理函数
+	ExportHandler(c *gin.Context)
+}
+
+type auditor struct {
+	db      *gorm.DB
+	service string
+
+	opts Options
+}
+
+// NewAuditor 生成Auditor，service为审计日志中记录的服务名
+func NewAuditor(db *gorm.DB, service string, opts ...Option) Auditor {
+	return &auditor{
+		db:      db,
+		service: service,
+		opts:    newOptions(opts...),
+	}
+}
+
+// Record 记录一条审计日志，对Before及After中的敏感字段脱敏，未设置Diff时自动计算Diff
+func (a *auditor) Record(ctx context.Context, l *AuditLog) error {
+	if l.Service == "" {
+		l.Service = a.service
+	}
+	if l.TraceId == "" {
+		l.TraceId = tracer.TraceIdFromCtx(ctx)
+	}
+	before, after := toJsonObject(l.Before), toJsonObject(l.After)
+	if before != nil {
+		maskJsonObject(before, a.opts.SensitiveKeys)
+		l.Before = marshalOrNil(before)
+	}
+	if after != nil {
+		maskJsonObject(after, a.opts.SensitiveKeys)
+		l.After = marshalOrNil(after)
+	}
+	if l.Diff == nil && (before != nil || after != nil) {
+		l.Diff = marshalOrNil(diffJsonObject(before, after))
+	}
+	l.CreatedAt = &utils.CustomTime{Time: time.Now()}
+
+	if err := a.db.WithContext(ctx).Create(l).Error; err != nil {
+		a.opts.Logger.ErrorWithFields(logger.Fields{
+			"action":   l.Action,
+			"resource": l.Resource,
+			"actor":    l.Actor,
+			"error":    err,
+		}, "An error occurred while db.Create in auditor.Record.")
+		return err
+	}
+	return nil
+}
+
+// PagedList 分页查询审计日志
+func (a *auditor) PagedList(
+	ctx context.Context, q orm.Query, page, pageSize int, orderBy ...string,
+) (*orm.Paginator, error) {
+	ls := make([]*AuditLog, pageSize)
+	db := q.Where(a.db.WithContext(ctx).Model(&AuditLog{}))
+	return orm.PagingQuery(db, page, pageSize, &ls, orderBy...)
+}
+
+// List 查询审计日志，按时间倒序最多返回limit条
+func (a *auditor) List(ctx context.Context, q orm.Query, limit int) ([]*AuditLog, error) {
+	ls := make([]*AuditLog, 0)
+	res := q.Where(a.db.WithContext(ctx)).Order("id DESC").Limit(limit).Find(&ls)
+	return ls, res.Error
+}
+
+// Marshal 将对象序列化为JSON，用于显式记录时填充Before及After，对象为空或无法序列化时返回nil
+func Marshal(v interface{}) *string {
+	return marshalOrNil(v)
+}
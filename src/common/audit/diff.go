@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// maskedValue 脱敏后的值
+const maskedValue = "******"
+
+// FieldDiff 单个字段修改前后的值
+type FieldDiff struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// toJsonObject 将对象转换为JSON对象，无法转换时返回nil
+func toJsonObject(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+
+	var raw []byte
+	switch val := v.(type) {
+	case []byte:
+		raw = val
+	case string:
+		raw = []byte(val)
+	case *string:
+		if val == nil {
+			return nil
+		}
+		raw = []byte(*val)
+	default:
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil
+		}
+	}
+
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+// diffJsonObject 比较两个JSON对象的第一层字段，返回发生变化的字段
+// before为空时after中的所有字段均视为新增，after为空时before中的所有字段均视为删除
+func diffJsonObject(before, after map[string]interface{}) map[string]*FieldDiff {
+	diff := make(map[string]*FieldDiff)
+	for k, bv := range before {
+		av, ok := after[k]
+		if after != nil && !ok {
+			// 修改后的内容中没有的字段视为未修改，如请求体中只包含部分字段
+			continue
+		}
+		if !reflect.DeepEqual(bv, av) {
+			diff[k] = &FieldDiff{Before: bv, After: av}
+		}
+	}
+	for k, av := range after {
+		if _, ok := before[k]; !ok {
+			diff[k] = &FieldDiff{Before: nil, After: av}
+		}
+	}
+	return diff
+}
+
+// maskJsonObject 递归脱敏JSON对象中的敏感字段
+func maskJsonObject(obj map[string]interface{}, keys []string) {
+	for k, v := range obj {
+		if isSensitiveKey(k, keys) {
+			obj[k] = maskedValue
+			continue
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			maskJsonObject(val, keys)
+		case []interface{}:
+			for _, item := range val {
+				if m, ok := item.(map[string]interface{}); ok {
+					maskJsonObject(m, keys)
+				}
+			}
+		}
+	}
+}
+
+// isSensitiveKey 判断字段名是否是敏感字段，字段名包含敏感字段即视为敏感，如new_password
+func isSensitiveKey(key string, keys []string) bool {
+	key = strings.ToLower(key)
+	for _, k := range keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalOrNil 序列化对象，对象为空时返回nil
+func marshalOrNil(v interface{}) *string {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); (rv.Kind() == reflect.Map || rv.Kind() == reflect.Ptr) && rv.IsNil() {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
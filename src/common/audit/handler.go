@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/global"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/tracer"
+	"encoding/csv"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// ListParamsForm 审计日志查询条件
+type ListParamsForm struct {
+	ActorId    *uint32 `form:"actor_id"`
+	Actor      *string `form:"actor"`
+	Action     *string `form:"action"`
+	Resource   *string `form:"resource"`
+	ResourceId *string `form:"resource_id"`
+	TraceId    *string `form:"trace_id"`
+	Succeeded  *bool   `form:"succeeded"`
+	// StartTime 及 EndTime 格式同global.TimestampFormat
+	StartTime *string `form:"start_time"`
+	EndTime   *string `form:"end_time"`
+}
+
+// GenQuery 生成查询条件，时间格式不正确时返回错误
+func (pf *ListParamsForm) GenQuery() (orm.Query, error) {
+	query := orm.Query{}
+	if pf.ActorId != nil {
+		query["actor_id=?"] = *pf.ActorId
+	}
+	if pf.Actor != nil && *pf.Actor != "" {
+		query["actor=?"] = *pf.Actor
+	}
+	if pf.Action != nil && *pf.Action != "" {
+		query["action LIKE ?"] = fmt.Sprintf("%%%s%%", *pf.Action)
+	}
+	if pf.Resource != nil && *pf.Resource != "" {
+		query["resource=?"] = *pf.Resource
+	}
+	if pf.ResourceId != nil && *pf.ResourceId != "" {
+		query["resource_id=?"] = *pf.ResourceId
+	}
+	if pf.TraceId != nil && *pf.TraceId != "" {
+		query["trace_id=?"] = *pf.TraceId
+	}
+	if pf.Succeeded != nil {
+		query["succeeded=?"] = *pf.Succeeded
+	}
+	if pf.StartTime != nil && *pf.StartTime != "" {
+		t, err := time.ParseInLocation(global.TimestampFormat, *pf.StartTime, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		query["created_at>=?"] = t
+	}
+	if pf.EndTime != nil && *pf.EndTime != "" {
+		t, err := time.ParseInLocation(global.TimestampFormat, *pf.EndTime, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		query["created_at<?"] = t
+	}
+	return query, nil
+}
+
+// ListHandler 分页列出审计日志，需配合api.PagingQueryMiddleware使用
+func (a *auditor) ListHandler(c *gin.Context) {
+	query, ok := a.bindQuery(c)
+	if !ok {
+		return
+	}
+
+	paged, err := a.PagedList(
+		tracer.ExtractTraceCtxFromGin(c),
+		query,
+		c.GetInt(global.GinCtxPageKey),
+		c.GetInt(global.GinCtxPageSizeKey),
+		c.GetStringSlice(global.GinCtxOrderByKey)...,
+	)
+	if err != nil {
+		m := cMsg.MsgAuditDaoErr.SetError(err)
+		a.opts.Logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while auditor.PagedList in auditor.ListHandler.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "audit_logs", paged)
+}
+
+// ExportHandler 以CSV格式导出符合条件的审计日志，最多导出ExportLimit条
+func (a *auditor) ExportHandler(c *gin.Context) {
+	query, ok := a.bindQuery(c)
+	if !ok {
+		return
+	}
+
+	ls, err := a.List(tracer.ExtractTraceCtxFromGin(c), query, a.opts.ExportLimit)
+	if err != nil {
+		m := cMsg.MsgAuditDaoErr.SetError(err)
+		a.opts.Logger.ErrorWithFields(m.ToLoggerFields(), "An error occurred while auditor.List in auditor.ExportHandler.")
+		m.Write2GinCtx(c)
+		return
+	}
+
+	filename := fmt.Sprintf("audit_logs_%s_%s.csv", a.service, time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"id", "service", "actor_id", "actor", "action", "resource", "resource_id",
+		"before", "after", "diff", "ip", "trace_id", "succeeded", "created_at",
+	})
+	for _, l := range ls {
+		_ = w.Write([]string{
+			strconv.FormatUint(l.Id, 10),
+			l.Service,
+			strconv.FormatUint(uint64(l.ActorId), 10),
+			l.Actor,
+			l.Action,
+			l.Resource,
+			l.ResourceId,
+			derefString(l.Before),
+			derefString(l.After),
+			derefString(l.Diff),
+			l.Ip,
+			l.TraceId,
+			strconv.FormatBool(l.Succeeded),
+			l.CreatedAt.String(),
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		a.opts.Logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while csv.Flush in auditor.ExportHandler.")
+	}
+}
+
+// bindQuery 读取查询条件，不合法时写入错误信息
+func (a *auditor) bindQuery(c *gin.Context) (orm.Query, bool) {
+	pFrm := new(ListParamsForm)
+	if err := c.ShouldBindQuery(pFrm); err != nil {
+		m := cMsg.MsgValidateFailed.SetError(err)
+		a.opts.Logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return nil, false
+	}
+
+	query, err := pFrm.GenQuery()
+	if err != nil {
+		m := cMsg.MsgValidateFailed.SetError(err)
+		a.opts.Logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return nil, false
+	}
+	return query, true
+}
+
+// derefString 获得*string的值，为空时返回空字符串
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bytes"
+	"eago/common/api"
+	perm "eago/common/api/permission"
+	"eago/common/global"
+	"eago/common/tracer"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	ginCtxBeforeKey = "__audit_before"
+	ginCtxAfterKey  = "__audit_after"
+	ginCtxSkipKey   = "__audit_skip"
+)
+
+// SetBefore 设置修改前的内容，需在处理函数修改资源前调用
+func SetBefore(c *gin.Context, v interface{}) {
+	c.Set(ginCtxBeforeKey, v)
+}
+
+// SetAfter 设置修改后的内容，未设置时使用请求体
+func SetAfter(c *gin.Context, v interface{}) {
+	c.Set(ginCtxAfterKey, v)
+}
+
+// Skip 不记录本次请求，用于请求体中只有凭据等无需审计的请求
+func Skip(c *gin.Context) {
+	c.Set(ginCtxSkipKey, true)
+}
+
+// Middleware 记录所有修改类请求，读取请求体作为默认的修改后内容，根据返回的code判断是否成功
+func (a *auditor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		var reqBody []byte
+		if c.Request.Body != nil && c.Request.ContentLength <= a.opts.MaxBodySize {
+			// 分块传输时ContentLength为-1，最多读取MaxBodySize+1字节，超出时不记录请求体
+			body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, a.opts.MaxBodySize+1))
+			if err == nil && int64(len(body)) <= a.opts.MaxBodySize {
+				reqBody = body
+			}
+			// 还原请求体供后续处理函数使用，未读取的部分仍从原请求体读取
+			c.Request.Body = readCloser{
+				Reader: io.MultiReader(bytes.NewReader(body), c.Request.Body),
+				Closer: c.Request.Body,
+			}
+		}
+
+		w := &auditWriter{ResponseWriter: c.Writer, buffer: &bytes.Buffer{}, limit: a.opts.MaxBodySize}
+		c.Writer = w
+
+		c.Next()
+
+		if c.GetBool(ginCtxSkipKey) {
+			return
+		}
+
+		resource, resourceId := splitRoute(c)
+		l := &AuditLog{
+			Action:     c.Request.Method + " " + c.FullPath(),
+			Resource:   resource,
+			ResourceId: resourceId,
+			Ip:         api.ClientIp(c),
+			TraceId:    c.GetString(global.GinCtxTracerIdKey),
+			Succeeded:  w.Status() < http.StatusBadRequest && w.code() == 0,
+		}
+		if tc, ok := perm.GetTokenContent(c); ok {
+			l.ActorId = tc.UserId
+			l.Actor = tc.Username
+		}
+		if v, ok := c.Get(ginCtxBeforeKey); ok {
+			l.Before = marshalOrNil(v)
+		}
+		if v, ok := c.Get(ginCtxAfterKey); ok {
+			l.After = marshalOrNil(v)
+		} else if toJsonObject(reqBody) != nil {
+			l.After = marshalOrNil(json.RawMessage(reqBody))
+		}
+
+		// 写入失败时已记录日志，不影响请求结果
+		_ = a.Record(tracer.ExtractTraceCtxFromGin(c), l)
+	}
+}
+
+// readCloser 组合Reader及原请求体的Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// isMutatingMethod 是否是修改类请求
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// splitRoute 将路由拆分为资源及资源ID，忽略第一段服务名
+// 如"/auth/roles/:role_id/users/:user_id"拆分为"roles/users"及"3/15"
+func splitRoute(c *gin.Context) (string, string) {
+	segs := strings.Split(strings.Trim(c.FullPath(), "/"), "/")
+	if len(segs) > 0 {
+		segs = segs[1:]
+	}
+
+	resources := make([]string, 0, len(segs))
+	ids := make([]string, 0)
+	for _, s := range segs {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			ids = append(ids, c.Param(s[1:]))
+			continue
+		}
+		resources = append(resources, s)
+	}
+	return strings.Join(resources, "/"), strings.Join(ids, "/")
+}
+
+// auditWriter 记录返回内容的Writer，超过limit的部分不记录
+type auditWriter struct {
+	gin.ResponseWriter
+	buffer *bytes.Buffer
+	limit  int64
+}
+
+// Write
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if int64(w.buffer.Len()+len(b)) <= w.limit {
+		w.buffer.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// code 获得返回内容中的code，无法解析时视为0
+func (w *auditWriter) code() float64 {
+	resp := make(map[string]interface{})
+	_ = json.Unmarshal(w.buffer.Bytes(), &resp)
+	code, _ := resp["code"].(float64)
+	return code
+}
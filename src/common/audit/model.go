@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"eago/common/utils"
+)
+
+// AuditLog 审计日志-数据库模型
+type AuditLog struct {
+	Id uint64 `json:"id"`
+
+	Service string `json:"service"`
+	ActorId uint32 `json:"actor_id"`
+	Actor   string `json:"actor"`
+	// Action 操作，格式为"<METHOD> <路由>"，非HTTP请求时由调用方指定
+	Action string `json:"action"`
+	// Resource 资源，由路由中的非参数部分组成，如"roles/users"
+	Resource string `json:"resource"`
+	// ResourceId 资源ID，由路由中的参数组成，如"3/15"
+	ResourceId string `json:"resource_id"`
+
+	// Before 修改前的内容，After 修改后的内容，Diff 发生变化的字段，均为JSON
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+	Diff   *string `json:"diff"`
+
+	Ip        string            `json:"ip"`
+	TraceId   string            `json:"trace_id"`
+	Succeeded bool              `json:"succeeded"`
+	CreatedAt *utils.CustomTime `json:"created_at"`
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"eago/common/logger"
+)
+
+const (
+	defaultMaxBodySize = 64 * 1024
+	defaultExportLimit = 10000
+)
+
+var defaultSensitiveKeys = []string{"password", "secret", "token"}
+
+type Option func(o *Options)
+
+// Option struct
+type Options struct {
+	// MaxBodySize 记录的请求体的最大长度，超过时不记录
+	MaxBodySize int64
+	// ExportLimit 单次导出的最大条数
+	ExportLimit int
+	// SensitiveKeys 记录前需要脱敏的字段，字段名包含其中任意一个即脱敏
+	SensitiveKeys []string
+
+	Logger *logger.Logger
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		MaxBodySize:   defaultMaxBodySize,
+		ExportLimit:   defaultExportLimit,
+		SensitiveKeys: defaultSensitiveKeys,
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	if opt.Logger == nil {
+		opt.Logger = logger.NewDefaultLogger()
+	}
+
+	return opt
+}
+
+// MaxBodySize 设置MaxBodySize
+func MaxBodySize(in int64) Option {
+	return func(o *Options) {
+		o.MaxBodySize = in
+	}
+}
+
+// ExportLimit 设置ExportLimit
+func ExportLimit(in int) Option {
+	return func(o *Options) {
+		o.ExportLimit = in
+	}
+}
+
+// SensitiveKeys 设置SensitiveKeys，会覆盖默认值
+func SensitiveKeys(in ...string) Option {
+	return func(o *Options) {
+		o.SensitiveKeys = in
+	}
+}
+
+// Logger 设置Logger
+func Logger(in *logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = in
+	}
+}
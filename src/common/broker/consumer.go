@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 	"eago/common/logger"
+	"eago/common/metrics"
 	"encoding/json"
 	"fmt"
 	"github.com/micro/go-micro/v2/broker"
@@ -99,12 +100,15 @@ func (c *consumer) Subscribe(model, event string, h SubHandleFunc) {
 				"message_body":  e.Message().Body,
 				"error":         err,
 			}, "An error occurred while run json.Unmarshal event.Message().Body, This message will be discarded.")
+			metrics.ObserveBrokerConsume(subTopicName, err)
 			return nil
 		}
 
 		msg.Body = bd
 
-		if err := h(c.ctx, msg); err != nil {
+		err := h(c.ctx, msg)
+		metrics.ObserveBrokerConsume(subTopicName, err)
+		if err != nil {
 			c.logger.ErrorWithFields(logger.Fields{
 				"topic":         subTopicName,
 				"consumer_name": c.consumerName,
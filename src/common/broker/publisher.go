@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 	"eago/common/logger"
+	"eago/common/metrics"
 	"github.com/micro/go-micro/v2/broker"
 	uuid "github.com/satori/go.uuid"
 )
@@ -59,6 +60,7 @@ func (p *publisher) Publish(
 	// 发送消息
 	p.logger.Debug("Prepare call publisher.broker.Publish.")
 	err := p.broker.Publish(tgtTopicName, msg.ToBrokerMessage(p.opts.TimestampFormat), broker.PublishContext(ctx))
+	metrics.ObserveBrokerPublish(tgtTopicName, err)
 	if err != nil {
 		p.logger.InfoWithFields(logger.Fields{
 			"message_uuid":  msg.Uuid,
@@ -3,6 +3,7 @@ package code_msg
 import (
 	"eago/common/logger"
 	"eago/common/utils"
+	"encoding/json"
 	"fmt"
 	"github.com/beego/beego/v2/core/validation"
 	"github.com/gin-gonic/gin"
@@ -13,6 +14,12 @@ import (
 	"strings"
 )
 
+// microErrDetail 携带字段级别错误的MicroErr详情
+type microErrDetail struct {
+	Message string            `json:"message"`
+	Errors  map[string]string `json:"errors"`
+}
+
 type CodeMsg struct {
 	code int
 	msg  string
@@ -26,8 +33,14 @@ func NewCodeMsg(code int, msg string) *CodeMsg {
 	}
 }
 
-// ToMicroErr 将CodeMsg类型转为ToMicroErr
+// ToMicroErr 将CodeMsg类型转为ToMicroErr，字段级别的错误一并传递
 func (cm *CodeMsg) ToMicroErr() error {
+	if fields, ok := cm.err.(map[string]string); ok {
+		detail, err := json.Marshal(microErrDetail{Message: cm.msg, Errors: fields})
+		if err == nil {
+			return errors.New(fieldsMicroErrId, string(detail), int32(cm.code))
+		}
+	}
 	return errors.New(defaultMicroErrId, cm.msg, int32(cm.code))
 }
 
@@ -0,0 +1,66 @@
+package code_msg
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// writeAndDecode 将CodeMsg写入响应并解析响应中的errors字段
+func writeAndDecode(t *testing.T, cm *CodeMsg) interface{} {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	cm.Write2GinCtx(c)
+
+	rsp := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	return rsp["errors"]
+}
+
+func TestWrite2GinCtxFieldErrors(t *testing.T) {
+	fields := map[string]string{"a": "x", "b.c": "y"}
+	cm := NewCodeMsg(400, "failed").SetError(fields)
+
+	raw := writeAndDecode(t, cm)
+	errs, ok := raw.(map[string]interface{})
+	if !ok {
+		t.Fatalf("errors should be a JSON object, got %T", raw)
+	}
+	if len(errs) != len(fields) || errs["a"] != "x" || errs["b.c"] != "y" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestMicroErrFieldErrorsRoundTrip(t *testing.T) {
+	fields := map[string]string{"a": "x", "b.c": "y"}
+	cm := NewCodeMsg(130002, "failed").SetError(fields)
+
+	got, ok := TransMicroErr2CodeMsg(cm.ToMicroErr())
+	if !ok {
+		t.Fatal("micro error should be translated")
+	}
+	if got.GetCode() != cm.GetCode() || got.GetMsg() != cm.GetMsg() {
+		t.Errorf("unexpected code/msg: %d %q", got.GetCode(), got.GetMsg())
+	}
+	raw := writeAndDecode(t, got)
+	if errs, ok := raw.(map[string]interface{}); !ok || len(errs) != len(fields) {
+		t.Errorf("errors should survive as a JSON object, got %v", raw)
+	}
+}
+
+func TestMicroErrWithoutFields(t *testing.T) {
+	cm := NewCodeMsg(130000, "failed").SetError(nil)
+
+	got, ok := TransMicroErr2CodeMsg(cm.ToMicroErr())
+	if !ok {
+		t.Fatal("micro error should be translated")
+	}
+	if !reflect.DeepEqual(got, NewCodeMsg(130000, "failed")) {
+		t.Errorf("unexpected code msg: %+v", got)
+	}
+}
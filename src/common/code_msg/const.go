@@ -4,4 +4,6 @@ const (
 	defaultSeparator = ", "
 
 	defaultMicroErrId = "eago.code_message"
+	// fieldsMicroErrId 携带字段级别错误的MicroErr，Detail为JSON格式的消息及错误
+	fieldsMicroErrId = "eago.code_message.fields"
 )
@@ -11,7 +11,15 @@ var (
 	MsgInvalidTokenFailed = NewCodeMsg(http.StatusUnauthorized, "无效的Token")
 	MsgUserNotRoleFailed  = NewCodeMsg(http.StatusUnauthorized, "无权限，需要当前用户属于以下角色中的任意一个:")
 	MsgNotFoundFailed     = NewCodeMsg(http.StatusNotFound, "操作失败，找不到指定对象")
+	MsgUserNotPermFailed  = NewCodeMsg(http.StatusUnauthorized, "无权限，需要当前用户拥有以下权限:")
+	MsgTokenScopeFailed   = NewCodeMsg(http.StatusForbidden, "无权限，访问令牌不允许访问以下接口:")
+	MsgMfaRequiredFailed  = NewCodeMsg(http.StatusForbidden, "无权限，该操作需要通过两步验证登录")
+
+	MsgTooManyRequestsFailed = NewCodeMsg(http.StatusTooManyRequests, "请求过于频繁，请在以下秒数后重试:")
+	MsgRateLimitLockedFailed = NewCodeMsg(http.StatusTooManyRequests, "失败次数过多，已被临时锁定，请在以下秒数后重试:")
+	MsgRequestTooLargeFailed = NewCodeMsg(http.StatusRequestEntityTooLarge, "请求内容过大，允许的最大字节数:")
 
 	MsgCheckRoleErr = NewCodeMsg(http.StatusInternalServerError, "检查用户角色时出错，请联系管理员")
 	MsgUndefinedErr = NewCodeMsg(http.StatusInternalServerError, "遇到未知错误失败，请先尝试重试，若无效请联系管理员")
+	MsgAuditDaoErr  = NewCodeMsg(http.StatusInternalServerError, "查询审计日志时出错，请联系管理员")
 )
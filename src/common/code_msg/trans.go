@@ -1,6 +1,7 @@
 package code_msg
 
 import (
+	"encoding/json"
 	"github.com/micro/go-micro/v2/errors"
 	"google.golang.org/grpc/status"
 )
@@ -8,11 +9,18 @@ import (
 // TransMicroErr2CodeMsg 尝试将MicroErr转为CodeMsg类型
 func TransMicroErr2CodeMsg(microErr error) (*CodeMsg, bool) {
 	rErr := errors.FromError(microErr)
-	if rErr.Id != defaultMicroErrId {
-		return nil, false
+	switch rErr.Id {
+	case defaultMicroErrId:
+		return NewCodeMsg(int(rErr.Code), rErr.Detail), true
+	case fieldsMicroErrId:
+		detail := microErrDetail{}
+		if err := json.Unmarshal([]byte(rErr.Detail), &detail); err != nil {
+			return nil, false
+		}
+		return NewCodeMsg(int(rErr.Code), detail.Message).SetError(detail.Errors), true
 	}
 
-	return NewCodeMsg(int(rErr.Code), rErr.Detail), true
+	return nil, false
 }
 
 // TransRpcErr2CodeMsg 尝试将RpcError转为CodeMsg类型
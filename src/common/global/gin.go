@@ -7,6 +7,8 @@ const (
 	GinParamOrderByKey  = "order_by"
 	GinParamPageKey     = "page"
 	GinParamPageSizeKey = "page_size"
+	GinParamCursorKey   = "cursor"
+	GinParamSkipTotal   = "skip_total"
 
 	GinCtxTracerIdKey = "__gin_tracer_id"
 	GinCtxQueryKey    = "__gin_query"
@@ -0,0 +1,345 @@
+package json_schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+
+	// RootField 根节点字段名
+	RootField = "(root)"
+)
+
+// Schema JSON Schema的子集，支持常用的类型、必填、默认值及取值范围校验
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// FieldErrors 字段级别的校验错误，key为字段路径，value为错误信息
+type FieldErrors map[string]string
+
+// Error 实现error接口
+func (fe FieldErrors) Error() string {
+	keys := make([]string, 0, len(fe))
+	for k := range fe {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", k, fe[k]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Compile 解析并检查JSON Schema，空字符串或空对象视为不做任何限制
+func Compile(raw string) (*Schema, error) {
+	s := &Schema{}
+	if strings.TrimSpace(raw) == "" {
+		return s, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), s); err != nil {
+		return nil, err
+	}
+
+	if err := s.compile(RootField); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Schema) compile(path string) error {
+	switch s.Type {
+	case "", TypeObject, TypeArray, TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeNull:
+	default:
+		return fmt.Errorf("%s: unsupported type %q", path, s.Type)
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %v", path, err)
+		}
+		s.pattern = re
+	}
+
+	for _, r := range s.Required {
+		if _, ok := s.Properties[r]; !ok && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			return fmt.Errorf("%s: required property %q is not defined", path, r)
+		}
+	}
+
+	for name, p := range s.Properties {
+		if p == nil {
+			return fmt.Errorf("%s: property %q has no schema", path, name)
+		}
+		if err := p.compile(joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		if err := s.Items.compile(path + "[]"); err != nil {
+			return err
+		}
+	}
+
+	// 默认值本身也必须满足约束
+	if s.Default != nil {
+		if errs := s.validate(path, s.Default); len(errs) > 0 {
+			return fmt.Errorf("%s: invalid default value: %v", path, errs)
+		}
+	}
+
+	return nil
+}
+
+// schemaKeywords 描述结构的JSON Schema关键字，形参中不含任何关键字时视为旧版形参
+var schemaKeywords = []string{"type", "properties", "required", "additionalProperties", "items", "enum"}
+
+// CompileFormalParams 解析任务形参，兼容早于JSON Schema的旧版形参
+// 旧版形参为非对象的JSON或不含任何JSON Schema关键字的对象，返回不做限制的Schema且legacy为true
+// 形参包含JSON Schema关键字但不合法时返回错误，调用方应拒绝调用而不是跳过校验
+func CompileFormalParams(raw string) (s *Schema, legacy bool, err error) {
+	if strings.TrimSpace(raw) == "" {
+		return &Schema{}, false, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err = json.Unmarshal([]byte(raw), &obj); err != nil {
+		// 不是合法JSON的形参无法判断是否为旧版形参
+		if !json.Valid([]byte(raw)) {
+			return nil, false, err
+		}
+		return &Schema{}, true, nil
+	}
+
+	legacy = len(obj) > 0
+	for _, k := range schemaKeywords {
+		if _, ok := obj[k]; ok {
+			legacy = false
+			break
+		}
+	}
+	if legacy {
+		return &Schema{}, true, nil
+	}
+
+	s, err = Compile(raw)
+	return s, false, err
+}
+
+// Validate 校验参数并填充默认值，返回填充默认值后的参数
+func (s *Schema) Validate(arguments []byte) ([]byte, error) {
+	var v interface{}
+	if len(strings.TrimSpace(string(arguments))) == 0 {
+		v = map[string]interface{}{}
+	} else if err := json.Unmarshal(arguments, &v); err != nil {
+		return nil, FieldErrors{RootField: "不是合法的JSON"}
+	}
+
+	v = s.applyDefaults(v)
+	if errs := s.validate(RootField, v); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return json.Marshal(v)
+}
+
+// applyDefaults 递归为缺失的字段填充默认值
+func (s *Schema) applyDefaults(v interface{}) interface{} {
+	if v == nil {
+		return s.Default
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for name, p := range s.Properties {
+			if sub, ok := val[name]; ok {
+				val[name] = p.applyDefaults(sub)
+			} else if p.Default != nil {
+				val[name] = p.applyDefaults(deepCopy(p.Default))
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i := range val {
+				val[i] = s.Items.applyDefaults(val[i])
+			}
+		}
+	}
+
+	return v
+}
+
+func (s *Schema) validate(path string, v interface{}) FieldErrors {
+	errs := FieldErrors{}
+
+	if s.Type != "" && !isType(s.Type, v) {
+		errs[path] = fmt.Sprintf("类型应为%s", s.Type)
+		return errs
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		errs[path] = "取值不在可选范围内"
+		return errs
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, r := range s.Required {
+			if _, ok := val[r]; !ok {
+				errs[joinPath(path, r)] = "必填字段"
+			}
+		}
+		for name, sub := range val {
+			p, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs[joinPath(path, name)] = "不允许的字段"
+				}
+				continue
+			}
+			for k, e := range p.validate(joinPath(path, name), sub) {
+				errs[k] = e
+			}
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			errs[path] = fmt.Sprintf("元素个数不能少于%d", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			errs[path] = fmt.Sprintf("元素个数不能多于%d", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, sub := range val {
+				for k, e := range s.Items.validate(fmt.Sprintf("%s[%d]", path, i), sub) {
+					errs[k] = e
+				}
+			}
+		}
+
+	case string:
+		l := len([]rune(val))
+		if s.MinLength != nil && l < *s.MinLength {
+			errs[path] = fmt.Sprintf("长度不能小于%d", *s.MinLength)
+		}
+		if s.MaxLength != nil && l > *s.MaxLength {
+			errs[path] = fmt.Sprintf("长度不能大于%d", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			errs[path] = fmt.Sprintf("格式不匹配%s", s.Pattern)
+		}
+
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			errs[path] = fmt.Sprintf("不能小于%v", *s.Minimum)
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			errs[path] = fmt.Sprintf("不能大于%v", *s.Maximum)
+		}
+	}
+
+	return errs
+}
+
+func isType(t string, v interface{}) bool {
+	switch t {
+	case TypeObject:
+		_, ok := v.(map[string]interface{})
+		return ok
+	case TypeArray:
+		_, ok := v.([]interface{})
+		return ok
+	case TypeString:
+		_, ok := v.(string)
+		return ok
+	case TypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case TypeInteger:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case TypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case TypeNull:
+		return v == nil
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	vb, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	for _, e := range enum {
+		if eb, err := json.Marshal(e); err == nil && string(eb) == string(vb) {
+			return true
+		}
+	}
+	return false
+}
+
+func deepCopy(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var c interface{}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return v
+	}
+	return c
+}
+
+func joinPath(path, name string) string {
+	if path == RootField {
+		return name
+	}
+	return path + "." + name
+}
+
+// IsFieldErrors 判断err是否为字段级别的校验错误
+func IsFieldErrors(err error) (FieldErrors, bool) {
+	var fe FieldErrors
+	if errors.As(err, &fe) {
+		return fe, true
+	}
+	return nil, false
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	namespace = "eago"
+
+	// Path 指标暴露路径
+	Path = "/metrics"
+
+	ResultSuccess = "success"
+	ResultFailed  = "failed"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "http", Name: "requests_total",
+		Help: "Total number of HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+		Help:    "HTTP request latency by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "rpc", Name: "requests_total",
+		Help: "Total number of RPC requests by side, service, endpoint and result.",
+	}, []string{"side", "service", "endpoint", "result"})
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "rpc", Name: "request_duration_seconds",
+		Help:    "RPC request latency by side, service and endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"side", "service", "endpoint"})
+
+	brokerMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "broker", Name: "messages_total",
+		Help: "Total number of broker messages published or consumed by topic and result.",
+	}, []string{"direction", "topic", "result"})
+
+	workerRunningTasks = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace, Subsystem: "worker", Name: "running_tasks",
+		Help: "Number of tasks running on the worker by codename.",
+	}, []string{"codename"})
+	taskResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "task", Name: "results_total",
+		Help: "Total number of finished tasks by codename and status.",
+	}, []string{"codename", "status"})
+
+	schedulerFires = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "scheduler", Name: "fires_total",
+		Help: "Total number of schedules fired successfully by codename.",
+	}, []string{"codename"})
+	schedulerMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "scheduler", Name: "misses_total",
+		Help: "Total number of schedules failed to call the task by codename.",
+	}, []string{"codename"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequests, httpDuration,
+		rpcRequests, rpcDuration,
+		brokerMessages,
+		workerRunningTasks, taskResults,
+		schedulerFires, schedulerMisses,
+	)
+}
+
+// Handler 指标HTTP Handler
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve 单独监听地址暴露指标，用于没有HTTP服务的进程，address为空时不启动
+func Serve(ctx context.Context, address string) error {
+	if address == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+	srv := &http.Server{Addr: address, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// ObserveHttpRequest 记录HTTP请求
+func ObserveHttpRequest(method, route string, status int, elapsed time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveRpcRequest 记录RPC请求，side为server或client
+func ObserveRpcRequest(side, service, endpoint string, err error, elapsed time.Duration) {
+	rpcRequests.WithLabelValues(side, service, endpoint, resultOf(err)).Inc()
+	rpcDuration.WithLabelValues(side, service, endpoint).Observe(elapsed.Seconds())
+}
+
+// ObserveBrokerPublish 记录消息发布结果
+func ObserveBrokerPublish(topic string, err error) {
+	brokerMessages.WithLabelValues("publish", topic, resultOf(err)).Inc()
+}
+
+// ObserveBrokerConsume 记录消息消费结果
+func ObserveBrokerConsume(topic string, err error) {
+	brokerMessages.WithLabelValues("consume", topic, resultOf(err)).Inc()
+}
+
+// WorkerTaskStarted Worker开始运行任务
+func WorkerTaskStarted(codename string) {
+	workerRunningTasks.WithLabelValues(codename).Inc()
+}
+
+// WorkerTaskFinished Worker任务结束
+func WorkerTaskFinished(codename string, status int) {
+	workerRunningTasks.WithLabelValues(codename).Dec()
+	ObserveTaskResult(codename, status)
+}
+
+// ObserveTaskResult 记录任务结束状态
+func ObserveTaskResult(codename string, status int) {
+	taskResults.WithLabelValues(codename, strconv.Itoa(status)).Inc()
+}
+
+// ObserveSchedulerFire 记录计划任务触发结果
+func ObserveSchedulerFire(codename string, err error) {
+	if err != nil {
+		schedulerMisses.WithLabelValues(codename).Inc()
+		return
+	}
+	schedulerFires.WithLabelValues(codename).Inc()
+}
+
+// RegisterSqlDbStats 注册数据库连接池指标
+func RegisterSqlDbStats(name string, db *sql.DB) {
+	labels := prometheus.Labels{"db": name}
+	gauge := func(metric, help string, fn func(s sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "db", Name: metric, Help: help, ConstLabels: labels,
+		}, func() float64 { return fn(db.Stats()) })
+	}
+
+	register(
+		gauge("open_connections", "Number of established connections.",
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		gauge("in_use_connections", "Number of connections currently in use.",
+			func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		gauge("idle_connections", "Number of idle connections.",
+			func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		gauge("wait_count", "Total number of connections waited for.",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		gauge("wait_duration_seconds", "Total time blocked waiting for a new connection.",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+	)
+}
+
+// RegisterRedisPoolStats 注册Redis连接池指标
+func RegisterRedisPoolStats(name string, stats func() *redis.PoolStats) {
+	labels := prometheus.Labels{"redis": name}
+	gauge := func(metric, help string, fn func(s *redis.PoolStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "redis", Name: metric, Help: help, ConstLabels: labels,
+		}, func() float64 { return fn(stats()) })
+	}
+
+	register(
+		gauge("total_connections", "Number of total connections in the pool.",
+			func(s *redis.PoolStats) float64 { return float64(s.TotalConns) }),
+		gauge("idle_connections", "Number of idle connections in the pool.",
+			func(s *redis.PoolStats) float64 { return float64(s.IdleConns) }),
+		gauge("pool_hits", "Number of times free connection was found in the pool.",
+			func(s *redis.PoolStats) float64 { return float64(s.Hits) }),
+		gauge("pool_misses", "Number of times free connection was NOT found in the pool.",
+			func(s *redis.PoolStats) float64 { return float64(s.Misses) }),
+		gauge("pool_timeouts", "Number of times a wait timeout occurred.",
+			func(s *redis.PoolStats) float64 { return float64(s.Timeouts) }),
+	)
+}
+
+// register 注册指标，同名指标已注册时忽略
+func register(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		_ = prometheus.Register(c)
+	}
+}
+
+func resultOf(err error) string {
+	if err != nil {
+		return ResultFailed
+	}
+	return ResultSuccess
+}
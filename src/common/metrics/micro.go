@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"github.com/micro/go-micro/v2/client"
+	"github.com/micro/go-micro/v2/server"
+	"time"
+)
+
+const (
+	SideServer = "server"
+	SideClient = "client"
+)
+
+// NewHandlerWrapper 记录RPC服务端请求的HandlerWrapper
+func NewHandlerWrapper() server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			start := time.Now()
+			err := fn(ctx, req, rsp)
+			ObserveRpcRequest(SideServer, req.Service(), req.Endpoint(), err, time.Since(start))
+			return err
+		}
+	}
+}
+
+// NewClientWrapper 记录RPC客户端请求的ClientWrapper
+func NewClientWrapper() client.Wrapper {
+	return func(c client.Client) client.Client {
+		return &clientWrapper{Client: c}
+	}
+}
+
+type clientWrapper struct {
+	client.Client
+}
+
+func (cw *clientWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	start := time.Now()
+	err := cw.Client.Call(ctx, req, rsp, opts...)
+	ObserveRpcRequest(SideClient, req.Service(), req.Endpoint(), err, time.Since(start))
+	return err
+}
+
+func (cw *clientWrapper) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	start := time.Now()
+	stream, err := cw.Client.Stream(ctx, req, opts...)
+	// 流式请求只记录建立连接的耗时
+	ObserveRpcRequest(SideClient, req.Service(), req.Endpoint(), err, time.Since(start))
+	return stream, err
+}
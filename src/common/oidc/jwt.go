@@ -0,0 +1,198 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrUnsupportedAlg 不支持的签名算法，不接受none及HMAC算法
+var ErrUnsupportedAlg = errors.New("unsupported jws alg")
+
+// jwsHeader JWS头
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// jws 解析后的JWS
+type jws struct {
+	header       *jwsHeader
+	payload      []byte
+	signingInput []byte
+	signature    []byte
+}
+
+// jwk JSON Web Key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA公钥
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC公钥
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJws 解析Compact格式的JWS
+func parseJws(raw string) (*jws, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed jws")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed jws header: %w", err)
+	}
+	header := new(jwsHeader)
+	if err = json.Unmarshal(headerBytes, header); err != nil {
+		return nil, fmt.Errorf("malformed jws header: %w", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed jws payload: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed jws signature: %w", err)
+	}
+
+	return &jws{
+		header:       header,
+		payload:      payload,
+		signingInput: []byte(parts[0] + "." + parts[1]),
+		signature:    sig,
+	}, nil
+}
+
+// verify 使用公钥校验签名
+func (j *jws) verify(key crypto.PublicKey) error {
+	var hash crypto.Hash
+	switch j.header.Alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlg, j.header.Alg)
+	}
+
+	h := hash.New()
+	h.Write(j.signingInput)
+	digest := h.Sum(nil)
+
+	switch j.header.Alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("jwk is not a rsa public key")
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, j.signature)
+
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("jwk is not a rsa public key")
+		}
+		return rsa.VerifyPSS(pub, hash, digest, j.signature, nil)
+
+	default:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("jwk is not an ecdsa public key")
+		}
+		// ECDSA签名为定长的r||s
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(j.signature) != 2*size {
+			return errors.New("invalid ecdsa signature length")
+		}
+		r := new(big.Int).SetBytes(j.signature[:size])
+		s := new(big.Int).SetBytes(j.signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("invalid ecdsa signature")
+		}
+		return nil
+	}
+}
+
+// publicKey 将JWK转换为公钥
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported jwk curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported jwk kty: %s", k.Kty)
+}
+
+// matchAlg JWK是否可用于指定签名算法
+func (k *jwk) matchAlg(alg string) bool {
+	if k.Use != "" && k.Use != "sig" || len(alg) < 2 {
+		return false
+	}
+	if k.Alg != "" {
+		return k.Alg == alg
+	}
+	switch alg[:2] {
+	case "RS", "PS":
+		return k.Kty == "RSA"
+	case "ES":
+		return k.Kty == "EC"
+	}
+	return false
+}
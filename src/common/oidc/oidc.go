@@ -0,0 +1,383 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"eago/common/logger"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// codeChallengeMethodS256 PKCE使用的Challenge计算方式
+	codeChallengeMethodS256 = "S256"
+)
+
+// ErrInvalidIdToken ID Token校验未通过
+var ErrInvalidIdToken = errors.New("invalid id token")
+
+// Oidc OpenID Connect授权码模式客户端，使用PKCE
+type Oidc interface {
+	// AuthCodeURL 生成跳转至身份提供方的授权地址
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange 使用授权码换取令牌
+	Exchange(ctx context.Context, code, codeVerifier string) (*Token, error)
+	// VerifyIdToken 校验ID Token的签名、签发方、受众、有效期及nonce，返回其中的声明
+	VerifyIdToken(ctx context.Context, rawIdToken, nonce string) (Claims, error)
+}
+
+// oidc struct
+type oidc struct {
+	issuer       string
+	clientId     string
+	clientSecret string
+	redirectUrl  string
+	scopes       []string
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      []*jwk
+
+	logger *logger.Logger
+
+	opts Options
+}
+
+func NewOidc(issuer, clientId, clientSecret, redirectUrl string, scopes []string, options ...Option) Oidc {
+	opts := newOptions(options...)
+
+	return &oidc{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		redirectUrl:  redirectUrl,
+		scopes:       scopes,
+
+		logger: opts.Logger,
+
+		opts: opts,
+	}
+}
+
+// AuthCodeURL 生成跳转至身份提供方的授权地址
+func (o *oidc) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	d, err := o.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := o.scopes
+	if !containsString(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", o.clientId)
+	v.Set("redirect_uri", o.redirectUrl)
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", CodeChallengeS256(codeVerifier))
+	v.Set("code_challenge_method", codeChallengeMethodS256)
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange 使用授权码换取令牌
+func (o *oidc) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	d, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", o.redirectUrl)
+	v.Set("client_id", o.clientId)
+	v.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// 公开客户端不设置密钥，仅依赖PKCE
+	if o.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.clientId), url.QueryEscape(o.clientSecret))
+	}
+
+	body, status, err := o.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		errBody := struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}{}
+		_ = json.Unmarshal(body, &errBody)
+		err = fmt.Errorf("token endpoint returned %d: %s %s", status, errBody.Error, errBody.ErrorDescription)
+		o.logger.ErrorWithFields(logger.Fields{
+			"status_code": status,
+			"error":       err,
+		}, "Error in oidc.Exchange.")
+		return nil, err
+	}
+
+	tk := new(Token)
+	if err = json.Unmarshal(body, tk); err != nil {
+		return nil, err
+	}
+	if tk.IdToken == "" {
+		return nil, errors.New("token endpoint returned no id_token")
+	}
+	return tk, nil
+}
+
+// VerifyIdToken 校验ID Token的签名、签发方、受众、有效期及nonce，返回其中的声明
+func (o *oidc) VerifyIdToken(ctx context.Context, rawIdToken, nonce string) (Claims, error) {
+	j, err := parseJws(rawIdToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIdToken, err)
+	}
+
+	key, err := o.getKey(ctx, j.header.Kid, j.header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	if err = j.verify(key); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIdToken, err)
+	}
+
+	claims := Claims{}
+	if err = json.Unmarshal(j.payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIdToken, err)
+	}
+	if err = o.validateClaims(claims, nonce); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIdToken, err)
+	}
+	return claims, nil
+}
+
+// validateClaims 校验ID Token中的标准声明
+func (o *oidc) validateClaims(claims Claims, nonce string) error {
+	if iss := claims.String("iss"); iss != o.issuer {
+		return fmt.Errorf("unexpected issuer %q", iss)
+	}
+
+	aud := claims.Strings("aud")
+	if !containsString(aud, o.clientId) {
+		return fmt.Errorf("audience %v does not contain client id", aud)
+	}
+	// 多个受众时授权方必须为当前客户端
+	if azp := claims.String("azp"); len(aud) > 1 && azp != o.clientId {
+		return fmt.Errorf("unexpected authorized party %q", azp)
+	}
+
+	now := time.Now()
+	skew := o.opts.ClockSkewSecs * time.Second
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("missing exp")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(skew)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(skew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not valid yet")
+	}
+
+	if claims.String("nonce") != nonce {
+		return errors.New("nonce mismatch")
+	}
+	return nil
+}
+
+// getDiscovery 获得身份提供方元数据，成功获取后缓存
+func (o *oidc) getDiscovery(ctx context.Context) (*Discovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.issuer+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, status, err := o.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err = fmt.Errorf("Http reponse status code is %d not 200.", status)
+		o.logger.ErrorWithFields(logger.Fields{
+			"status_code": status,
+			"error":       err,
+		}, "Error in oidc.getDiscovery.")
+		return nil, err
+	}
+
+	d := new(Discovery)
+	if err = json.Unmarshal(body, d); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", d.Issuer, o.issuer)
+	}
+	// 未声明支持的方式时按支持S256处理
+	if len(d.CodeChallengeMethodsSupported) > 0 && !containsString(d.CodeChallengeMethodsSupported, codeChallengeMethodS256) {
+		return nil, errors.New("identity provider does not support pkce S256")
+	}
+
+	o.issuer = d.Issuer
+	o.discovery = d
+	return d, nil
+}
+
+// getKey 获得签名公钥，找不到时重新获取JWKS以支持密钥轮换
+func (o *oidc) getKey(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	o.mu.Lock()
+	keys := o.keys
+	o.mu.Unlock()
+
+	if k := findKey(keys, kid, alg); k != nil {
+		return k.publicKey()
+	}
+
+	keys, err := o.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if k := findKey(keys, kid, alg); k != nil {
+		return k.publicKey()
+	}
+	return nil, fmt.Errorf("%w: no jwk found for kid %q and alg %q", ErrInvalidIdToken, kid, alg)
+}
+
+// fetchKeys 获取JWKS
+func (o *oidc) fetchKeys(ctx context.Context) ([]*jwk, error) {
+	d, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, status, err := o.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err = fmt.Errorf("Http reponse status code is %d not 200.", status)
+		o.logger.ErrorWithFields(logger.Fields{
+			"status_code": status,
+			"error":       err,
+		}, "Error in oidc.fetchKeys.")
+		return nil, err
+	}
+
+	jwks := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err = json.Unmarshal(body, &jwks); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	o.keys = jwks.Keys
+	o.mu.Unlock()
+	return jwks.Keys, nil
+}
+
+// do 发送请求并读取响应
+func (o *oidc) do(req *http.Request) ([]byte, int, error) {
+	resp, err := o.opts.HttpClient.Do(req)
+	if err != nil {
+		o.logger.ErrorWithFields(logger.Fields{
+			"url":   req.URL.String(),
+			"error": err,
+		}, "Error in http.Client.Do.")
+		return nil, 0, err
+	}
+	// 结束后关闭IO
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		o.logger.ErrorWithFields(logger.Fields{
+			"status_code": resp.StatusCode,
+			"error":       err,
+		}, "Error in ioutil.ReadAll(resp.Body).")
+		return nil, resp.StatusCode, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// findKey 按kid及签名算法查找公钥，ID Token未指定kid时使用唯一可用的公钥
+func findKey(keys []*jwk, kid, alg string) *jwk {
+	var found *jwk
+	for _, k := range keys {
+		if !k.matchAlg(alg) {
+			continue
+		}
+		if kid != "" {
+			if k.Kid == kid {
+				return k
+			}
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = k
+	}
+	return found
+}
+
+// GenRandomString 生成URL安全的随机字符串，用于state、nonce及PKCE的code_verifier
+func GenRandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallengeS256 计算PKCE的code_challenge
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// containsString 切片中是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
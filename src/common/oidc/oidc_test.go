@@ -0,0 +1,295 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testClientId     = "eago"
+	testClientSecret = "secret"
+	testRedirectUrl  = "https://eago.example.com/auth/login/oidc/callback"
+	testKid          = "test-key"
+	testCode         = "test-code"
+)
+
+// mockIdp 本地模拟的身份提供方，按请求签发ID Token
+type mockIdp struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// claims 下一次换取令牌时签发的声明
+	claims Claims
+	// codeChallenge 授权请求中的code_challenge，换取令牌时校验code_verifier
+	codeChallenge string
+}
+
+func newMockIdp(t *testing.T) *mockIdp {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &mockIdp{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJwks)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *mockIdp) issuer() string {
+	return idp.server.URL
+}
+
+func (idp *mockIdp) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJson(w, http.StatusOK, Discovery{
+		Issuer:                        idp.issuer(),
+		AuthorizationEndpoint:         idp.issuer() + "/authorize",
+		TokenEndpoint:                 idp.issuer() + "/token",
+		JwksUri:                       idp.issuer() + "/jwks",
+		CodeChallengeMethodsSupported: []string{codeChallengeMethodS256},
+	})
+}
+
+func (idp *mockIdp) handleJwks(w http.ResponseWriter, _ *http.Request) {
+	pub := idp.key.PublicKey
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"keys": []jwk{{
+			Kty: "RSA",
+			Kid: testKid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *mockIdp) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != testClientId || pass != testClientSecret {
+		writeJson(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("code") != testCode || r.PostForm.Get("redirect_uri") != testRedirectUrl {
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if CodeChallengeS256(r.PostForm.Get("code_verifier")) != idp.codeChallenge {
+		writeJson(w, http.StatusBadRequest, map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "pkce verification failed",
+		})
+		return
+	}
+
+	writeJson(w, http.StatusOK, Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		IdToken:     idp.sign(idp.claims),
+		ExpiresIn:   3600,
+	})
+}
+
+// sign 使用RS256签发ID Token
+func (idp *mockIdp) sign(claims Claims) string {
+	header, _ := json.Marshal(jwsHeader{Alg: "RS256", Kid: testKid, Typ: "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	sum := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, sum[:])
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// baseClaims 可通过校验的声明
+func (idp *mockIdp) baseClaims(nonce string) Claims {
+	now := time.Now()
+	return Claims{
+		"iss":            idp.issuer(),
+		"sub":            "user-1",
+		"aud":            testClientId,
+		"exp":            float64(now.Add(time.Hour).Unix()),
+		"iat":            float64(now.Unix()),
+		"nonce":          nonce,
+		"email":          "user1@example.com",
+		"email_verified": true,
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// login 按授权码流程登录，返回校验后的声明
+func login(t *testing.T, idp *mockIdp, o Oidc, claims func(nonce string) Claims, verifyNonce string) (Claims, error) {
+	ctx := context.Background()
+
+	nonce, _ := GenRandomString()
+	codeVerifier, _ := GenRandomString()
+	authUrl, err := o.AuthCodeURL(ctx, "state", nonce, codeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("nonce") != nonce || q.Get("code_challenge_method") != codeChallengeMethodS256 {
+		t.Fatalf("unexpected auth code url: %s", authUrl)
+	}
+	if !containsString(strings.Fields(q.Get("scope")), "openid") {
+		t.Fatalf("scope %q does not contain openid", q.Get("scope"))
+	}
+
+	idp.codeChallenge = q.Get("code_challenge")
+	idp.claims = claims(nonce)
+
+	tk, err := o.Exchange(ctx, testCode, codeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifyNonce == "" {
+		verifyNonce = nonce
+	}
+	return o.VerifyIdToken(ctx, tk.IdToken, verifyNonce)
+}
+
+func newTestOidc(idp *mockIdp) Oidc {
+	return NewOidc(idp.issuer(), testClientId, testClientSecret, testRedirectUrl, []string{"email"},
+		HttpClient(idp.server.Client()),
+		ClockSkewSecs(0),
+	)
+}
+
+func TestOidcLogin(t *testing.T) {
+	idp := newMockIdp(t)
+	o := newTestOidc(idp)
+
+	claims, err := login(t, idp, o, idp.baseClaims, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.String("iss") != idp.issuer() || claims.String("sub") != "user-1" {
+		t.Errorf("unexpected iss/sub: %v", claims)
+	}
+	if !claims.Bool("email_verified") {
+		t.Error("email_verified should be true")
+	}
+}
+
+func TestOidcVerifyIdTokenRejects(t *testing.T) {
+	idp := newMockIdp(t)
+
+	cases := map[string]struct {
+		claims      func(nonce string) Claims
+		verifyNonce string
+	}{
+		"nonce mismatch": {
+			claims:      idp.baseClaims,
+			verifyNonce: "other-nonce",
+		},
+		"wrong audience": {
+			claims: func(nonce string) Claims {
+				c := idp.baseClaims(nonce)
+				c["aud"] = "other-client"
+				return c
+			},
+		},
+		"wrong issuer": {
+			claims: func(nonce string) Claims {
+				c := idp.baseClaims(nonce)
+				c["iss"] = "https://evil.example.com"
+				return c
+			},
+		},
+		"expired": {
+			claims: func(nonce string) Claims {
+				c := idp.baseClaims(nonce)
+				c["exp"] = float64(time.Now().Add(-time.Minute).Unix())
+				return c
+			},
+		},
+		"multiple audiences without azp": {
+			claims: func(nonce string) Claims {
+				c := idp.baseClaims(nonce)
+				c["aud"] = []interface{}{testClientId, "other-client"}
+				return c
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := login(t, idp, newTestOidc(idp), tc.claims, tc.verifyNonce)
+			if !errors.Is(err, ErrInvalidIdToken) {
+				t.Errorf("expected ErrInvalidIdToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOidcVerifyIdTokenRejectsForgedSignature(t *testing.T) {
+	idp := newMockIdp(t)
+	o := newTestOidc(idp)
+
+	// 使用另一把密钥以相同kid签发
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forger := &mockIdp{t: t, key: other}
+
+	nonce := "nonce"
+	_, err = o.VerifyIdToken(context.Background(), forger.sign(idp.baseClaims(nonce)), nonce)
+	if !errors.Is(err, ErrInvalidIdToken) {
+		t.Errorf("expected ErrInvalidIdToken, got %v", err)
+	}
+}
+
+func TestClaimsBool(t *testing.T) {
+	c := Claims{
+		"bool_true":   true,
+		"string_true": "True",
+		"bool_false":  false,
+		"number":      float64(1),
+	}
+	for name, expected := range map[string]bool{
+		"bool_true":   true,
+		"string_true": true,
+		"bool_false":  false,
+		"number":      false,
+		"missing":     false,
+	} {
+		if got := c.Bool(name); got != expected {
+			t.Errorf("Claims.Bool(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package oidc
+
+import (
+	"eago/common/logger"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultHttpTimeoutSecs = 5
+	defaultClockSkewSecs   = 60
+)
+
+type Option func(o *Options)
+
+// Option struct
+type Options struct {
+	HttpTimeoutSecs time.Duration
+	// HttpClient 访问身份提供方使用的Client，为空时按HttpTimeoutSecs生成
+	HttpClient *http.Client
+	// ClockSkewSecs 校验ID Token有效期时允许的时钟偏差
+	ClockSkewSecs time.Duration
+
+	Logger *logger.Logger
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		HttpTimeoutSecs: defaultHttpTimeoutSecs,
+		ClockSkewSecs:   defaultClockSkewSecs,
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	if opt.HttpClient == nil {
+		opt.HttpClient = &http.Client{Timeout: opt.HttpTimeoutSecs * time.Second}
+	}
+	if opt.Logger == nil {
+		opt.Logger = logger.NewDefaultLogger()
+	}
+
+	return opt
+}
+
+// HttpTimeoutSecs 设置HttpTimeoutSecs，设置了HttpClient时无效
+func HttpTimeoutSecs(in time.Duration) Option {
+	return func(o *Options) {
+		o.HttpTimeoutSecs = in
+	}
+}
+
+// HttpClient 设置访问身份提供方使用的Client，可用于对接本地模拟的身份提供方
+func HttpClient(in *http.Client) Option {
+	return func(o *Options) {
+		o.HttpClient = in
+	}
+}
+
+// ClockSkewSecs 设置ClockSkewSecs
+func ClockSkewSecs(in time.Duration) Option {
+	return func(o *Options) {
+		o.ClockSkewSecs = in
+	}
+}
+
+// Logger 设置Logger
+func Logger(in *logger.Logger) Option {
+	return func(o *Options) {
+		o.Logger = in
+	}
+}
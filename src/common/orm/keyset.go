@@ -0,0 +1,312 @@
+package orm
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"eago/common/global"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	keysetTiebreakColumn = "id"
+
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+var (
+	ErrInvalidOrderBy  = errors.New("invalid order_by")
+	ErrUnsortableOrder = errors.New("column is not sortable")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+
+	orderByRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?:\s+(?i:(asc|desc)))?$`)
+)
+
+type keysetPagingCtxKey struct{}
+
+// KeysetPaging 游标分页参数，通过Context传递给PagingQuery
+type KeysetPaging struct {
+	// Cursor 上一页返回的游标，为空时查询第一页
+	Cursor string
+	// SkipTotal 是否跳过总数查询
+	SkipTotal bool
+	// Sortable 允许排序的字段白名单，为空时不限制
+	Sortable []string
+}
+
+// OrderColumn 排序字段
+type OrderColumn struct {
+	Column string
+	Desc   bool
+}
+
+func (oc OrderColumn) String() string {
+	if oc.Desc {
+		return oc.Column + " " + orderDesc
+	}
+	return oc.Column + " " + orderAsc
+}
+
+// WithKeysetPaging 将游标分页参数放入Context
+func WithKeysetPaging(ctx context.Context, kp *KeysetPaging) context.Context {
+	return context.WithValue(ctx, keysetPagingCtxKey{}, kp)
+}
+
+// KeysetPagingFromCtx 从Context中取出游标分页参数
+func KeysetPagingFromCtx(ctx context.Context) (*KeysetPaging, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	kp, ok := ctx.Value(keysetPagingCtxKey{}).(*KeysetPaging)
+	return kp, ok && kp != nil
+}
+
+// ParseOrderBy 解析排序字段，格式为"column [asc|desc]"，sortable不为空时只允许其中的字段
+func ParseOrderBy(orderBy []string, sortable ...string) ([]OrderColumn, error) {
+	cols := make([]OrderColumn, 0, len(orderBy))
+	for _, o := range orderBy {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+
+		sub := orderByRe.FindStringSubmatch(o)
+		if sub == nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidOrderBy, o)
+		}
+		if len(sortable) > 0 && !inStrings(sortable, sub[1]) {
+			return nil, fmt.Errorf("%w: %q", ErrUnsortableOrder, sub[1])
+		}
+
+		cols = append(cols, OrderColumn{Column: sub[1], Desc: strings.EqualFold(sub[2], orderDesc)})
+	}
+
+	return cols, nil
+}
+
+// keysetPagingQuery 游标分页查询，按排序字段的值定位下一页，避免OFFSET深翻页
+func keysetPagingQuery(
+	db *gorm.DB, kp *KeysetPaging, _pageSize int, _result interface{}, _orderBy ...string,
+) (*Paginator, error) {
+	cols, err := keysetOrderColumns(_orderBy, kp.Sortable...)
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := &Paginator{Page: 1, Total: -1}
+	paginator.PageSize = _pageSize
+	if paginator.PageSize < 1 || paginator.PageSize > global.MaxPageSize {
+		paginator.PageSize = global.DefaultPageSize
+	}
+
+	// 取得总数
+	if !kp.SkipTotal {
+		if res := db.Session(&gorm.Session{}).Count(&paginator.Total); res.Error != nil {
+			return nil, res.Error
+		}
+		paginator.Pages = int(math.Ceil(float64(paginator.Total) / float64(paginator.PageSize)))
+	}
+
+	tx := db.Session(&gorm.Session{})
+	if kp.Cursor != "" {
+		values, err := decodeKeysetCursor(kp.Cursor, len(cols))
+		if err != nil {
+			return nil, err
+		}
+		cond, args := keysetCondition(cols, values)
+		tx = tx.Where(cond, args...)
+	}
+	for _, c := range cols {
+		tx = tx.Order(c.String())
+	}
+
+	// 多取一条用于判断是否还有下一页
+	res := tx.Limit(paginator.PageSize + 1).Find(_result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(_result))
+	if rv.Kind() == reflect.Slice && rv.Len() > paginator.PageSize {
+		rv.Set(rv.Slice(0, paginator.PageSize))
+
+		if res.Statement.Schema == nil {
+			return nil, fmt.Errorf("%w: unknown schema of result", ErrInvalidCursor)
+		}
+		last := rv.Index(rv.Len() - 1)
+		values := make([]interface{}, 0, len(cols))
+		for _, c := range cols {
+			field := res.Statement.Schema.LookUpField(c.Column)
+			if field == nil {
+				return nil, fmt.Errorf("%w: %q", ErrUnsortableOrder, c.Column)
+			}
+			v, _ := field.ValueOf(db.Statement.Context, last)
+			values = append(values, v)
+		}
+		if paginator.NextCursor, err = encodeKeysetCursor(values); err != nil {
+			return nil, err
+		}
+	}
+	paginator.Data = _result
+
+	return paginator, nil
+}
+
+// CheckKeysetCursor 检查游标能否与排序字段匹配
+func CheckKeysetCursor(cursor string, orderBy []string, sortable ...string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	cols, err := keysetOrderColumns(orderBy, sortable...)
+	if err != nil {
+		return err
+	}
+	_, err = decodeKeysetCursor(cursor, len(cols))
+	return err
+}
+
+// keysetOrderColumns 解析排序字段，并追加主键作为最后一个排序字段保证游标唯一
+func keysetOrderColumns(orderBy []string, sortable ...string) ([]OrderColumn, error) {
+	cols, err := ParseOrderBy(orderBy, sortable...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cols {
+		if c.Column == keysetTiebreakColumn {
+			return cols, nil
+		}
+	}
+
+	desc := true
+	if len(cols) > 0 {
+		desc = cols[len(cols)-1].Desc
+	}
+	return append(cols, OrderColumn{Column: keysetTiebreakColumn, Desc: desc}), nil
+}
+
+// keysetCondition 生成游标条件: (c1>v1) OR (c1=v1 AND c2>v2) OR ...，降序字段使用小于
+// 排序字段可为NULL，与MySQL一致将NULL视为最小值，即升序时排在最前，降序时排在最后
+func keysetCondition(cols []OrderColumn, values []interface{}) (string, []interface{}) {
+	ors := make([]string, 0, len(cols))
+	args := make([]interface{}, 0, len(cols)*(len(cols)+1)/2)
+	for i, c := range cols {
+		after, afterArgs := keysetAfter(c, values[i])
+		if after == "" {
+			continue
+		}
+
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				ands = append(ands, fmt.Sprintf("`%s` IS NULL", cols[j].Column))
+				continue
+			}
+			ands = append(ands, fmt.Sprintf("`%s`=?", cols[j].Column))
+			args = append(args, values[j])
+		}
+		ands = append(ands, after)
+		args = append(args, afterArgs...)
+
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+
+	// 游标已是最后一条，不再有数据
+	if len(ors) == 0 {
+		return "1=0", args
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// keysetAfter 生成排序字段位于游标值之后的条件，不存在之后的值时返回空字符串
+func keysetAfter(c OrderColumn, value interface{}) (string, []interface{}) {
+	switch {
+	case value == nil && c.Desc:
+		return "", nil
+	case value == nil:
+		return fmt.Sprintf("`%s` IS NOT NULL", c.Column), nil
+	case c.Desc && c.Column == keysetTiebreakColumn:
+		// 主键不为NULL
+		return fmt.Sprintf("`%s`<?", c.Column), []interface{}{value}
+	case c.Desc:
+		return fmt.Sprintf("(`%s`<? OR `%s` IS NULL)", c.Column, c.Column), []interface{}{value}
+	}
+	return fmt.Sprintf("`%s`>?", c.Column), []interface{}{value}
+}
+
+// encodeKeysetCursor 将最后一条记录的排序字段值编码为游标
+func encodeKeysetCursor(values []interface{}) (string, error) {
+	normalized := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+		// 时间按数据库连接的本地时区格式化，便于直接比较
+		if t, ok := v.(time.Time); ok {
+			v = t.Local().Format(global.TimestampFormat)
+		}
+		normalized = append(normalized, v)
+	}
+
+	b, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeKeysetCursor 解码游标，字段个数需与排序字段一致
+func decodeKeysetCursor(cursor string, n int) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var raw []interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if len(raw) != n {
+		return nil, fmt.Errorf("%w: cursor does not match order_by", ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, 0, n)
+	for _, v := range raw {
+		if num, ok := v.(json.Number); ok {
+			if i, err := num.Int64(); err == nil {
+				v = i
+			} else if f, err := num.Float64(); err == nil {
+				v = f
+			}
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func inStrings(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package orm
+
+import (
+	"database/sql"
+	"eago/common/global"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	sortable := []string{"id", "name", "created_at"}
+
+	cases := []struct {
+		name     string
+		orderBy  []string
+		sortable []string
+		want     []OrderColumn
+		wantErr  error
+	}{
+		{"empty", nil, sortable, []OrderColumn{}, nil},
+		{"blank skipped", []string{"", "  "}, sortable, []OrderColumn{}, nil},
+		{"default asc", []string{"name"}, sortable, []OrderColumn{{"name", false}}, nil},
+		{"desc", []string{"created_at desc"}, sortable, []OrderColumn{{"created_at", true}}, nil},
+		{"case insensitive", []string{"name DESC", " id Asc "}, sortable, []OrderColumn{{"name", true}, {"id", false}}, nil},
+		{"no whitelist", []string{"anything"}, nil, []OrderColumn{{"anything", false}}, nil},
+		{"not in whitelist", []string{"password"}, sortable, nil, ErrUnsortableOrder},
+		{"whitelist is case sensitive", []string{"Name"}, sortable, nil, ErrUnsortableOrder},
+		// 不再接受带表名的排序字段
+		{"table prefix", []string{"users.name"}, sortable, nil, ErrInvalidOrderBy},
+		{"table prefix without whitelist", []string{"users.name desc"}, nil, nil, ErrInvalidOrderBy},
+		{"quoted", []string{"`name`"}, sortable, nil, ErrInvalidOrderBy},
+		{"bad direction", []string{"name up"}, sortable, nil, ErrInvalidOrderBy},
+		{"expression", []string{"name, (select 1)"}, sortable, nil, ErrInvalidOrderBy},
+		{"comment", []string{"name--"}, sortable, nil, ErrInvalidOrderBy},
+		{"leading digit", []string{"1name"}, nil, nil, ErrInvalidOrderBy},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseOrderBy(tc.orderBy, tc.sortable...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ParseOrderBy(%q) error = %v, want %v", tc.orderBy, err, tc.wantErr)
+			}
+			if tc.wantErr == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseOrderBy(%q) = %v, want %v", tc.orderBy, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKeysetOrderColumns(t *testing.T) {
+	cases := []struct {
+		name    string
+		orderBy []string
+		want    []OrderColumn
+	}{
+		{"default id desc", nil, []OrderColumn{{"id", true}}},
+		{"follow last asc", []string{"name"}, []OrderColumn{{"name", false}, {"id", false}}},
+		{"follow last desc", []string{"name", "created_at desc"}, []OrderColumn{{"name", false}, {"created_at", true}, {"id", true}}},
+		{"id present", []string{"id asc", "name desc"}, []OrderColumn{{"id", false}, {"name", true}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := keysetOrderColumns(tc.orderBy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("keysetOrderColumns(%q) = %v, want %v", tc.orderBy, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		values []interface{}
+		want   []interface{}
+	}{
+		{"int", []interface{}{uint32(7)}, []interface{}{int64(7)}},
+		{"string and id", []interface{}{"a\"b", 12}, []interface{}{"a\"b", int64(12)}},
+		{"float", []interface{}{1.5, 3}, []interface{}{1.5, int64(3)}},
+		{"null", []interface{}{nil, 3}, []interface{}{nil, int64(3)}},
+		{"null valuer", []interface{}{sql.NullString{}, 3}, []interface{}{nil, int64(3)}},
+		{"valuer", []interface{}{sql.NullInt64{Int64: 9, Valid: true}, 3}, []interface{}{int64(9), int64(3)}},
+		{"bool", []interface{}{true, 3}, []interface{}{true, int64(3)}},
+		{"time", []interface{}{now, 3}, []interface{}{now.Local().Format(global.TimestampFormat), int64(3)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := encodeKeysetCursor(tc.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decodeKeysetCursor(cursor, len(tc.values))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("decodeKeysetCursor(encodeKeysetCursor(%v)) = %#v, want %#v", tc.values, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeKeysetCursorInvalid(t *testing.T) {
+	valid, err := encodeKeysetCursor([]interface{}{"a", 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		cursor string
+		n      int
+	}{
+		{"not base64", "!!!", 2},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`["a",1]`)), 2},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("abc")), 2},
+		{"not array", base64.RawURLEncoding.EncodeToString([]byte(`{"a":1}`)), 2},
+		{"too few values", valid, 3},
+		{"too many values", valid, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := decodeKeysetCursor(tc.cursor, tc.n); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeKeysetCursor(%q, %d) error = %v, want %v", tc.cursor, tc.n, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestCheckKeysetCursor(t *testing.T) {
+	cursor, err := encodeKeysetCursor([]interface{}{"a", 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = CheckKeysetCursor("", []string{"users.name"}); err != nil {
+		t.Errorf("empty cursor should not be checked, got %v", err)
+	}
+	if err = CheckKeysetCursor(cursor, []string{"name desc"}, "name"); err != nil {
+		t.Errorf("cursor should match order_by, got %v", err)
+	}
+	if err = CheckKeysetCursor(cursor, nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("cursor should not match default order, got %v", err)
+	}
+	if err = CheckKeysetCursor(cursor, []string{"name"}, "id"); !errors.Is(err, ErrUnsortableOrder) {
+		t.Errorf("unsortable column should be rejected, got %v", err)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	asc := []OrderColumn{{"created_at", false}, {"id", false}}
+	desc := []OrderColumn{{"created_at", true}, {"id", true}}
+
+	cases := []struct {
+		name     string
+		cols     []OrderColumn
+		values   []interface{}
+		wantCond string
+		wantArgs []interface{}
+	}{
+		{
+			"asc",
+			asc, []interface{}{"t", int64(5)},
+			"((`created_at`>?) OR (`created_at`=? AND `id`>?))",
+			[]interface{}{"t", "t", int64(5)},
+		},
+		{
+			"desc includes trailing nulls",
+			desc, []interface{}{"t", int64(5)},
+			"(((`created_at`<? OR `created_at` IS NULL)) OR (`created_at`=? AND `id`<?))",
+			[]interface{}{"t", "t", int64(5)},
+		},
+		{
+			// 升序时NULL排在最前，之后为同为NULL的其余记录及所有非NULL记录
+			"asc null",
+			asc, []interface{}{nil, int64(5)},
+			"((`created_at` IS NOT NULL) OR (`created_at` IS NULL AND `id`>?))",
+			[]interface{}{int64(5)},
+		},
+		{
+			// 降序时NULL排在最后，之后只剩同为NULL的其余记录
+			"desc null",
+			desc, []interface{}{nil, int64(5)},
+			"((`created_at` IS NULL AND `id`<?))",
+			[]interface{}{int64(5)},
+		},
+		{
+			"mixed directions",
+			[]OrderColumn{{"name", false}, {"created_at", true}, {"id", true}},
+			[]interface{}{"n", nil, int64(5)},
+			"((`name`>?) OR (`name`=? AND `created_at` IS NULL AND `id`<?))",
+			[]interface{}{"n", "n", int64(5)},
+		},
+		{
+			"last row",
+			[]OrderColumn{{"name", true}},
+			[]interface{}{nil},
+			"1=0",
+			[]interface{}{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cond, args := keysetCondition(tc.cols, tc.values)
+			if cond != tc.wantCond {
+				t.Errorf("keysetCondition() cond = %s, want %s", cond, tc.wantCond)
+			}
+			if !reflect.DeepEqual(args, tc.wantArgs) {
+				t.Errorf("keysetCondition() args = %#v, want %#v", args, tc.wantArgs)
+			}
+		})
+	}
+}
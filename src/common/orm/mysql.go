@@ -1,11 +1,16 @@
 package orm
 
 import (
+	"errors"
 	"fmt"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// mysqlErrDupEntry 违反唯一索引的MySQL错误码
+const mysqlErrDupEntry = 1062
+
 // NewMysqlGorm 新建MysqlGorm
 func NewMysqlGorm(address, user, password, dbName string, opts ...Option) *gorm.DB {
 	dsn := fmt.Sprintf(
@@ -36,3 +41,9 @@ func NewMysqlGorm(address, user, password, dbName string, opts ...Option) *gorm.
 
 	return d
 }
+
+// IsDuplicateKeyErr 是否为违反唯一索引的错误
+func IsDuplicateKeyErr(err error) bool {
+	var mErr *mysqlDriver.MySQLError
+	return errors.As(err, &mErr) && mErr.Number == mysqlErrDupEntry
+}
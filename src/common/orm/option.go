@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"eago/common/metrics"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"time"
@@ -80,3 +81,15 @@ func UsingOpentracingPlugin() Option {
 		_ = d.Use(new(OpentracingPlugin))
 	}
 }
+
+// UsingDbStatsMetrics 暴露连接池指标
+func UsingDbStatsMetrics(name string) Option {
+	return func(d *gorm.DB) {
+		if d == nil {
+			return
+		}
+		if sqlDB, err := d.DB(); err == nil {
+			metrics.RegisterSqlDbStats(name, sqlDB)
+		}
+	}
+}
@@ -15,10 +15,17 @@ type Paginator struct {
 	PageSize int         `json:"page_size"`
 	Total    int64       `json:"total"`
 	Data     interface{} `json:"data"`
-	Offset   int
+	// NextCursor 游标分页时下一页的游标，为空表示没有下一页
+	NextCursor string `json:"next_cursor,omitempty"`
+	Offset     int
 }
 
+// PagingQuery 分页查询器，Context中带有KeysetPaging时使用游标分页，否则使用OFFSET分页
 func PagingQuery(db *gorm.DB, _page, _pageSize int, _result interface{}, _orderBy ...string) (*Paginator, error) {
+	if kp, ok := KeysetPagingFromCtx(db.Statement.Context); ok {
+		return keysetPagingQuery(db, kp, _pageSize, _result, _orderBy...)
+	}
+
 	// 处理OrderBy
 	for _, o := range _orderBy {
 		if len(o) < 1 {
@@ -34,13 +41,15 @@ func PagingQuery(db *gorm.DB, _page, _pageSize int, _result interface{}, _orderB
 	// 设置PageSize，每页的对象数量
 	paginator.PageSize = utils.IntMin(utils.IntMax(_pageSize, 1), global.MaxPageSize)
 	// 设置Offset，查询偏移量
-	paginator.Offset = (paginator.Page - 1) * paginator.PageSize
+	paginator.Offset = (utils.IntMax(_page, 1) - 1) * paginator.PageSize
+
+	// 取得总数，Count会带上Limit和Offset，需与分页查询分开
+	if res := db.Session(&gorm.Session{}).Count(&paginator.Total); res.Error != nil {
+		return nil, res.Error
+	}
 
 	// 分页查询
-	res := db.Session(&gorm.Session{}).
-		Limit(paginator.PageSize).Offset(paginator.Offset).
-		Count(&paginator.Total).
-		Find(_result)
+	res := db.Session(&gorm.Session{}).Limit(paginator.PageSize).Offset(paginator.Offset).Find(_result)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -89,7 +98,7 @@ func PagingQueryCoroutine(
 	// 设置PageSize，每页的对象数量
 	paginator.PageSize = utils.IntMin(utils.IntMax(_pageSize, 1), global.MaxPageSize)
 	// 设置Offset，查询偏移量
-	paginator.Offset = (paginator.Page - 1) * paginator.PageSize
+	paginator.Offset = (utils.IntMax(_page, 1) - 1) * paginator.PageSize
 
 	// 分页查询
 	res := tx.Limit(paginator.PageSize).Offset(paginator.Offset).Find(_result)
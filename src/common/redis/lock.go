@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// tryLockScript 锁不存在时由owner获得，已由owner持有时续期
+	tryLockScript = NewScript(`
+local v = redis.call('GET', KEYS[1])
+if v == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+if v == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+	// unlockScript 只释放owner持有的锁
+	unlockScript = NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// TryLock 尝试获得分布式锁，owner已持有时续期，锁由其他owner持有时返回false
+func (rt *RedisTool) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	res, err := rt.RunScript(ctx, tryLockScript, []string{key}, owner, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	return res.(int64) == 1, nil
+}
+
+// Unlock 释放owner持有的分布式锁，锁已过期或由其他owner持有时忽略
+func (rt *RedisTool) Unlock(ctx context.Context, key, owner string) error {
+	_, err := rt.RunScript(ctx, unlockScript, []string{key}, owner)
+	return err
+}
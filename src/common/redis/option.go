@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"eago/common/metrics"
 	"github.com/go-redis/redis/v8"
 )
 
@@ -15,3 +16,13 @@ func UsingOpentracingHook() Option {
 		c.AddHook(NewOpentracingHook())
 	}
 }
+
+// UsingPoolStatsMetrics 暴露连接池指标
+func UsingPoolStatsMetrics(name string) Option {
+	return func(c *redis.Client) {
+		if c == nil {
+			return
+		}
+		metrics.RegisterRedisPoolStats(name, c.PoolStats)
+	}
+}
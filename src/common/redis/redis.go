@@ -10,6 +10,15 @@ const (
 	defaultKeyPrefix = "/td/eago"
 )
 
+// KeepTTL 设置值时保持Key原有的过期时间
+const KeepTTL = redis.KeepTTL
+
+// Script Lua脚本，用于需要原子执行的多个操作
+type Script = redis.Script
+
+// NewScript 新建Lua脚本
+var NewScript = redis.NewScript
+
 type RedisTool struct {
 	client      *redis.Client
 	serviceName string
@@ -45,6 +54,21 @@ func (rt *RedisTool) Set(ctx context.Context, key string, value interface{}, exp
 	return rt.client.Set(ctx, rt.getFinalKey(key), value, expiration).Err()
 }
 
+// SetXX 仅在Key存在时设置值，Key不存在时返回false
+func (rt *RedisTool) SetXX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return rt.client.SetXX(ctx, rt.getFinalKey(key), value, expiration).Result()
+}
+
+// SetNX 仅在Key不存在时设置值，Key已存在时返回false
+func (rt *RedisTool) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return rt.client.SetNX(ctx, rt.getFinalKey(key), value, expiration).Result()
+}
+
+// Incr 将Key的值加1并返回加1后的值，Key不存在时从0开始
+func (rt *RedisTool) Incr(ctx context.Context, key string) (int64, error) {
+	return rt.client.Incr(ctx, rt.getFinalKey(key)).Result()
+}
+
 func (rt *RedisTool) Del(ctx context.Context, key string) error {
 	return rt.client.Del(ctx, rt.getFinalKey(key)).Err()
 }
@@ -61,6 +85,30 @@ func (rt *RedisTool) Exist(ctx context.Context, key string) bool {
 	return 0 < rt.client.Exists(ctx, rt.getFinalKey(key)).Val()
 }
 
+// SAdd 向集合中添加成员
+func (rt *RedisTool) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return rt.client.SAdd(ctx, rt.getFinalKey(key), members...).Err()
+}
+
+// SRem 从集合中移除成员
+func (rt *RedisTool) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return rt.client.SRem(ctx, rt.getFinalKey(key), members...).Err()
+}
+
+// SMembers 列出集合中所有成员
+func (rt *RedisTool) SMembers(ctx context.Context, key string) ([]string, error) {
+	return rt.client.SMembers(ctx, rt.getFinalKey(key)).Result()
+}
+
+// RunScript 执行Lua脚本，keys会自动加上前缀
+func (rt *RedisTool) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	finalKeys := make([]string, 0, len(keys))
+	for _, k := range keys {
+		finalKeys = append(finalKeys, rt.getFinalKey(k))
+	}
+	return script.Run(ctx, rt.client, finalKeys, args...).Result()
+}
+
 // DirectGet 直接获取redis中的值
 func (rt *RedisTool) DirectGet(ctx context.Context, key string) (string, error) {
 	return rt.client.Get(ctx, key).Result()
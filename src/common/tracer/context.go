@@ -4,6 +4,8 @@ import (
 	"context"
 	"eago/common/global"
 	"github.com/gin-gonic/gin"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
 )
 
 // ExtractTraceCtxFromGin 从gin.context中提取带有tracer的Context
@@ -16,3 +18,15 @@ func ExtractTraceCtxFromGin(c *gin.Context) context.Context {
 
 	return context.Background()
 }
+
+// TraceIdFromCtx 获得context中span的trace id，不存在时返回空字符串
+func TraceIdFromCtx(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	if jSpanCtx, ok := span.Context().(jaeger.SpanContext); ok {
+		return jSpanCtx.TraceID().String()
+	}
+	return ""
+}
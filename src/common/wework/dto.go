@@ -10,4 +10,6 @@ type WeworkDepartment struct {
 	Id       int
 	Name     string
 	ParentId int
+	// Leaders 部门负责人的UserID列表
+	Leaders []string
 }
@@ -250,11 +250,21 @@ func (w *wework) ListDepartments() ([]*WeworkDepartment, error) {
 
 	for _, v := range jsonBody["department"].([]interface{}) {
 		mp := v.(map[string]interface{})
-		wd = append(wd, &WeworkDepartment{
+		dept := &WeworkDepartment{
 			Id:       int(mp["id"].(float64)),
 			Name:     mp["name"].(string),
 			ParentId: int(mp["parentid"].(float64)),
-		})
+			Leaders:  make([]string, 0),
+		}
+		// 部门负责人字段需要应用有对应权限才会返回
+		if leaders, ok := mp["department_leader"].([]interface{}); ok {
+			for _, l := range leaders {
+				if uid, ok := l.(string); ok {
+					dept.Leaders = append(dept.Leaders, uid)
+				}
+			}
+		}
+		wd = append(wd, dept)
 	}
 
 	return wd, nil
@@ -4,8 +4,12 @@ import (
 	"context"
 	"eago/common/api"
 	perm "eago/common/api/permission"
+	"eago/common/api/ratelimit"
+	"eago/common/audit"
 	"eago/common/broker"
 	"eago/common/logger"
+	"eago/common/metrics"
+	"eago/common/redis"
 	"eago/common/service"
 	"eago/common/tracer"
 	"eago/flow/api/handler"
@@ -33,7 +37,7 @@ type flowApi struct {
 	cancelFunc context.CancelFunc
 }
 
-func NewFlowApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
+func NewFlowApi(dao *dao.Dao, auditor audit.Auditor, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// 生成etcdRegistry
@@ -71,7 +75,7 @@ func NewFlowApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.Ea
 	_api := web.NewService(
 		web.Name(conf.Const.ApiRegisterKey),
 		web.Address(conf.ApiListen),
-		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler)),
+		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler, auditor, redis)),
 		web.Registry(etcdReg),
 		web.RegisterTTL(conf.MicroRegisterTtl),
 		web.RegisterInterval(conf.MicroRegisterInterval),
@@ -95,6 +99,16 @@ func NewFlowApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.Ea
 func (fa *flowApi) Start() error {
 	fa.logger.Info("Starting flow api ...")
 
+	// 在内部监听地址上暴露Prometheus指标，不对外暴露
+	go func() {
+		if err := metrics.Serve(fa.ctx, fa.conf.ApiMetricsListen); err != nil {
+			fa.logger.ErrorWithFields(logger.Fields{
+				"address": fa.conf.ApiMetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
 	return fa.api.Run()
 }
 
@@ -104,13 +118,35 @@ func (fa *flowApi) Stop() {
 	}
 }
 
-func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *handler.FlowHandler) *gin.Engine {
+func newGinEngine(
+	ginMode string,
+	_conf *conf.Conf,
+	logger *logger.Logger,
+	h *handler.FlowHandler,
+	auditor audit.Auditor,
+	redis *redis.RedisTool,
+) *gin.Engine {
 	gin.SetMode(ginMode)
 
 	engine := gin.New()
-	engine.Use(api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware)
+	// 客户端IP只从可信代理转发的X-Forwarded-For中获取，gin自身的解析在未调用engine.Run时会信任任意来源
+	engine.ForwardedByClientIP = false
+	engine.Use(
+		api.ClientIpMiddleware(_conf.TrustedProxies),
+		api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware, api.MetricsMiddleware,
+	)
 
-	fGroup := engine.Group("/flow", perm.MustLogin(h.GetAuthCli()))
+	// 支持游标分页的列表及其允许排序的字段
+	instancesPaging := api.KeysetPagingQueryMiddleware("id", "name", "status", "created_at", "updated_at")
+	// 按用户限制发起流程的频率
+	instantiateLimit := ratelimit.NewLimiter(
+		redis, "instantiate_flow",
+		ratelimit.Limit(_conf.InstantiateFlowRateLimit),
+		ratelimit.Window(_conf.InstantiateFlowRateWindow),
+		ratelimit.Logger(logger),
+	).Middleware(ratelimit.ByUser)
+
+	fGroup := engine.Group("/flow", perm.MustLogin(h.GetAuthCli()), auditor.Middleware())
 	{
 		// 根据当前登录用户权限列出菜单
 		fGroup.GET("/menus", h.ListMenus)
@@ -122,14 +158,14 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 			iR.PUT("/:instance_id/handle", h.HandleInstance)
 
 			// 列出我发起的流程实例
-			iR.GET("/my", api.PagingQueryMiddleware, h.PagedListMyInstances)
+			iR.GET("/my", instancesPaging, h.PagedListMyInstances)
 			// 列出我代办的流程实例
-			iR.GET("/todo", api.PagingQueryMiddleware, h.PagedListTodoInstances)
+			iR.GET("/todo", instancesPaging, h.PagedListTodoInstances)
 			// 列出我已办的流程实例
-			iR.GET("/done", api.PagingQueryMiddleware, h.PagedListDoneInstances)
+			iR.GET("/done", instancesPaging, h.PagedListDoneInstances)
 
 			// 列出所有流程实例，要求管理员权限
-			iR.GET("", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, h.PagedListInstances)
+			iR.GET("", perm.MustRole(_conf.Const.AdminRole), instancesPaging, h.PagedListInstances)
 		}
 
 		// Log审批日志模块
@@ -162,7 +198,7 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 		flR := fGroup.Group("/flows")
 		{
 			// 发起流程
-			flR.POST("/:flow_id/instantiate", h.InstantiateFlow)
+			flR.POST("/:flow_id/instantiate", instantiateLimit, h.InstantiateFlow)
 
 			// 分页列出所有流程
 			flR.GET("", api.PagingQueryMiddleware, h.PagedListFlows)
@@ -233,6 +269,11 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 			// 列出指定触发器所关联节点，要求管理员权限
 			tR.GET("/:trigger_id/nodes", perm.MustRole(_conf.Const.AdminRole), h.ListTriggersNodes)
 		}
+
+		// 审计日志，要求管理员权限
+		fGroup.GET("/audit_logs", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, auditor.ListHandler)
+		// 导出审计日志，要求管理员权限
+		fGroup.GET("/audit_logs/export", perm.MustRole(_conf.Const.AdminRole), auditor.ExportHandler)
 	}
 
 	engine.NoRoute(api.PageNotFound)
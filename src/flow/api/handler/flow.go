@@ -3,8 +3,10 @@ package handler
 import (
 	"eago/common/api/ext"
 	perm "eago/common/api/permission"
+	"eago/common/audit"
 	cMsg "eago/common/code_msg"
 	"eago/common/global"
+	"eago/common/orm"
 	"eago/common/tracer"
 	"eago/flow/api/form"
 	"eago/flow/conf/msg"
@@ -181,6 +183,11 @@ func (h *FlowHandler) SetFlow(c *gin.Context) {
 		return
 	}
 
+	// 记录修改前的内容用于审计
+	if before, err := h.dao.GetFlow(ctx, orm.Query{"id=?": flowId}); err == nil {
+		audit.SetBefore(c, before)
+	}
+
 	flow, err := h.dao.SetFlow(
 		ctx,
 		flowId,
@@ -200,6 +207,7 @@ func (h *FlowHandler) SetFlow(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, flow)
 	ext.WriteSuccessPayload(c, "flow", flow)
 }
 
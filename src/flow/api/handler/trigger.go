@@ -3,8 +3,10 @@ package handler
 import (
 	"eago/common/api/ext"
 	perm "eago/common/api/permission"
+	"eago/common/audit"
 	cMsg "eago/common/code_msg"
 	"eago/common/global"
+	"eago/common/orm"
 	"eago/common/tracer"
 	"eago/flow/api/form"
 	"eago/flow/conf/msg"
@@ -105,6 +107,11 @@ func (h *FlowHandler) SetTrigger(c *gin.Context) {
 		return
 	}
 
+	// 记录修改前的内容用于审计
+	if before, err := h.dao.GetTrigger(ctx, orm.Query{"id=?": triId}); err == nil {
+		audit.SetBefore(c, before)
+	}
+
 	tri, err := h.dao.SetTrigger(
 		ctx, triId, frm.Name, *frm.Description, frm.TaskCodename, frm.Arguments, perm.MustGetTokenContent(c).Username,
 	)
@@ -115,6 +122,7 @@ func (h *FlowHandler) SetTrigger(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, tri)
 	ext.WriteSuccessPayload(c, "trigger", tri)
 }
 
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"eago/common/audit"
 	"eago/common/logger"
 	"eago/common/orm"
+	"eago/common/redis"
 	"eago/common/service"
 	"eago/flow/conf"
 	"eago/flow/dao"
@@ -16,14 +18,16 @@ import (
 var (
 	flow service.EagoSrv
 
-	flowDao *dao.Dao
+	flowDao     *dao.Dao
+	flowAuditor audit.Auditor
+	flowRedis   *redis.RedisTool
 
 	flowConf *conf.Conf
 	flowLg   *logger.Logger
 )
 
 func main() {
-	flow = NewFlowApi(flowDao, flowConf, flowLg)
+	flow = NewFlowApi(flowDao, flowAuditor, flowRedis, flowConf, flowLg)
 
 	e := make(chan error)
 	go func() {
@@ -82,7 +86,7 @@ func init() {
 	}
 	flowLg = lg
 
-	flowDao = dao.NewDao(orm.NewMysqlGorm(
+	db := orm.NewMysqlGorm(
 		flowConf.MysqlAddress,
 		flowConf.MysqlUser,
 		flowConf.MysqlPassword,
@@ -90,5 +94,19 @@ func init() {
 		orm.MysqlMaxIdleConns(flowConf.MysqlMaxIdleConns),
 		orm.MysqlMaxOpenConns(flowConf.MysqlMaxOpenConns),
 		orm.UsingOpentracingPlugin(),
-	), flowConf, flowLg)
+		orm.UsingDbStatsMetrics("mysql"),
+	)
+	flowDao = dao.NewDao(db, flowConf, flowLg)
+	// 审计日志与业务数据使用同一数据库
+	flowAuditor = audit.NewAuditor(db, flowConf.Const.ServiceName, audit.Logger(flowLg))
+
+	// 用于接口限流
+	flowRedis = redis.NewRedisTool(
+		flowConf.RedisAddress,
+		flowConf.RedisPassword,
+		flowConf.Const.ServiceName,
+		flowConf.RedisDb,
+		redis.UsingOpentracingHook(),
+		redis.UsingPoolStatsMetrics("redis"),
+	)
 }
@@ -12,7 +12,9 @@ type Conf struct {
 	Const *constConf
 
 	ApiListen             string
+	ApiMetricsListen      string
 	GinMode               string
+	TrustedProxies        []string
 	MicroRegisterTtl      time.Duration
 	MicroRegisterInterval time.Duration
 
@@ -33,9 +35,16 @@ type Conf struct {
 	MysqlMaxIdleConns int
 	MysqlMaxOpenConns int
 
+	RedisAddress  string
+	RedisPassword string
+	RedisDb       int
+
 	KafkaAddresses []string
 
 	JaegerAddress string
+
+	InstantiateFlowRateLimit  int
+	InstantiateFlowRateWindow time.Duration
 }
 
 func NewConfig(options ...Option) *Conf {
@@ -50,8 +59,10 @@ func NewConfig(options ...Option) *Conf {
 	return &Conf{
 		Const: newConstConf(),
 
-		ApiListen: cfg.MustValue("main", "api_listen", defaultApiListen),
-		GinMode:   cfg.MustValue("main", "gin_mode", defaultGinModel),
+		ApiListen:        cfg.MustValue("main", "api_listen", defaultApiListen),
+		ApiMetricsListen: cfg.MustValue("main", "api_metrics_listen", defaultApiMetricsListen),
+		GinMode:          cfg.MustValue("main", "gin_mode", defaultGinModel),
+		TrustedProxies:   cfg.MustValueArray("main", "trusted_proxies", global.DefaultConfigSeparator),
 		MicroRegisterTtl: time.Duration(cfg.MustInt(
 			"main", "register_ttl", defaultMicroRegisterTtl,
 		)) * time.Second,
@@ -76,8 +87,17 @@ func NewConfig(options ...Option) *Conf {
 		MysqlMaxIdleConns: cfg.MustInt("mysql", "max_idle_conns", defaultMysqlMaxIdleConns),
 		MysqlMaxOpenConns: cfg.MustInt("mysql", "max_open_conns", defaultMysqlMaxOpenConns),
 
+		RedisAddress:  cfg.MustValue("redis", "address", defaultRedisAddress),
+		RedisPassword: cfg.MustValue("redis", "password", defaultRedisPassword),
+		RedisDb:       cfg.MustInt("redis", "db", defaultRedisDb),
+
 		KafkaAddresses: cfg.MustValueArray("kafka", "addresses", global.DefaultConfigSeparator),
 
 		JaegerAddress: cfg.MustValue("tracer", "jaeger_address", defaultJaegerAddress),
+
+		InstantiateFlowRateLimit: cfg.MustInt("ratelimit", "instantiate_flow_limit", defaultInstantiateFlowRateLimit),
+		InstantiateFlowRateWindow: time.Duration(cfg.MustInt(
+			"ratelimit", "instantiate_flow_window_secs", defaultInstantiateFlowRateWindowSecs,
+		)) * time.Second,
 	}
 }
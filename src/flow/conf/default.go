@@ -2,6 +2,7 @@ package conf
 
 const (
 	defaultApiListen             = "127.0.0.1:0"
+	defaultApiMetricsListen      = ""
 	defaultGinModel              = "release"
 	defaultMicroRegisterTtl      = 10
 	defaultMicroRegisterInterval = 3
@@ -24,6 +25,15 @@ const (
 	defaultMysqlMaxOpenConns = 20
 	defaultMysqlMaxIdleConns = 5
 
+	// Redis默认配置
+	defaultRedisAddress  = "127.0.0.1:6379"
+	defaultRedisPassword = ""
+	defaultRedisDb       = 1
+
 	// Jaeger默认配置
 	defaultJaegerAddress = "127.0.0.1:5775"
+
+	// 接口限流默认配置
+	defaultInstantiateFlowRateLimit      = 30
+	defaultInstantiateFlowRateWindowSecs = 60
 )
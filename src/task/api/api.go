@@ -4,7 +4,11 @@ import (
 	"context"
 	"eago/common/api"
 	perm "eago/common/api/permission"
+	"eago/common/api/ratelimit"
+	"eago/common/audit"
 	"eago/common/logger"
+	"eago/common/metrics"
+	"eago/common/redis"
 	"eago/common/service"
 	"eago/common/tracer"
 	"eago/task/api/handler"
@@ -31,7 +35,7 @@ type taskApi struct {
 	cancelFunc context.CancelFunc
 }
 
-func NewTaskApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
+func NewTaskApi(dao *dao.Dao, auditor audit.Auditor, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) service.EagoSrv {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// 生成etcdRegistry
@@ -56,7 +60,7 @@ func NewTaskApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.Ea
 	_api := web.NewService(
 		web.Name(conf.Const.ApiRegisterKey),
 		web.Address(conf.ApiListen),
-		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler)),
+		web.Handler(newGinEngine(conf.GinMode, conf, logger, _handler, auditor, redis)),
 		web.Registry(etcdReg),
 		web.RegisterTTL(conf.MicroRegisterTtl),
 		web.RegisterInterval(conf.MicroRegisterInterval),
@@ -80,6 +84,16 @@ func NewTaskApi(dao *dao.Dao, conf *conf.Conf, logger *logger.Logger) service.Ea
 func (ta *taskApi) Start() error {
 	ta.logger.Info("Starting task api ...")
 
+	// 在内部监听地址上暴露Prometheus指标，不对外暴露
+	go func() {
+		if err := metrics.Serve(ta.ctx, ta.conf.ApiMetricsListen); err != nil {
+			ta.logger.ErrorWithFields(logger.Fields{
+				"address": ta.conf.ApiMetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
 	return ta.api.Run()
 }
 
@@ -89,19 +103,57 @@ func (ta *taskApi) Stop() {
 	}
 }
 
-func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *handler.TaskHandler) *gin.Engine {
+func newGinEngine(
+	ginMode string,
+	_conf *conf.Conf,
+	logger *logger.Logger,
+	h *handler.TaskHandler,
+	auditor audit.Auditor,
+	redis *redis.RedisTool,
+) *gin.Engine {
 	gin.SetMode(ginMode)
 
 	engine := gin.New()
-	engine.Use(api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware)
+	// 客户端IP只从可信代理转发的X-Forwarded-For中获取，gin自身的解析在未调用engine.Run时会信任任意来源
+	engine.ForwardedByClientIP = false
+	engine.Use(
+		api.ClientIpMiddleware(_conf.TrustedProxies),
+		api.GinCustomLogger(logger), gin.Recovery(), api.OpentracingMiddleware, api.MetricsMiddleware,
+	)
 
-	g := engine.Group("/task", perm.MustLogin(h.GetAuthCli()))
+	// 支持游标分页的列表及其允许排序的字段
+	tasksPaging := api.KeysetPagingQueryMiddleware("id", "codename", "category", "created_at", "updated_at")
+	artifactsPaging := api.KeysetPagingQueryMiddleware("id", "name", "version", "created_at")
+	schedulesPaging := api.KeysetPagingQueryMiddleware("id", "task_codename", "created_at", "updated_at")
+	resultsPaging := api.KeysetPagingQueryMiddleware("id", "task_codename", "status", "start_at", "end_at")
+	// 调用、结束任务按配置要求Token通过了两步验证
+	mustMfa := perm.MustMfaIf(_conf.SensitiveRequireMfa)
+	// 按用户限制调用任务的频率
+	callLimit := ratelimit.NewLimiter(
+		redis, "call_task",
+		ratelimit.Limit(_conf.CallTaskRateLimit),
+		ratelimit.Window(_conf.CallTaskRateWindow),
+		ratelimit.Logger(logger),
+	).Middleware(ratelimit.ByUser)
+
+	g := engine.Group("/task", perm.MustLogin(h.GetAuthCli()), auditor.Middleware())
 	{
 		// 根据当前登录用户权限列出菜单
 		g.GET("/menus", h.ListMenus)
 
 		// 列出所有Worker
 		g.GET("/workers", perm.MustRole(_conf.Const.AdminRole), h.ListWorkers)
+		wr := g.Group("/workers/:worker_id", perm.MustRole(_conf.Const.AdminRole))
+		{
+			// 查看Worker
+			wr.GET("", h.GetWorker)
+			// 隔离和取消隔离Worker
+			wr.POST("/cordon", h.CordonWorker)
+			wr.POST("/uncordon", h.UncordonWorker)
+			// 暂停和恢复Worker
+			wr.POST("/pause", h.PauseWorker)
+			wr.POST("/resume", h.ResumeWorker)
+		}
 
 		// Task模块
 		tr := g.Group("/tasks")
@@ -109,19 +161,39 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 			tr.POST("", perm.MustRole(_conf.Const.AdminRole), h.NewTask)
 			tr.DELETE("/:task_id", perm.MustRole(_conf.Const.AdminRole), h.RemoveTask)
 			tr.PUT("/:task_id", perm.MustRole(_conf.Const.AdminRole), h.SetTask)
-			tr.GET("", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, h.PagedListTasks)
+			tr.GET("", perm.MustRole(_conf.Const.AdminRole), tasksPaging, h.PagedListTasks)
+			// 获得任务形参(JSON Schema)
+			tr.GET("/:task_id/formal_params", perm.MustRole(_conf.Const.AdminRole), h.GetTaskFormalParams)
+			// 列出实现了任务的活跃Worker
+			tr.GET("/:task_id/workers", perm.MustRole(_conf.Const.AdminRole), h.ListTaskWorkers)
 
 			// 调用任务
-			tr.POST("/:task_id/call", perm.MustRole(_conf.Const.AdminRole), h.CallTask)
+			tr.POST("/:task_id/call", perm.MustRole(_conf.Const.AdminRole), mustMfa, callLimit, h.CallTask)
+		}
+
+		// Artifact模块
+		ar := g.Group("/artifacts")
+		{
+			// 上传制品
+			ar.POST("", perm.MustRole(_conf.Const.AdminRole), h.NewArtifact)
+			// 列出所有制品
+			ar.GET("", perm.MustRole(_conf.Const.AdminRole), artifactsPaging, h.PagedListArtifacts)
+			// 获得制品信息
+			ar.GET("/:artifact_id", perm.MustRole(_conf.Const.AdminRole), h.GetArtifact)
+			// 下载制品
+			ar.GET("/:artifact_id/download", perm.MustRole(_conf.Const.AdminRole), h.DownloadArtifact)
+			// 校验制品
+			ar.GET("/:artifact_id/checksum", perm.MustRole(_conf.Const.AdminRole), h.ChecksumArtifact)
 		}
 
 		// Schedule模块
 		sr := g.Group("/schedules")
 		{
-			sr.POST("", perm.MustRole(_conf.Const.AdminRole), h.NewSchedule)
-			sr.DELETE("/:schedule_id", perm.MustRole(_conf.Const.AdminRole), h.RemoveSchedule)
-			sr.PUT("/:schedule_id", perm.MustRole(_conf.Const.AdminRole), h.SetSchedule)
-			sr.GET("", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, h.PagedListSchedules)
+			scheduleWrite := perm.MustPermOrRole(conf.PermScheduleWrite, _conf.Const.AdminRole)
+			sr.POST("", scheduleWrite, h.NewSchedule)
+			sr.DELETE("/:schedule_id", scheduleWrite, h.RemoveSchedule)
+			sr.PUT("/:schedule_id", scheduleWrite, h.SetSchedule)
+			sr.GET("", perm.MustPermOrRole(conf.PermScheduleRead, _conf.Const.AdminRole), schedulesPaging, h.PagedListSchedules)
 		}
 
 		// ResultTables模块
@@ -137,22 +209,31 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 
 			// 列出所有结果分区
 			rpr.GET("", h.ListResultPartitions)
+			// 列出所有结果分区的行数及大小
+			rpr.GET("/stats", perm.MustRole(_conf.Const.AdminRole), h.ListResultPartitionStats)
 		}
 
 		// Result模块
 		rr := g.Group("/results")
 		{
+			// 跨分区查询结果
+			rr.GET("/search", h.SearchResults)
+			// 统计结果
+			rr.GET("/stats", h.GetResultStats)
 			// 按分区ID列出所有结果
-			rr.GET("/:result_partition_id", api.PagingQueryMiddleware, h.PagedListResults)
+			rr.GET("/:result_partition_id", resultsPaging, h.PagedListResults)
 			// 手动结束任务
-			rr.DELETE("/:result_partition_id/:result_id", perm.MustRole(_conf.Const.AdminRole), h.KillTask)
+			rr.DELETE("/:result_partition_id/:result_id", perm.MustRole(_conf.Const.AdminRole), mustMfa, h.KillTask)
 
 			// 按任务唯一ID查询结果
 			rr.GET("/task_unique_id/:task_unique_id", h.GetResultByTaskUniqueId)
+			// 按任务唯一ID列出任务结果的状态变更记录
+			rr.GET("/task_unique_id/:task_unique_id/transitions", h.ListResultTransitions)
 			// 按任务唯一ID手动结束任务
 			rr.DELETE(
 				"/task_unique_id/:task_unique_id",
 				perm.MustRole(_conf.Const.AdminRole),
+				mustMfa,
 				h.KillTaskByTaskUniqueId,
 			)
 		}
@@ -160,6 +241,63 @@ func newGinEngine(ginMode string, _conf *conf.Conf, logger *logger.Logger, h *ha
 		// Log模块
 		// 按分区ID列出所有结果日志
 		g.GET("/logs/:result_partition_id/:result_id", h.ListLogs)
+
+		// 归属模块
+		// 任务和计划任务可归属于产品线、组或角色，其成员无需管理员角色即可调用、结束并查看归属任务的结果
+		// 产品线下的路由同样接受授予该产品线范围的权限
+		ownerScopes := map[string]func(p string) gin.HandlerFunc{
+			"/products/:product_id": func(p string) gin.HandlerFunc {
+				return perm.MustCurrUserInProductOrPermOrRole("product_id", p, _conf.Const.AdminRole)
+			},
+			"/groups/:group_id": func(string) gin.HandlerFunc {
+				return perm.MustCurrUserInGroupOrRole("group_id", _conf.Const.AdminRole, false)
+			},
+			"/roles/:role_name": func(string) gin.HandlerFunc {
+				return perm.MustParamRoleOrRole("role_name", _conf.Const.AdminRole)
+			},
+		}
+		for prefix, mustOwner := range ownerScopes {
+			taskRead := mustOwner(conf.PermTaskRead)
+			taskCall := mustOwner(conf.PermTaskCall)
+			scheduleRead := mustOwner(conf.PermScheduleRead)
+
+			or := g.Group(prefix)
+			{
+				// 列出归属任务
+				or.GET("/tasks", taskRead, tasksPaging, h.PagedListTasks)
+				// 获得归属任务形参
+				or.GET("/tasks/:task_id/formal_params", taskRead, h.GetTaskFormalParams)
+				// 列出实现了归属任务的活跃Worker
+				or.GET("/tasks/:task_id/workers", taskRead, h.ListTaskWorkers)
+				// 调用归属任务
+				or.POST("/tasks/:task_id/call", taskCall, mustMfa, callLimit, h.CallTask)
+				// 列出归属计划任务
+				or.GET("/schedules", scheduleRead, schedulesPaging, h.PagedListSchedules)
+
+				// 跨分区查询归属任务的结果
+				or.GET("/results/search", taskRead, h.SearchResults)
+				// 统计归属任务的结果
+				or.GET("/results/stats", taskRead, h.GetResultStats)
+				// 按分区ID列出归属任务的结果
+				or.GET("/results/:result_partition_id", taskRead, resultsPaging, h.PagedListResults)
+				// 手动结束归属任务
+				or.DELETE("/results/:result_partition_id/:result_id", taskCall, mustMfa, h.KillTask)
+				// 按任务唯一ID查询归属任务结果
+				or.GET("/results/task_unique_id/:task_unique_id", taskRead, h.GetResultByTaskUniqueId)
+				// 按任务唯一ID列出归属任务结果的状态变更记录
+				or.GET("/results/task_unique_id/:task_unique_id/transitions", taskRead, h.ListResultTransitions)
+				// 按任务唯一ID手动结束归属任务
+				or.DELETE("/results/task_unique_id/:task_unique_id", taskCall, mustMfa, h.KillTaskByTaskUniqueId)
+
+				// 按分区ID列出归属任务的结果日志
+				or.GET("/logs/:result_partition_id/:result_id", taskRead, h.ListLogs)
+			}
+		}
+
+		// 审计日志，要求管理员权限
+		g.GET("/audit_logs", perm.MustRole(_conf.Const.AdminRole), api.PagingQueryMiddleware, auditor.ListHandler)
+		// 导出审计日志，要求管理员权限
+		g.GET("/audit_logs/export", perm.MustRole(_conf.Const.AdminRole), auditor.ExportHandler)
 	}
 
 	// Log模块
@@ -0,0 +1,76 @@
+package form
+
+import (
+	"context"
+	"database/sql"
+	cMsg "eago/common/code_msg"
+	"eago/common/orm"
+	"eago/task/dao"
+	"eago/task/model"
+	"fmt"
+	"github.com/beego/beego/v2/core/validation"
+)
+
+type NewArtifactForm struct {
+	Name        string  `form:"name" valid:"Required;MaxSize(100);Match(/^[a-zA-Z][a-zA-Z0-9._-]{1,}$/)"`
+	Description *string `form:"description" valid:"MinSize(0);MaxSize(500)"`
+}
+
+func (f *NewArtifactForm) Validate() *cMsg.CodeMsg {
+	valid := validation.Validation{}
+	// 验证数据
+	ok, err := valid.Valid(f)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err)
+	}
+	// 数据验证未通过
+	if !ok {
+		return cMsg.MsgValidateFailed.SetError(valid.Errors)
+	}
+
+	if f.Description == nil {
+		desc := ""
+		f.Description = &desc
+	}
+
+	return nil
+}
+
+type GetArtifactForm struct{}
+
+func (*GetArtifactForm) Validate(ctx context.Context, dao *dao.Dao, aId uint32) (*model.Artifact, *cMsg.CodeMsg) {
+	// 验证制品是否存在
+	a, err := dao.GetArtifact(ctx, orm.Query{"id=?": aId})
+	if err != nil {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("制品不存在")
+	}
+
+	if a == nil || a.Id < 1 {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("制品不存在")
+	}
+
+	return a, nil
+}
+
+type PagedListArtifactsParamsForm struct {
+	Query *string `form:"query"`
+	Name  *string `form:"name"`
+}
+
+func (pf *PagedListArtifactsParamsForm) GenQuery() orm.Query {
+	query := orm.Query{}
+
+	// 通用Query
+	if pf.Query != nil && *pf.Query != "" {
+		likeQuery := fmt.Sprintf("%%%s%%", *pf.Query)
+		query["(name LIKE @query OR "+
+			"description LIKE @query OR "+
+			"sha256 LIKE @query)"] = sql.Named("query", likeQuery)
+	}
+
+	if pf.Name != nil {
+		query["name=?"] = *pf.Name
+	}
+
+	return query
+}
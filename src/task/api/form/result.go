@@ -2,10 +2,17 @@ package form
 
 import (
 	"database/sql"
+	cMsg "eago/common/code_msg"
+	"eago/common/global"
 	"eago/common/orm"
+	"eago/task/dto"
 	"fmt"
+	"time"
 )
 
+// resultStatsMaxWindow 结果统计的最长时间范围
+const resultStatsMaxWindow = 31 * 24 * time.Hour
+
 type ListResultsParamsForm struct {
 	Query  *string `form:"query"`
 	Status *int32  `form:"status"`
@@ -28,3 +35,172 @@ func (pf *ListResultsParamsForm) GenQuery() orm.Query {
 
 	return query
 }
+
+// SearchResultsParamsForm 跨分区查询结果参数
+type SearchResultsParamsForm struct {
+	StartFrom    *string `form:"start_from"`
+	StartTo      *string `form:"start_to"`
+	TaskCodename *string `form:"task_codename"`
+	Status       []int32 `form:"status"`
+	Caller       *string `form:"caller"`
+	Worker       *string `form:"worker"`
+	Cursor       *string `form:"cursor"`
+	PageSize     *int    `form:"page_size"`
+
+	From  *time.Time
+	To    *time.Time
+	Next  *dto.ResultSearchCursor
+	Limit int
+}
+
+// Validate 解析时间范围、游标及分页大小
+func (pf *SearchResultsParamsForm) Validate() *cMsg.CodeMsg {
+	var err error
+
+	if pf.From, err = parseTimestamp(pf.StartFrom); err != nil {
+		return cMsg.MsgValidateFailed.SetError(err, "start_from")
+	}
+	if pf.To, err = parseTimestamp(pf.StartTo); err != nil {
+		return cMsg.MsgValidateFailed.SetError(err, "start_to")
+	}
+
+	if pf.From != nil && pf.To != nil && !pf.From.Before(*pf.To) {
+		return cMsg.MsgValidateFailed.SetError(fmt.Errorf("start_from must be before start_to"))
+	}
+
+	if pf.Cursor != nil && *pf.Cursor != "" {
+		if pf.Next, err = dto.DecodeResultSearchCursor(*pf.Cursor); err != nil {
+			return cMsg.MsgValidateFailed.SetError(err, "cursor")
+		}
+	}
+
+	pf.Limit = global.DefaultPageSize
+	if pf.PageSize != nil && *pf.PageSize > 0 {
+		pf.Limit = *pf.PageSize
+	}
+	if pf.Limit > global.MaxPageSize {
+		pf.Limit = global.MaxPageSize
+	}
+
+	return nil
+}
+
+// GenQuery 生成各分区结果表通用的查询条件
+func (pf *SearchResultsParamsForm) GenQuery() orm.Query {
+	query := orm.Query{}
+
+	if pf.TaskCodename != nil && *pf.TaskCodename != "" {
+		query["task_codename=?"] = *pf.TaskCodename
+	}
+
+	if len(pf.Status) > 0 {
+		query["status IN ?"] = pf.Status
+	}
+
+	if pf.Caller != nil && *pf.Caller != "" {
+		query["caller=?"] = *pf.Caller
+	}
+
+	if pf.Worker != nil && *pf.Worker != "" {
+		query["worker=?"] = *pf.Worker
+	}
+
+	return query
+}
+
+// ResultStatsParamsForm 结果统计参数
+type ResultStatsParamsForm struct {
+	StartFrom    *string `form:"start_from"`
+	StartTo      *string `form:"start_to"`
+	TaskCodename *string `form:"task_codename"`
+	Caller       *string `form:"caller"`
+	Worker       *string `form:"worker"`
+	GroupBy      string  `form:"group_by"`
+	Bucket       string  `form:"bucket"`
+
+	From time.Time
+	To   time.Time
+}
+
+// Validate 解析时间范围，默认统计最近一天，最长统计31天
+func (pf *ResultStatsParamsForm) Validate() *cMsg.CodeMsg {
+	from, err := parseTimestamp(pf.StartFrom)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err, "start_from")
+	}
+	to, err := parseTimestamp(pf.StartTo)
+	if err != nil {
+		return cMsg.MsgValidateFailed.SetError(err, "start_to")
+	}
+
+	pf.To = time.Now()
+	if to != nil {
+		pf.To = *to
+	}
+	pf.From = pf.To.Add(-24 * time.Hour)
+	if from != nil {
+		pf.From = *from
+	}
+
+	if !pf.From.Before(pf.To) {
+		return cMsg.MsgValidateFailed.SetError(fmt.Errorf("start_from must be before start_to"))
+	}
+	if pf.To.Sub(pf.From) > resultStatsMaxWindow {
+		return cMsg.MsgValidateFailed.SetError(fmt.Errorf("time window must not exceed %s", resultStatsMaxWindow))
+	}
+
+	switch pf.GroupBy {
+	case "":
+		pf.GroupBy = dto.ResultStatsGroupByTaskCodename
+	case dto.ResultStatsGroupByTaskCodename, dto.ResultStatsGroupByStatus,
+		dto.ResultStatsGroupByCaller, dto.ResultStatsGroupByWorker:
+	default:
+		return cMsg.MsgValidateFailed.SetError(fmt.Errorf("unsupported group_by %q", pf.GroupBy), "group_by")
+	}
+
+	switch pf.Bucket {
+	case "":
+		// 超过两天的范围按天分桶，否则按小时分桶
+		pf.Bucket = dto.ResultStatsBucketHour
+		if pf.To.Sub(pf.From) > 48*time.Hour {
+			pf.Bucket = dto.ResultStatsBucketDay
+		}
+	case dto.ResultStatsBucketHour, dto.ResultStatsBucketDay:
+	default:
+		return cMsg.MsgValidateFailed.SetError(fmt.Errorf("unsupported bucket %q", pf.Bucket), "bucket")
+	}
+
+	return nil
+}
+
+// GenQuery 生成各分区结果表通用的查询条件
+func (pf *ResultStatsParamsForm) GenQuery() orm.Query {
+	query := orm.Query{}
+
+	if pf.TaskCodename != nil && *pf.TaskCodename != "" {
+		query["task_codename=?"] = *pf.TaskCodename
+	}
+
+	if pf.Caller != nil && *pf.Caller != "" {
+		query["caller=?"] = *pf.Caller
+	}
+
+	if pf.Worker != nil && *pf.Worker != "" {
+		query["worker=?"] = *pf.Worker
+	}
+
+	return query
+}
+
+// parseTimestamp 按本地时区解析时间，为空时返回nil
+func parseTimestamp(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+
+	t, err := time.ParseInLocation(global.TimestampFormat, *s, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -37,7 +37,7 @@ func (pf *ListResultPartitionsParamsForm) GenQuery() orm.Query {
 	// 通用Query
 	if pf.Query != nil && *pf.Query != "" {
 		likeQuery := fmt.Sprintf("%%%s%%", *pf.Query)
-		query["partition LIKE @query"] = sql.Named("query", likeQuery)
+		query["`partition` LIKE @query"] = sql.Named("query", likeQuery)
 	}
 
 	return query
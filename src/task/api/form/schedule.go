@@ -17,6 +17,9 @@ type NewScheduleForm struct {
 	Arguments    string  `json:"arguments" valid:"Required;MinSize(2)"`
 	Disabled     *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
 	Description  *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	ProductId    uint32  `json:"product_id"`
+	GroupId      uint32  `json:"group_id"`
+	RoleName     string  `json:"role_name" valid:"MaxSize(100)"`
 }
 
 func (f *NewScheduleForm) Validate() *cMsg.CodeMsg {
@@ -52,6 +55,9 @@ type SetScheduleForm struct {
 	Arguments    string  `json:"arguments" valid:"Required;MinSize(2)"`
 	Disabled     *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
 	Description  *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	ProductId    uint32  `json:"product_id"`
+	GroupId      uint32  `json:"group_id"`
+	RoleName     string  `json:"role_name" valid:"MaxSize(100)"`
 }
 
 func (f *SetScheduleForm) Validate(ctx context.Context, dao *dao.Dao, schId uint32) *cMsg.CodeMsg {
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	cMsg "eago/common/code_msg"
+	"eago/common/json_schema"
 	"eago/common/orm"
+	"eago/common/utils"
 	"eago/task/conf/msg"
 	"eago/task/dao"
+	"eago/task/dto"
+	"eago/task/model"
 	"fmt"
 	"github.com/beego/beego/v2/core/validation"
 )
@@ -18,7 +22,7 @@ type CallTask struct {
 	Arguments string `json:"arguments" valid:"Required;MinSize(2)"`
 }
 
-func (ct *CallTask) Validate(ctx context.Context, dao *dao.Dao, tId uint32) *cMsg.CodeMsg {
+func (ct *CallTask) Validate(ctx context.Context, dao *dao.Dao, tId uint32, owner orm.Query) *cMsg.CodeMsg {
 	valid := validation.Validation{}
 	// 验证数据
 	ok, err := valid.Valid(ct)
@@ -31,7 +35,8 @@ func (ct *CallTask) Validate(ctx context.Context, dao *dao.Dao, tId uint32) *cMs
 	}
 
 	// 验证任务是否存在
-	taskObj, err := dao.GetTask(ctx, orm.Query{"id=?": tId, "disabled=?": false})
+	query := utils.MergeMapStringInterface(owner, orm.Query{"id=?": tId, "disabled=?": false})
+	taskObj, err := dao.GetTask(ctx, query)
 	if err != nil {
 		return cMsg.MsgNotFoundFailed.SetDetail("任务不存在")
 	}
@@ -43,6 +48,21 @@ func (ct *CallTask) Validate(ctx context.Context, dao *dao.Dao, tId uint32) *cMs
 
 	ct.TaskCodeName = taskObj.Codename
 
+	// 按任务形参校验实参并填充默认值
+	schema, _, err := json_schema.CompileFormalParams(taskObj.FormalParams)
+	if err != nil {
+		return msg.MsgInvalidFormalParamsFailed.SetError(err)
+	}
+	args, err := schema.Validate([]byte(ct.Arguments))
+	if err != nil {
+		// 以字段为Key返回校验错误
+		if fe, ok := json_schema.IsFieldErrors(err); ok {
+			return msg.MsgCallTaskArgumentsFailed.SetError(map[string]string(fe))
+		}
+		return msg.MsgCallTaskArgumentsFailed.SetError(err)
+	}
+	ct.Arguments = string(args)
+
 	return nil
 }
 
@@ -50,14 +70,37 @@ type NewTaskForm struct {
 	Category     *int32  `json:"category" valid:"Min(0)"`
 	Codename     string  `json:"codename" valid:"Required;MaxSize(100);Match(/^[a-zA-Z][a-zA-Z0-9._]{1,}$/)"`
 	FormalParams string  `json:"formal_params" valid:"Required;MinSize(2)"`
-	Disabled     *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
-	Description  *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	Script       *string `json:"script" valid:"MaxSize(1048576)"`
+	// 脚本的环境变量，JSON对象格式
+	ScriptEnv *string `json:"script_env" valid:"MaxSize(65535)"`
+	// 任务引用的制品及版本
+	ArtifactName    string  `json:"artifact_name" valid:"MaxSize(100)"`
+	ArtifactVersion uint32  `json:"artifact_version"`
+	Disabled        *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
+	Description     *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	ProductId       uint32  `json:"product_id"`
+	GroupId         uint32  `json:"group_id"`
+	RoleName        string  `json:"role_name" valid:"MaxSize(100)"`
 
 	dao *dao.Dao
 	ctx context.Context
 }
 
 func (f *NewTaskForm) Valid(v *validation.Validation) {
+	if f.ArtifactName != "" {
+		q := orm.Query{"name=?": f.ArtifactName, "version=?": f.ArtifactVersion}
+		if exist, _ := f.dao.IsArtifactExist(f.ctx, q); !exist {
+			_ = v.SetError("ArtifactVersion", "引用的制品版本不存在")
+		}
+	}
+	if _, _, err := json_schema.CompileFormalParams(f.FormalParams); err != nil {
+		_ = v.SetError("FormalParams", msg.MsgInvalidFormalParamsFailed.GetMsg()+": "+err.Error())
+	}
+	if f.ScriptEnv != nil {
+		if _, err := dto.ParseScriptEnv(*f.ScriptEnv); err != nil {
+			_ = v.SetError("ScriptEnv", err.Error())
+		}
+	}
 	if exist, _ := f.dao.IsTaskExist(f.ctx, orm.Query{"codename=?": f.Codename}); exist {
 		_ = v.SetError("Codename", "已有相同代号的任务存在")
 	}
@@ -106,8 +149,17 @@ type SetTaskForm struct {
 	Category     *int32  `json:"category" valid:"Min(0)"`
 	Codename     string  `json:"codename" valid:"Required;MaxSize(100);Match(/^[a-zA-Z][a-zA-Z0-9._]{1,}$/)"`
 	FormalParams string  `json:"formal_params" valid:"Required;MinSize(2)"`
-	Disabled     *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
-	Description  *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	Script       *string `json:"script" valid:"MaxSize(1048576)"`
+	// 脚本的环境变量，JSON对象格式
+	ScriptEnv *string `json:"script_env" valid:"MaxSize(65535)"`
+	// 任务引用的制品及版本
+	ArtifactName    string  `json:"artifact_name" valid:"MaxSize(100)"`
+	ArtifactVersion uint32  `json:"artifact_version"`
+	Disabled        *bool   `json:"disabled" gorm:"default:0" valid:"Required"`
+	Description     *string `json:"description" valid:"MinSize(0);MaxSize(500)"`
+	ProductId       uint32  `json:"product_id"`
+	GroupId         uint32  `json:"group_id"`
+	RoleName        string  `json:"role_name" valid:"MaxSize(100)"`
 
 	taskId uint32
 
@@ -116,6 +168,20 @@ type SetTaskForm struct {
 }
 
 func (f *SetTaskForm) Valid(v *validation.Validation) {
+	if f.ArtifactName != "" {
+		q := orm.Query{"name=?": f.ArtifactName, "version=?": f.ArtifactVersion}
+		if exist, _ := f.dao.IsArtifactExist(f.ctx, q); !exist {
+			_ = v.SetError("ArtifactVersion", "引用的制品版本不存在")
+		}
+	}
+	if _, _, err := json_schema.CompileFormalParams(f.FormalParams); err != nil {
+		_ = v.SetError("FormalParams", msg.MsgInvalidFormalParamsFailed.GetMsg()+": "+err.Error())
+	}
+	if f.ScriptEnv != nil {
+		if _, err := dto.ParseScriptEnv(*f.ScriptEnv); err != nil {
+			_ = v.SetError("ScriptEnv", err.Error())
+		}
+	}
 	if exist, _ := f.dao.IsTaskExist(f.ctx, orm.Query{"codename=?": f.Codename, "id<>?": f.taskId}); exist {
 		_ = v.SetError("Codename", "已有相同代号的任务存在")
 	}
@@ -146,9 +212,51 @@ func (f *SetTaskForm) Validate(ctx context.Context, dao *dao.Dao, taskId uint32)
 	return nil
 }
 
+type GetTaskFormalParamsForm struct{}
+
+func (*GetTaskFormalParamsForm) Validate(
+	ctx context.Context, dao *dao.Dao, tId uint32, owner orm.Query,
+) (*json_schema.Schema, *cMsg.CodeMsg) {
+	// 验证任务是否存在
+	taskObj, err := dao.GetTask(ctx, utils.MergeMapStringInterface(owner, orm.Query{"id=?": tId}))
+	if err != nil {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("任务不存在")
+	}
+
+	if taskObj == nil || taskObj.Id < 1 {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("任务不存在")
+	}
+
+	schema, _, err := json_schema.CompileFormalParams(taskObj.FormalParams)
+	if err != nil {
+		return nil, msg.MsgInvalidFormalParamsFailed.SetError(err)
+	}
+
+	return schema, nil
+}
+
+type ListTaskWorkersForm struct{}
+
+func (*ListTaskWorkersForm) Validate(
+	ctx context.Context, dao *dao.Dao, tId uint32, owner orm.Query,
+) (*model.Task, *cMsg.CodeMsg) {
+	// 验证任务是否存在
+	taskObj, err := dao.GetTask(ctx, utils.MergeMapStringInterface(owner, orm.Query{"id=?": tId}))
+	if err != nil {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("任务不存在")
+	}
+
+	if taskObj == nil || taskObj.Id < 1 {
+		return nil, cMsg.MsgNotFoundFailed.SetDetail("任务不存在")
+	}
+
+	return taskObj, nil
+}
+
 type PagedListTasksParamsForm struct {
 	Query    *string `form:"query"`
 	Disabled *bool   `form:"disabled"`
+	Orphaned *bool   `form:"orphaned"`
 }
 
 func (pf *PagedListTasksParamsForm) GenQuery() orm.Query {
@@ -166,5 +274,9 @@ func (pf *PagedListTasksParamsForm) GenQuery() orm.Query {
 		query["disabled=?"] = *pf.Disabled
 	}
 
+	if pf.Orphaned != nil {
+		query["orphaned=?"] = *pf.Orphaned
+	}
+
 	return query
 }
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"eago/common/api/ext"
+	perm "eago/common/api/permission"
+	cMsg "eago/common/code_msg"
+	"eago/common/global"
+	"eago/common/tracer"
+	"eago/common/utils"
+	"eago/task/api/form"
+	"eago/task/conf/msg"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// NewArtifact 上传制品，同名制品自动生成新版本
+func (th *TaskHandler) NewArtifact(c *gin.Context) {
+	frm := form.NewArtifactForm{}
+
+	// 序列化request body
+	if err := c.ShouldBind(&frm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	// 验证数据
+	if m := frm.Validate(); m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	// 读取上传文件
+	fh, err := c.FormFile("file")
+	if err != nil {
+		m := msg.MsgArtifactReadFileFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if fh.Size > th.conf.ArtifactMaxSizeMb*1024*1024 {
+		m := msg.MsgArtifactTooLargeFailed.SetDetail(strconv.FormatInt(th.conf.ArtifactMaxSizeMb, 10))
+		th.logger.WarnWithFields(m.ToLoggerFields().Append("size", fh.Size), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		m := msg.MsgArtifactReadFileFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		m := msg.MsgArtifactReadFileFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	// 新建
+	a, err := th.dao.NewArtifact(
+		tracer.ExtractTraceCtxFromGin(c),
+		frm.Name,
+		*frm.Description,
+		content,
+		perm.MustGetTokenContent(c).Username,
+	)
+	// 新建失败
+	if err != nil {
+		m := msg.MsgArtifactStorageErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "artifact", a)
+}
+
+// GetArtifact 获得制品信息
+func (th *TaskHandler) GetArtifact(c *gin.Context) {
+	aId, err := ext.ParamUint32(c, "artifact_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "artifact_id")
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := form.GetArtifactForm{}
+	a, m := frm.Validate(tracer.ExtractTraceCtxFromGin(c), th.dao, aId)
+	if m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "artifact", a)
+}
+
+// DownloadArtifact 下载制品
+func (th *TaskHandler) DownloadArtifact(c *gin.Context) {
+	aId, err := ext.ParamUint32(c, "artifact_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "artifact_id")
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	frm := form.GetArtifactForm{}
+	a, m := frm.Validate(ctx, th.dao, aId)
+	if m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	content, err := th.dao.GetArtifactContent(ctx, a)
+	if err != nil {
+		m := msg.MsgArtifactStorageErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields().Append("sha256", a.Sha256), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.Name))
+	c.Header("ETag", a.Sha256)
+	c.Data(http.StatusOK, "application/octet-stream", content)
+}
+
+// ChecksumArtifact 重新计算制品内容的sha256，校验存储的制品是否完整
+func (th *TaskHandler) ChecksumArtifact(c *gin.Context) {
+	aId, err := ext.ParamUint32(c, "artifact_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "artifact_id")
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	frm := form.GetArtifactForm{}
+	a, m := frm.Validate(ctx, th.dao, aId)
+	if m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	content, err := th.dao.GetArtifactContent(ctx, a)
+	if err != nil {
+		m := msg.MsgArtifactStorageErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields().Append("sha256", a.Sha256), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	sha := utils.GenSha256HashCode(string(content))
+	ext.WriteSuccessPayload(c, "checksum", gin.H{
+		"sha256":        a.Sha256,
+		"actual_sha256": sha,
+		"valid":         sha == a.Sha256,
+	})
+}
+
+// PagedListArtifacts 列出所有制品-分页
+func (th *TaskHandler) PagedListArtifacts(c *gin.Context) {
+	pFrm := form.PagedListArtifactsParamsForm{}
+	if err := c.ShouldBindQuery(&pFrm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	paged, err := th.dao.PagedListArtifacts(
+		tracer.ExtractTraceCtxFromGin(c),
+		pFrm.GenQuery(),
+		c.GetInt(global.GinCtxPageKey),
+		c.GetInt(global.GinCtxPageSizeKey),
+		c.GetStringSlice(global.GinCtxOrderByKey)...,
+	)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "artifacts", paged)
+}
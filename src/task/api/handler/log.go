@@ -44,6 +44,13 @@ func (th *TaskHandler) ListLogs(c *gin.Context) {
 		return
 	}
 
+	// 归属路由下检查任务归属
+	if m := th.checkResultOwner(tracer.ExtractTraceCtxFromGin(c), c, part, resultId); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
 	// 查询结果日志
 	logs, err := th.dao.ListLogsByPartition(tracer.ExtractTraceCtxFromGin(c), part, resultId)
 	// 查询失败
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"eago/common/api/ext"
+	cMsg "eago/common/code_msg"
+	"eago/common/orm"
+	"eago/task/conf/msg"
+	"github.com/gin-gonic/gin"
+)
+
+// ownerQuery 根据路由中的产品线、组或角色生成任务归属的查询条件，非归属路由返回空条件
+func ownerQuery(c *gin.Context) orm.Query {
+	query := orm.Query{}
+
+	if prodId, err := ext.ParamUint32(c, "product_id"); err == nil {
+		query["product_id=?"] = prodId
+	}
+	if groupId, err := ext.ParamUint32(c, "group_id"); err == nil {
+		query["group_id=?"] = groupId
+	}
+	if roleName := c.Param("role_name"); roleName != "" {
+		query["role_name=?"] = roleName
+	}
+
+	return query
+}
+
+// ownedTaskCodenamesQuery 生成限定结果只属于归属任务的查询条件，非归属路由返回空条件
+func (th *TaskHandler) ownedTaskCodenamesQuery(ctx context.Context, c *gin.Context) (orm.Query, error) {
+	query := orm.Query{}
+
+	owner := ownerQuery(c)
+	if len(owner) < 1 {
+		return query, nil
+	}
+
+	tasks, err := th.dao.ListTasks(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	codenames := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		codenames = append(codenames, t.Codename)
+	}
+	query["task_codename IN ?"] = codenames
+
+	return query, nil
+}
+
+// checkResultOwner 在归属路由下检查任务结果所属的任务是否归属于路由中的产品线、组或角色
+func (th *TaskHandler) checkResultOwner(ctx context.Context, c *gin.Context, part string, resId uint32) *cMsg.CodeMsg {
+	owner := ownerQuery(c)
+	if len(owner) < 1 {
+		return nil
+	}
+
+	resObj, err := th.dao.GetResult(ctx, part, resId)
+	if err != nil {
+		return msg.MsgTaskDaoErr.SetError(err)
+	}
+	if resObj == nil || resObj.Id < 1 {
+		return cMsg.MsgNotFoundFailed.SetDetail("任务结果不存在")
+	}
+
+	owner["codename=?"] = resObj.TaskCodename
+	exist, err := th.dao.IsTaskExist(ctx, owner)
+	if err != nil {
+		return msg.MsgTaskDaoErr.SetError(err)
+	}
+	if !exist {
+		return msg.MsgTaskNotOwnedFailed
+	}
+
+	return nil
+}
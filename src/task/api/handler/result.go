@@ -6,6 +6,7 @@ import (
 	"eago/common/global"
 	"eago/common/logger"
 	"eago/common/tracer"
+	"eago/common/utils"
 	"eago/task/api/form"
 	"eago/task/conf/msg"
 	"github.com/gin-gonic/gin"
@@ -51,6 +52,13 @@ func (th *TaskHandler) KillTask(c *gin.Context) {
 		return
 	}
 
+	// 归属路由下检查任务归属
+	if m := th.checkResultOwner(ctx, c, part, rId); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
 	// 执行手动结束任务
 	if err = th.biz.KillTask(ctx, th.biz.TaskUniqueIdEncode(part, rId)); err != nil {
 		m := msg.MsgKillTaskFailed.SetError(err)
@@ -64,8 +72,28 @@ func (th *TaskHandler) KillTask(c *gin.Context) {
 
 // KillTaskByTaskUniqueId 按任务唯一ID手动结束任务
 func (th *TaskHandler) KillTaskByTaskUniqueId(c *gin.Context) {
+	// 获得任务唯一ID
+	taskUniqueId := c.Param("task_unique_id")
+	// 解码任务唯一ID
+	part, resId, err := th.biz.TaskUniqueIdDecode(taskUniqueId)
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "task_unique_id")
+		th.logger.WarnWithFields(m.ToLoggerFields().Append("task_unique_id", taskUniqueId), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 归属路由下检查任务归属
+	if m := th.checkResultOwner(ctx, c, part, resId); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
 	// 执行手动结束任务
-	if err := th.biz.KillTask(tracer.ExtractTraceCtxFromGin(c), c.Param("task_unique_id")); err != nil {
+	if err = th.biz.KillTask(ctx, taskUniqueId); err != nil {
 		m := msg.MsgKillTaskFailed.SetError(err)
 		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
 		m.Write2GinCtx(c)
@@ -115,10 +143,19 @@ func (th *TaskHandler) PagedListResults(c *gin.Context) {
 		}, "An error occurred while c.ShouldBindQuery in TaskHandler.PagedListResults, skipped it.")
 	}
 
+	// 归属路由下只列出归属任务的结果
+	ownedQuery, err := th.ownedTaskCodenamesQuery(ctx, c)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
 	// 查询结果
 	paged, err := th.dao.PagedListResultsByPartition(
 		ctx,
-		pFrm.GenQuery(),
+		utils.MergeMapStringInterface(pFrm.GenQuery(), ownedQuery),
 		part,
 		c.GetInt(global.GinCtxPageKey),
 		c.GetInt(global.GinCtxPageSizeKey),
@@ -145,10 +182,20 @@ func (th *TaskHandler) GetResultByTaskUniqueId(c *gin.Context) {
 		m := cMsg.MsgInvalidUriFailed.SetError(err, "task_unique_id")
 		th.logger.WarnWithFields(m.ToLoggerFields().Append("task_unique_id", taskUniqueId), m.GetMsg())
 		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 归属路由下检查任务归属
+	if m := th.checkResultOwner(ctx, c, part, resId); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
 	}
 
 	// 查询结果
-	result, err := th.dao.GetResult(tracer.ExtractTraceCtxFromGin(c), part, resId)
+	result, err := th.dao.GetResult(ctx, part, resId)
 	// 查询失败
 	if err != nil {
 		m := msg.MsgTaskDaoErr.SetError(err)
@@ -159,3 +206,134 @@ func (th *TaskHandler) GetResultByTaskUniqueId(c *gin.Context) {
 
 	ext.WriteSuccessPayload(c, "result", result)
 }
+
+// ListResultTransitions 按任务唯一ID列出任务结果的状态变更记录
+func (th *TaskHandler) ListResultTransitions(c *gin.Context) {
+	// 获得任务唯一ID
+	taskUniqueId := c.Param("task_unique_id")
+	// 解码任务唯一ID
+	part, resId, err := th.biz.TaskUniqueIdDecode(taskUniqueId)
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "task_unique_id")
+		th.logger.WarnWithFields(m.ToLoggerFields().Append("task_unique_id", taskUniqueId), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 归属路由下检查任务归属
+	if m := th.checkResultOwner(ctx, c, part, resId); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	// 查询状态变更记录
+	trans, err := th.dao.ListResultTransitions(ctx, part, resId)
+	// 查询失败
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "transitions", trans)
+}
+
+// SearchResults 跨分区查询结果，使用游标分页
+func (th *TaskHandler) SearchResults(c *gin.Context) {
+	pFrm := form.SearchResultsParamsForm{}
+	if err := c.ShouldBindQuery(&pFrm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if m := pFrm.Validate(); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 归属路由下只查询归属任务的结果
+	ownedQuery, err := th.ownedTaskCodenamesQuery(ctx, c)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	results, next, err := th.dao.SearchResults(
+		ctx,
+		utils.MergeMapStringInterface(pFrm.GenQuery(), ownedQuery),
+		pFrm.From,
+		pFrm.To,
+		pFrm.Next,
+		pFrm.Limit,
+	)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	nextCursor := ""
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
+	ext.WriteSuccessPayload(c, "results", gin.H{
+		"results":     results,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetResultStats 统计时间范围内的结果
+func (th *TaskHandler) GetResultStats(c *gin.Context) {
+	pFrm := form.ResultStatsParamsForm{}
+	if err := c.ShouldBindQuery(&pFrm); err != nil {
+		m := cMsg.MsgSerializeFailed.SetError(err)
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+	if m := pFrm.Validate(); m != nil {
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 归属路由下只统计归属任务的结果
+	ownedQuery, err := th.ownedTaskCodenamesQuery(ctx, c)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	stats, err := th.biz.ResultStats(
+		ctx,
+		utils.MergeMapStringInterface(pFrm.GenQuery(), ownedQuery),
+		pFrm.GroupBy,
+		pFrm.Bucket,
+		pFrm.From,
+		pFrm.To,
+	)
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "stats", stats)
+}
@@ -42,6 +42,19 @@ func (th *TaskHandler) NewResultPartitionsWithCreateTables(c *gin.Context) {
 	ext.WriteSuccessPayload(c, "result_partition", resPart)
 }
 
+// ListResultPartitionStats 列出所有结果分区的行数及大小
+func (th *TaskHandler) ListResultPartitionStats(c *gin.Context) {
+	stats, err := th.dao.ListResultPartitionStats(tracer.ExtractTraceCtxFromGin(c))
+	if err != nil {
+		m := msg.MsgTaskDaoErr.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "result_partition_stats", stats)
+}
+
 // ListResultPartitions 列出所有结果分区
 func (th *TaskHandler) ListResultPartitions(c *gin.Context) {
 	pFrm := form.ListResultPartitionsParamsForm{}
@@ -3,10 +3,13 @@ package handler
 import (
 	"eago/common/api/ext"
 	perm "eago/common/api/permission"
+	"eago/common/audit"
 	cMsg "eago/common/code_msg"
 	"eago/common/global"
 	"eago/common/logger"
+	"eago/common/orm"
 	"eago/common/tracer"
+	"eago/common/utils"
 	"eago/task/api/form"
 	"eago/task/conf/msg"
 	"github.com/gin-gonic/gin"
@@ -40,6 +43,9 @@ func (th *TaskHandler) NewSchedule(c *gin.Context) {
 		*frm.Description,
 		*frm.Timeout,
 		*frm.Disabled,
+		frm.ProductId,
+		frm.GroupId,
+		frm.RoleName,
 		perm.MustGetTokenContent(c).Username,
 	)
 	// 新建失败
@@ -111,6 +117,11 @@ func (th *TaskHandler) SetSchedule(c *gin.Context) {
 		return
 	}
 
+	// 记录修改前的内容用于审计
+	if before, err := th.dao.GetSchedule(ctx, orm.Query{"id=?": schId}); err == nil {
+		audit.SetBefore(c, before)
+	}
+
 	sch, err := th.dao.SetSchedule(
 		ctx,
 		schId,
@@ -120,6 +131,9 @@ func (th *TaskHandler) SetSchedule(c *gin.Context) {
 		*frm.Description,
 		*frm.Timeout,
 		*frm.Disabled,
+		frm.ProductId,
+		frm.GroupId,
+		frm.RoleName,
 		perm.MustGetTokenContent(c).Username,
 	)
 	if err != nil {
@@ -129,6 +143,7 @@ func (th *TaskHandler) SetSchedule(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, sch)
 	ext.WriteSuccessPayload(c, "schedule", sch)
 }
 
@@ -145,7 +160,8 @@ func (th *TaskHandler) PagedListSchedules(c *gin.Context) {
 
 	paged, err := th.dao.PagedListSchedules(
 		tracer.ExtractTraceCtxFromGin(c),
-		pFrm.GenQuery(),
+		// 归属路由下只列出归属于对应产品线、组或角色的计划任务
+		utils.MergeMapStringInterface(pFrm.GenQuery(), ownerQuery(c)),
 		c.GetInt(global.GinCtxPageKey),
 		c.GetInt(global.GinCtxPageSizeKey),
 		c.GetStringSlice(global.GinCtxOrderByKey)...,
@@ -6,6 +6,7 @@ import (
 	cMsg "eago/common/code_msg"
 	"eago/common/global"
 	"eago/common/tracer"
+	"eago/common/utils"
 	"eago/task/api/form"
 	"eago/task/conf/msg"
 	taskpb "eago/task/proto"
@@ -33,7 +34,7 @@ func (th *TaskHandler) CallTask(c *gin.Context) {
 		return
 	}
 	// 验证数据
-	if m := frm.Validate(ctx, th.dao, taskId); m != nil {
+	if m := frm.Validate(ctx, th.dao, taskId, ownerQuery(c)); m != nil {
 		// 数据验证未通过
 		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
 		m.Write2GinCtx(c)
@@ -50,7 +51,11 @@ func (th *TaskHandler) CallTask(c *gin.Context) {
 	rsp, err := th.taskCli.CallTask(ctx, &req)
 	// 调用失败
 	if err != nil {
-		m := msg.MsgCallTaskFailed.SetError(err)
+		// 优先返回任务服务给出的错误，如参数校验未通过
+		m, ok := cMsg.TransMicroErr2CodeMsg(err)
+		if !ok || m == nil {
+			m = msg.MsgCallTaskFailed.SetError(err)
+		}
 		th.logger.ErrorWithFields(m.ToLoggerFields(), m.GetMsg())
 		m.Write2GinCtx(c)
 		return
@@ -59,6 +64,52 @@ func (th *TaskHandler) CallTask(c *gin.Context) {
 	ext.WriteSuccessPayload(c, "task_unique_id", rsp.TaskUniqueId)
 }
 
+// GetTaskFormalParams 获得任务形参(JSON Schema)，用于渲染调用任务的参数表单
+func (th *TaskHandler) GetTaskFormalParams(c *gin.Context) {
+	taskId, err := ext.ParamUint32(c, "task_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "task_id")
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	frm := form.GetTaskFormalParamsForm{}
+	schema, m := frm.Validate(tracer.ExtractTraceCtxFromGin(c), th.dao, taskId, ownerQuery(c))
+	if m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "formal_params", schema)
+}
+
+// ListTaskWorkers 列出实现了任务的活跃Worker及其版本
+func (th *TaskHandler) ListTaskWorkers(c *gin.Context) {
+	taskId, err := ext.ParamUint32(c, "task_id")
+	if err != nil {
+		m := cMsg.MsgInvalidUriFailed.SetError(err, "task_id")
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	frm := form.ListTaskWorkersForm{}
+	taskObj, m := frm.Validate(ctx, th.dao, taskId, ownerQuery(c))
+	if m != nil {
+		// 数据验证未通过
+		th.logger.WarnWithFields(m.ToLoggerFields(), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "workers", th.biz.ListTaskWorkers(ctx, taskObj))
+}
+
 // NewTask 新建任务
 func (th *TaskHandler) NewTask(c *gin.Context) {
 	frm := form.NewTaskForm{}
@@ -88,6 +139,13 @@ func (th *TaskHandler) NewTask(c *gin.Context) {
 		frm.Codename,
 		*frm.Description,
 		frm.FormalParams,
+		frm.Script,
+		frm.ScriptEnv,
+		frm.ArtifactName,
+		frm.ArtifactVersion,
+		frm.ProductId,
+		frm.GroupId,
+		frm.RoleName,
 		perm.MustGetTokenContent(c).Username,
 	)
 	// 新建失败
@@ -169,6 +227,13 @@ func (th *TaskHandler) SetTask(c *gin.Context) {
 		frm.Codename,
 		*frm.Description,
 		frm.FormalParams,
+		frm.Script,
+		frm.ScriptEnv,
+		frm.ArtifactName,
+		frm.ArtifactVersion,
+		frm.ProductId,
+		frm.GroupId,
+		frm.RoleName,
 		perm.MustGetTokenContent(c).Username,
 	)
 	// 更新失败
@@ -194,7 +259,8 @@ func (th *TaskHandler) PagedListTasks(c *gin.Context) {
 
 	paged, err := th.dao.PagedListTasks(
 		tracer.ExtractTraceCtxFromGin(c),
-		pFrm.GenQuery(),
+		// 归属路由下只列出归属于对应产品线、组或角色的任务
+		utils.MergeMapStringInterface(pFrm.GenQuery(), ownerQuery(c)),
 		c.GetInt(global.GinCtxPageKey),
 		c.GetInt(global.GinCtxPageSizeKey),
 		c.GetStringSlice(global.GinCtxOrderByKey)...,
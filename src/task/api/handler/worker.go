@@ -1,12 +1,79 @@
 package handler
 
 import (
+	"context"
 	"eago/common/api/ext"
 	"eago/common/tracer"
+	"eago/task/conf/msg"
+	taskpb "eago/task/proto"
 	"github.com/gin-gonic/gin"
+	"github.com/micro/go-micro/v2/client"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // ListWorkers 列出所有Worker
 func (th *TaskHandler) ListWorkers(c *gin.Context) {
 	ext.WriteSuccessPayload(c, "workers", th.workerCli.List(tracer.ExtractTraceCtxFromGin(c)))
 }
+
+// GetWorker 查看Worker
+func (th *TaskHandler) GetWorker(c *gin.Context) {
+	workerId := c.Param("worker_id")
+
+	wk := th.workerCli.GetWorkerById(tracer.ExtractTraceCtxFromGin(c), workerId)
+	if wk == nil {
+		m := msg.MsgWorkerNotFoundFailed
+		th.logger.WarnWithFields(m.ToLoggerFields().Append("worker_id", workerId), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccessPayload(c, "worker", wk)
+}
+
+// CordonWorker 隔离Worker，不再向其下发新任务
+func (th *TaskHandler) CordonWorker(c *gin.Context) {
+	th.setWorkerState(c, th.taskCli.SetWorkerCordoned, true)
+}
+
+// UncordonWorker 取消隔离Worker
+func (th *TaskHandler) UncordonWorker(c *gin.Context) {
+	th.setWorkerState(c, th.taskCli.SetWorkerCordoned, false)
+}
+
+// PauseWorker 暂停Worker，拒绝新任务调用，运行中的任务继续运行至结束
+func (th *TaskHandler) PauseWorker(c *gin.Context) {
+	th.setWorkerState(c, th.taskCli.SetWorkerPaused, true)
+}
+
+// ResumeWorker 恢复Worker
+func (th *TaskHandler) ResumeWorker(c *gin.Context) {
+	th.setWorkerState(c, th.taskCli.SetWorkerPaused, false)
+}
+
+// setWorkerState 通过Task服务设置Worker状态
+func (th *TaskHandler) setWorkerState(
+	c *gin.Context,
+	set func(ctx context.Context, in *taskpb.SetWorkerStateReq, opts ...client.CallOption) (*emptypb.Empty, error),
+	value bool,
+) {
+	workerId := c.Param("worker_id")
+	ctx := tracer.ExtractTraceCtxFromGin(c)
+
+	// 找不到Worker直接返回
+	if th.workerCli.GetWorkerById(ctx, workerId) == nil {
+		m := msg.MsgWorkerNotFoundFailed
+		th.logger.WarnWithFields(m.ToLoggerFields().Append("worker_id", workerId), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	if _, err := set(ctx, &taskpb.SetWorkerStateReq{WorkerId: workerId, Value: value}); err != nil {
+		m := msg.MsgSetWorkerStateFailed.SetError(err)
+		th.logger.ErrorWithFields(m.ToLoggerFields().Append("worker_id", workerId), m.GetMsg())
+		m.Write2GinCtx(c)
+		return
+	}
+
+	ext.WriteSuccess(c)
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"eago/common/audit"
 	"eago/common/logger"
 	"eago/common/orm"
+	"eago/common/redis"
 	"eago/common/service"
 	"eago/task/conf"
 	"eago/task/dao"
@@ -16,14 +18,16 @@ import (
 var (
 	task service.EagoSrv
 
-	taskDao *dao.Dao
+	taskDao     *dao.Dao
+	taskAuditor audit.Auditor
+	taskRedis   *redis.RedisTool
 
 	taskConf *conf.Conf
 	taskLg   *logger.Logger
 )
 
 func main() {
-	task = NewTaskApi(taskDao, taskConf, taskLg)
+	task = NewTaskApi(taskDao, taskAuditor, taskRedis, taskConf, taskLg)
 
 	e := make(chan error)
 	go func() {
@@ -82,7 +86,7 @@ func init() {
 	}
 	taskLg = lg
 
-	taskDao = dao.NewDao(orm.NewMysqlGorm(
+	db := orm.NewMysqlGorm(
 		taskConf.MysqlAddress,
 		taskConf.MysqlUser,
 		taskConf.MysqlPassword,
@@ -90,5 +94,19 @@ func init() {
 		orm.MysqlMaxIdleConns(taskConf.MysqlMaxIdleConns),
 		orm.MysqlMaxOpenConns(taskConf.MysqlMaxOpenConns),
 		orm.UsingOpentracingPlugin(),
-	), taskConf, taskLg)
+		orm.UsingDbStatsMetrics("mysql"),
+	)
+	taskDao = dao.NewDao(db, taskConf, taskLg)
+	// 审计日志与业务数据使用同一数据库
+	taskAuditor = audit.NewAuditor(db, taskConf.Const.ServiceName, audit.Logger(taskLg))
+
+	// 用于接口限流
+	taskRedis = redis.NewRedisTool(
+		taskConf.RedisAddress,
+		taskConf.RedisPassword,
+		taskConf.Const.ServiceName,
+		taskConf.RedisDb,
+		redis.UsingOpentracingHook(),
+		redis.UsingPoolStatsMetrics("redis"),
+	)
 }
@@ -6,6 +6,8 @@ import (
 	"eago/task/conf"
 	"eago/task/dao"
 	workerCli "eago/task/worker/client"
+	"fmt"
+	"github.com/satori/go.uuid"
 )
 
 type Biz struct {
@@ -14,6 +16,9 @@ type Biz struct {
 
 	workerCli *workerCli.WorkerClient
 
+	// instanceId 当前实例的唯一Id，用于多实例选主
+	instanceId string
+
 	conf   *conf.Conf
 	logger *logger.Logger
 }
@@ -25,6 +30,8 @@ func NewBiz(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *logge
 
 		workerCli: workerCli.NewWorkerCli(conf.EtcdAddresses, logger),
 
+		instanceId: fmt.Sprintf("%s-%s", conf.Const.ServiceName, uuid.NewV4().String()),
+
 		conf:   conf,
 		logger: logger,
 	}
@@ -0,0 +1,107 @@
+package biz
+
+import (
+	"context"
+	"eago/common/logger"
+	"time"
+)
+
+const (
+	// leaderLease 选主锁的有效期，与任务周期无关，持有锁的实例宕机后最多经过该时长由其他实例接替
+	leaderLease = 30 * time.Second
+	// leaderHeartbeat 续期选主锁的间隔，有效期内可重试多次
+	leaderHeartbeat = leaderLease / 3
+)
+
+// runAsLeader 多个实例中只有持有锁的实例周期性执行fn，直到ctx结束
+// 锁按leaderHeartbeat续期，与执行fn的周期分开，fn执行时间较长时也不会丢失锁
+// 续期失败或锁已被其他实例持有时取消正在执行的fn，避免多个实例同时执行
+func (b *Biz) runAsLeader(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	key := genLeaderKey(name)
+
+	heartbeat := time.NewTicker(leaderHeartbeat)
+	defer heartbeat.Stop()
+
+	var (
+		cancelLeader context.CancelFunc
+		leaderDone   chan struct{}
+	)
+	// stepDown 取消并等待fn执行结束
+	stepDown := func() {
+		if cancelLeader == nil {
+			return
+		}
+		cancelLeader()
+		<-leaderDone
+		cancelLeader, leaderDone = nil, nil
+	}
+	defer func() {
+		stepDown()
+		// ctx已结束，使用新的Context释放锁
+		if err := b.redis.Unlock(context.Background(), key, b.instanceId); err != nil {
+			b.logger.WarnWithFields(logger.Fields{
+				"leader": name,
+				"error":  err,
+			}, "An error occurred while redis.Unlock in biz.runAsLeader.")
+		}
+	}()
+
+	for {
+		// 锁不存在时获得，已持有时续期
+		ok, err := b.redis.TryLock(ctx, key, b.instanceId, leaderLease)
+		if err != nil {
+			// Redis不可用时无法确认是否持有锁，视为失去锁
+			b.logger.ErrorWithFields(logger.Fields{
+				"leader": name,
+				"error":  err,
+			}, "An error occurred while redis.TryLock in biz.runAsLeader.")
+			ok = false
+		}
+		if leader := cancelLeader != nil; ok != leader {
+			if ok {
+				leaderCtx, cancel := context.WithCancel(ctx)
+				done := make(chan struct{})
+				cancelLeader, leaderDone = cancel, done
+				go func() {
+					defer close(done)
+					runPeriodically(leaderCtx, interval, fn)
+				}()
+			} else {
+				stepDown()
+			}
+			b.logger.InfoWithFields(logger.Fields{
+				"leader":      name,
+				"instance_id": b.instanceId,
+				"is_leader":   ok,
+			}, "Leader changed.")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+		}
+	}
+}
+
+// runPeriodically 立即执行fn，之后每个周期执行一次，直到ctx结束
+func runPeriodically(ctx context.Context, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// 失败仅记录日志，下个周期重试
+		_ = fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// genLeaderKey 生成选主锁的Key
+func genLeaderKey(name string) string {
+	return "leader/" + name
+}
@@ -0,0 +1,123 @@
+package biz
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/orm"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxPartitionScanDays 推算后续分区时最多向后查找的天数
+const maxPartitionScanDays = 3660
+
+// ManageResultPartitions 管理结果分区，提前创建后续分区并按保留策略处理过期分区
+func (b *Biz) ManageResultPartitions(ctx context.Context) error {
+	now := time.Now()
+
+	// 已有分区可能在升级前创建，补齐缺少的表和字段
+	resParts, err := b.dao.ListResultPartitions(ctx, orm.Query{})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListResultPartitions in biz.ManageResultPartitions.")
+		return err
+	}
+
+	// 创建当前及后续分区
+	parts := append([]string{now.Format(b.conf.Const.TaskResultPartitionTsFormat)},
+		b.UpcomingResultPartitions(now, b.conf.PartitionPreCreateCount)...)
+	ensureParts := make([]string, 0, len(resParts)+len(parts))
+	for _, rp := range resParts {
+		ensureParts = append(ensureParts, rp.Partition)
+	}
+	for _, p := range append(ensureParts, parts...) {
+		if err := b.dao.EnsureResultPartition(ctx, p); err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"partition": p,
+				"error":     err,
+			}, "An error occurred while dao.EnsureResultPartition in biz.ManageResultPartitions.")
+			return err
+		}
+	}
+
+	return b.expireResultPartitions(ctx, parts[0])
+}
+
+// UpcomingResultPartitions 按分区时间格式推算之后的n个分区
+func (b *Biz) UpcomingResultPartitions(from time.Time, n int) []string {
+	format := b.conf.Const.TaskResultPartitionTsFormat
+	curr := from.Format(format)
+
+	parts := make([]string, 0, n)
+	for i := 1; i <= maxPartitionScanDays && len(parts) < n; i++ {
+		p := from.AddDate(0, 0, i).Format(format)
+		if p != curr {
+			parts = append(parts, p)
+			curr = p
+		}
+	}
+
+	return parts
+}
+
+// expireResultPartitions 按保留策略删除或归档过期分区，当前分区之后的分区不会过期
+func (b *Biz) expireResultPartitions(ctx context.Context, currPart string) error {
+	if b.conf.PartitionRetentionCount < 1 {
+		return nil
+	}
+
+	resParts, err := b.dao.ListResultPartitions(ctx, orm.Query{"`partition`<=?": currPart})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListResultPartitions in biz.expireResultPartitions.")
+		return err
+	}
+
+	// 分区名按时间格式生成，按字符串倒序即为时间倒序
+	parts := make([]string, 0, len(resParts))
+	for _, rp := range resParts {
+		parts = append(parts, rp.Partition)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(parts)))
+
+	if len(parts) <= b.conf.PartitionRetentionCount {
+		return nil
+	}
+
+	for _, p := range parts[b.conf.PartitionRetentionCount:] {
+		switch b.conf.PartitionRetentionAction {
+		case b.conf.Const.PartitionRetentionActionDrop:
+			err = b.dao.DropResultPartition(ctx, p)
+		case b.conf.Const.PartitionRetentionActionArchive:
+			err = b.dao.ArchiveResultPartition(ctx, p)
+		default:
+			err = fmt.Errorf("unsupported partition retention action: %s", b.conf.PartitionRetentionAction)
+		}
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"partition": p,
+				"action":    b.conf.PartitionRetentionAction,
+				"error":     err,
+			}, "An error occurred while expiring result partition in biz.expireResultPartitions.")
+			return err
+		}
+
+		b.logger.InfoWithFields(logger.Fields{
+			"partition": p,
+			"action":    b.conf.PartitionRetentionAction,
+		}, "Result partition expired.")
+	}
+
+	return nil
+}
+
+// RunResultPartitionManager 周期性管理结果分区，直到ctx结束，多个实例中只有一个实例执行
+func (b *Biz) RunResultPartitionManager(ctx context.Context) {
+	b.logger.Info("Result partition manager started.")
+	defer b.logger.Info("Result partition manager end.")
+
+	b.runAsLeader(ctx, "result_partition_manager", b.conf.PartitionManageInterval, b.ManageResultPartitions)
+}
@@ -0,0 +1,140 @@
+package biz
+
+import (
+	"context"
+	"eago/common/global"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/task/dto"
+	"math"
+	"sort"
+	"time"
+)
+
+// resultStatsDurationSampleSize 估算耗时百分位数时每个分组最多读取的已结束结果数
+const resultStatsDurationSampleSize = 1000
+
+// ResultStats 统计时间范围内的结果，按groupBy分组并按bucket生成时间直方图
+// 数量及最大耗时在数据库中聚合，耗时百分位数按各分组最近的已结束结果估算
+func (b *Biz) ResultStats(
+	ctx context.Context, q orm.Query, groupBy, bucket string, from, to time.Time,
+) (*dto.ResultStats, error) {
+	groups := make(map[string]*dto.ResultStatsGroup)
+
+	// 预先生成所有时间桶，便于绘图时不缺少空桶
+	buckets := make([]*dto.ResultStatsBucket, 0)
+	bucketIdx := make(map[string]*dto.ResultStatsBucket)
+	for t := truncateToBucket(from, bucket); t.Before(to); t = nextBucket(t, bucket) {
+		bk := &dto.ResultStatsBucket{StartAt: t.Format(global.TimestampFormat)}
+		buckets = append(buckets, bk)
+		bucketIdx[bk.StartAt] = bk
+	}
+
+	counts, err := b.dao.ListResultStatCounts(ctx, q, groupBy, bucket, from, to)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"group_by": groupBy,
+			"error":    err,
+		}, "An error occurred while dao.ListResultStatCounts in biz.ResultStats.")
+		return nil, err
+	}
+	for _, c := range counts {
+		g, ok := groups[c.Key]
+		if !ok {
+			g = &dto.ResultStatsGroup{Key: c.Key, Failures: make(map[int32]int64)}
+			groups[c.Key] = g
+		}
+		bk := bucketIdx[c.Bucket]
+		if bk == nil {
+			bk = &dto.ResultStatsBucket{}
+		}
+
+		g.Total += c.Count
+		bk.Total += c.Count
+		switch {
+		case c.Status > dto.TaskResultStatusSuccessEnd:
+			g.Unfinished += c.Count
+			bk.Unfinished += c.Count
+			continue
+		case c.Status == dto.TaskResultStatusSuccessEnd:
+			g.Success += c.Count
+			bk.Success += c.Count
+		default:
+			g.Failures[c.Status] += c.Count
+			bk.Failed += c.Count
+		}
+
+		if c.DurationMax != nil && *c.DurationMax > g.DurationMax {
+			g.DurationMax = *c.DurationMax
+		}
+	}
+
+	durations, err := b.dao.ListResultDurationSamples(ctx, q, groupBy, from, to, resultStatsDurationSampleSize)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"group_by": groupBy,
+			"error":    err,
+		}, "An error occurred while dao.ListResultDurationSamples in biz.ResultStats.")
+		return nil, err
+	}
+
+	stats := &dto.ResultStats{
+		StartFrom: from.Format(global.TimestampFormat),
+		StartTo:   to.Format(global.TimestampFormat),
+		GroupBy:   groupBy,
+		Bucket:    bucket,
+		Groups:    make([]*dto.ResultStatsGroup, 0, len(groups)),
+		Histogram: buckets,
+	}
+	for key, g := range groups {
+		if finished := g.Total - g.Unfinished; finished > 0 {
+			g.SuccessRate = float64(g.Success) / float64(finished)
+		}
+
+		ds := durations[key]
+		if len(ds) > 0 {
+			sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+			p50, p95 := percentile(ds, 50), percentile(ds, 95)
+			g.DurationP50 = &p50
+			g.DurationP95 = &p95
+			g.DurationSampled = int64(len(ds))
+		}
+
+		stats.Groups = append(stats.Groups, g)
+	}
+	// 按总数倒序，总数相同时按分组名排序
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		if stats.Groups[i].Total != stats.Groups[j].Total {
+			return stats.Groups[i].Total > stats.Groups[j].Total
+		}
+		return stats.Groups[i].Key < stats.Groups[j].Key
+	})
+
+	return stats, nil
+}
+
+// percentile 最近秩法计算百分位数，sorted需已升序排列
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// truncateToBucket 将时间按本地时区截断到所在时间桶的起点
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	t = t.Local()
+	if bucket == dto.ResultStatsBucketDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+}
+
+// nextBucket 获得下一个时间桶的起点
+func nextBucket(t time.Time, bucket string) time.Time {
+	if bucket == dto.ResultStatsBucketDay {
+		return t.AddDate(0, 0, 1)
+	}
+	return t.Add(time.Hour)
+}
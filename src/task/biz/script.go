@@ -0,0 +1,100 @@
+package biz
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/task/dto"
+	"encoding/json"
+	"errors"
+)
+
+// WorkerTaskArguments 获得实际下发给Worker的任务名和参数，存储了脚本的任务交由Worker内置脚本任务执行
+func (b *Biz) WorkerTaskArguments(
+	ctx context.Context, taskCodename, taskName, arguments string,
+) (wkTaskName, wkArguments string, err error) {
+	taskObj, err := b.dao.GetTask(ctx, orm.Query{"codename=?": taskCodename})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"error":         err,
+		}, "An error occurred while dao.GetTask in biz.WorkerTaskArguments.")
+		return "", "", err
+	}
+	// 未登记的任务按原样下发
+	if taskObj == nil || taskObj.Id < 1 || taskObj.Category == nil {
+		return taskName, arguments, nil
+	}
+
+	interpreter := ""
+	switch int(*taskObj.Category) {
+	case b.conf.Const.TaskCategoryBash:
+		interpreter = dto.ScriptInterpreterBash
+	case b.conf.Const.TaskCategoryPython:
+		interpreter = dto.ScriptInterpreterPython
+	default:
+		return taskName, arguments, nil
+	}
+
+	sArgs := dto.ScriptTaskArguments{
+		Interpreter: interpreter,
+		Arguments:   json.RawMessage(arguments),
+	}
+	if len(arguments) == 0 {
+		sArgs.Arguments = json.RawMessage("{}")
+	}
+	if taskObj.Script != nil {
+		sArgs.Script = *taskObj.Script
+	}
+	if taskObj.ScriptEnv != nil {
+		sArgs.Env, err = dto.ParseScriptEnv(*taskObj.ScriptEnv)
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"task_codename": taskCodename,
+				"error":         err,
+			}, "An error occurred while dto.ParseScriptEnv in biz.WorkerTaskArguments.")
+			return "", "", err
+		}
+	}
+
+	// 引用了制品的任务，由Worker按sha256拉取制品
+	if taskObj.ArtifactName != "" {
+		a, err := b.dao.GetArtifact(ctx, orm.Query{
+			"name=?":    taskObj.ArtifactName,
+			"version=?": taskObj.ArtifactVersion,
+		})
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"task_codename":    taskCodename,
+				"artifact_name":    taskObj.ArtifactName,
+				"artifact_version": taskObj.ArtifactVersion,
+				"error":            err,
+			}, "An error occurred while dao.GetArtifact in biz.WorkerTaskArguments.")
+			return "", "", err
+		}
+		if a == nil || a.Id < 1 {
+			return "", "", errors.New("task artifact not found")
+		}
+		sArgs.Artifact = &dto.ScriptArtifact{Name: a.Name, Version: a.Version, Sha256: a.Sha256}
+	}
+
+	if sArgs.Script == "" && sArgs.Artifact == nil {
+		err = errors.New("task has neither script nor artifact")
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"error":         err,
+		}, "An error occurred while biz.WorkerTaskArguments, script task has nothing to run.")
+		return "", "", err
+	}
+
+	sArgsJson, err := json.Marshal(sArgs)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"error":         err,
+		}, "An error occurred while json.Marshal in biz.WorkerTaskArguments.")
+		return "", "", err
+	}
+
+	return dto.ScriptTaskName, string(sArgsJson), nil
+}
@@ -2,7 +2,9 @@ package biz
 
 import (
 	"context"
+	"eago/common/json_schema"
 	"eago/common/logger"
+	"eago/common/orm"
 	"eago/common/utils"
 	"eago/task/dto"
 	"errors"
@@ -11,12 +13,21 @@ import (
 	"strings"
 )
 
+// resultStatusSource 调度中心变更任务结果状态时记录的来源
+const resultStatusSource = "task.dispatcher"
+
 // CallTask 调用任务
 func (b *Biz) CallTask(
 	ctx context.Context,
 	taskCodename, arguments, caller string,
 	timeout int64,
 ) (taskUniqueId string, err error) {
+	// 按任务形参校验实参并填充默认值
+	arguments, err = b.ValidateArguments(ctx, taskCodename, arguments)
+	if err != nil {
+		return "", err
+	}
+
 	resObj, err := b.dao.NewResult(ctx, taskCodename, caller, arguments, timeout, dto.TaskResultStatusInitialization)
 	if err != nil {
 		b.logger.ErrorWithFields(logger.Fields{
@@ -49,7 +60,7 @@ func (b *Biz) CallTask(
 		b.logger.ErrorWithFields(logger.Fields{
 			"error": err,
 		}, "An error occurred while strings.Split for taskCodename.")
-		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusCallErrEnd, true)
+		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusCallErrEnd, resultStatusSource)
 		return "", err
 	}
 
@@ -58,32 +69,77 @@ func (b *Biz) CallTask(
 	wks := b.workerCli.ListByModular(ctx, modular)
 	if wks == nil || len(wks) < 1 {
 		// 找不到模块所属的worker
-		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusNoWorkerErrEnd, true)
+		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusNoWorkerErrEnd, resultStatusSource)
 		b.logger.ErrorWithFields(logger.Fields{
 			"worker": modular,
 		}, "Can not kill task, no worker found.")
 		return "", fmt.Errorf("no worker found for %s", modular)
 	}
 
+	// 获得实际下发给Worker的任务名和参数
+	wkTaskName, wkArguments, err := b.WorkerTaskArguments(ctx, taskCodename, cNameSplit[1], arguments)
+	if err != nil {
+		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusCallErrEnd, resultStatusSource)
+		return "", err
+	}
+
+	// 只下发给注册了该任务的Worker
+	regWks := make([]*dto.WorkerInfo, 0, len(wks))
+	for _, w := range wks {
+		if w.HasCodename(wkTaskName) {
+			regWks = append(regWks, w)
+		}
+	}
+	if len(regWks) < 1 {
+		_ = b.dao.SetResultStatus(
+			ctx, part, resObj.Id, dto.TaskResultStatusWorkerTaskNotFoundErrEnd, resultStatusSource,
+		)
+		b.logger.ErrorWithFields(logger.Fields{
+			"worker":        modular,
+			"task_codename": taskCodename,
+		}, "Can not call task, no worker registered the task.")
+		return "", fmt.Errorf("no worker of %s registered task %s", modular, wkTaskName)
+	}
+
+	// 跳过已隔离或已暂停的Worker
+	schWks := make([]*dto.WorkerInfo, 0, len(regWks))
+	for _, w := range regWks {
+		if w.Schedulable() {
+			schWks = append(schWks, w)
+		}
+	}
+	if len(schWks) < 1 {
+		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusNoWorkerErrEnd, resultStatusSource)
+		b.logger.ErrorWithFields(logger.Fields{
+			"worker":        modular,
+			"task_codename": taskCodename,
+		}, "Can not call task, all workers registered the task are cordoned or paused.")
+		return "", fmt.Errorf("all workers of %s registered task %s are cordoned or paused", modular, wkTaskName)
+	}
+
 	// 生成任务实例唯一ID
 	taskUniqueId = b.TaskUniqueIdEncode(part, resObj.Id)
 
 	// 随机找一个Worker
-	wk := wks[rand.Intn(len(wks))]
+	wk := schWks[rand.Intn(len(schWks))]
+
+	// 先记录执行任务的WorkerId，调用应答丢失时仍可结束任务
+	_ = b.dao.SetResultWorker(ctx, part, resObj.Id, wk.WorkerId)
 
 	// 调用Worker
-	if err = b.workerCli.CallTask(
+	ack, err := b.workerCli.CallTask(
 		b.NewSrvTokenWithCtx(ctx, wk.Address),
 		wk,
-		cNameSplit[1],
+		wkTaskName,
 		taskUniqueId,
-		arguments,
+		wkArguments,
 		caller,
 		timeout,
 		resObj.StartAt.Unix(),
-	); err != nil {
-		// 任务调用错误
-		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusCallErrEnd, true)
+	)
+	if err != nil {
+		// 调用超时时Worker可能已经接收任务，状态机只允许从初始化状态标记为调用错误，不会覆盖Worker上报的状态
+		_ = b.dao.SetResultStatus(ctx, part, resObj.Id, dto.TaskResultStatusCallErrEnd, resultStatusSource)
 		b.logger.ErrorWithFields(logger.Fields{
 			"partition": part,
 			"result_id": resObj.Id,
@@ -91,12 +147,59 @@ func (b *Biz) CallTask(
 		}, "An error occurred while workerCli.CallTask in biz.CallTask.")
 		return "", err
 	}
-	// 填充执行任务的WorkerId
-	_ = b.dao.SetResultWorker(ctx, part, resObj.Id, wk.WorkerId)
+	if ack.Duplicate {
+		b.logger.WarnWithFields(logger.Fields{
+			"task_unique_id": taskUniqueId,
+			"worker":         wk.WorkerId,
+		}, "Worker reported duplicate call task request.")
+	}
 
 	return
 }
 
+// ValidateArguments 按任务的FormalParams(JSON Schema)校验参数，返回填充默认值后的参数
+func (b *Biz) ValidateArguments(ctx context.Context, taskCodename, arguments string) (string, error) {
+	taskObj, err := b.dao.GetTask(ctx, orm.Query{"codename=?": taskCodename})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"error":         err,
+		}, "An error occurred while dao.GetTask in biz.ValidateArguments.")
+		return "", err
+	}
+	// 未登记的任务没有形参定义，不做校验
+	if taskObj == nil || taskObj.Id < 1 {
+		return arguments, nil
+	}
+
+	schema, legacy, err := json_schema.CompileFormalParams(taskObj.FormalParams)
+	if err != nil {
+		// 形参不合法时拒绝调用，避免未经校验的参数下发至Worker
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"error":         err,
+		}, "An error occurred while json_schema.CompileFormalParams in biz.ValidateArguments.")
+		return "", fmt.Errorf("%w: %s", dto.ErrInvalidFormalParams, err)
+	}
+	if legacy {
+		b.logger.DebugWithFields(logger.Fields{
+			"task_codename": taskCodename,
+		}, "Task formal params is legacy format, skip arguments validation.")
+	}
+
+	args, err := schema.Validate([]byte(arguments))
+	if err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"task_codename": taskCodename,
+			"arguments":     arguments,
+			"error":         err,
+		}, "Task arguments validation failed.")
+		return "", err
+	}
+
+	return string(args), nil
+}
+
 // KillTask 结束任务
 func (b *Biz) KillTask(ctx context.Context, taskUniqueId string) error {
 	// 将任务唯一Id解码为任务结果Id和分区
@@ -143,7 +246,7 @@ func (b *Biz) KillTask(ctx context.Context, taskUniqueId string) error {
 	wk := b.workerCli.GetWorkerById(ctx, resObj.Worker)
 	if wk == nil {
 		// 找不到任务所属的worker
-		_ = b.dao.SetResultStatus(ctx, part, resId, dto.TaskResultStatusNoWorkerErrEnd, true)
+		_ = b.dao.SetResultStatus(ctx, part, resId, dto.TaskResultStatusNoWorkerErrEnd, resultStatusSource)
 		b.logger.ErrorWithFields(logger.Fields{
 			"partition": part,
 			"result_id": resId,
@@ -0,0 +1,212 @@
+package biz
+
+import (
+	"context"
+	"eago/common/json_schema"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/task/dto"
+	"eago/task/model"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// taskCatalogCreator 自动登记任务的创建人
+	taskCatalogCreator = "task.catalog"
+	// taskCatalogDefaultFormalParams Worker未提供形参时使用的JSON Schema，不限制参数
+	taskCatalogDefaultFormalParams = "{}"
+	// taskDescriptionMaxLen 任务描述最大长度，与表结构一致
+	taskDescriptionMaxLen = 500
+)
+
+// SyncTaskCatalog 按活跃Worker上报的任务同步任务目录
+// 登记新出现的内置任务，标记没有活跃Worker的内置任务为孤立状态
+func (b *Biz) SyncTaskCatalog(ctx context.Context) error {
+	// 注册中心不可用时跳过，避免把全部任务误标记为孤立
+	wks, err := b.workerCli.ListWithError(ctx)
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while workerCli.ListWithError in biz.SyncTaskCatalog.")
+		return err
+	}
+
+	// 活跃的任务，Key为"模块名.任务名"
+	live := make(map[string]*dto.WorkerTaskMeta)
+	// 未上报任务列表的旧版本Worker所属模块，无法判断这些模块的任务是否孤立
+	legacyModulars := make(map[string]bool)
+	for _, wk := range wks {
+		if wk.Tasks == nil {
+			legacyModulars[wk.Modular] = true
+			continue
+		}
+		for _, t := range wk.Tasks {
+			if t.Codename == dto.ScriptTaskName {
+				continue
+			}
+			codename := wk.Modular + "." + t.Codename
+			if _, ok := live[codename]; !ok {
+				live[codename] = t
+			}
+		}
+	}
+
+	tasks, err := b.dao.ListTasks(ctx, orm.Query{})
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"error": err,
+		}, "An error occurred while dao.ListTasks in biz.SyncTaskCatalog.")
+		return err
+	}
+
+	exists := make(map[string]bool, len(tasks))
+	seenIds := make([]uint32, 0)
+	orphanIds := make([]uint32, 0)
+	for _, t := range tasks {
+		exists[t.Codename] = true
+		// 脚本任务由内置脚本任务执行，不参与同步
+		if t.Category == nil || int(*t.Category) != b.conf.Const.TaskCategoryBuiltin {
+			continue
+		}
+
+		if _, ok := live[t.Codename]; ok {
+			seenIds = append(seenIds, t.Id)
+			continue
+		}
+		if legacyModulars[taskModular(t.Codename)] {
+			continue
+		}
+		if t.Orphaned == nil || !*t.Orphaned {
+			orphanIds = append(orphanIds, t.Id)
+		}
+	}
+
+	// 登记新出现的任务
+	for codename, meta := range live {
+		if exists[codename] {
+			continue
+		}
+
+		t, err := b.newCatalogTask(ctx, codename, meta)
+		if err != nil {
+			// 单个任务登记失败不影响其他任务
+			continue
+		}
+		seenIds = append(seenIds, t.Id)
+	}
+
+	if err = b.dao.SetTasksSeen(ctx, seenIds, time.Now()); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_ids": seenIds,
+			"error":    err,
+		}, "An error occurred while dao.SetTasksSeen in biz.SyncTaskCatalog.")
+		return err
+	}
+	if err = b.dao.SetTasksOrphaned(ctx, orphanIds); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_ids": orphanIds,
+			"error":    err,
+		}, "An error occurred while dao.SetTasksOrphaned in biz.SyncTaskCatalog.")
+		return err
+	}
+	if len(orphanIds) > 0 {
+		b.logger.WarnWithFields(logger.Fields{
+			"task_ids": orphanIds,
+		}, "Tasks have no live worker, marked as orphaned.")
+	}
+
+	return nil
+}
+
+// newCatalogTask 按Worker上报的元信息登记任务
+func (b *Biz) newCatalogTask(ctx context.Context, codename string, meta *dto.WorkerTaskMeta) (*model.Task, error) {
+	fParams := meta.FormalParams
+	if fParams == "" {
+		fParams = taskCatalogDefaultFormalParams
+	}
+	if _, err := json_schema.Compile(fParams); err != nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"task_codename": codename,
+			"error":         err,
+		}, "Worker reported formal params is not a valid json schema, use default instead.")
+		fParams = taskCatalogDefaultFormalParams
+	}
+
+	desc := []rune(meta.Description)
+	if len(desc) > taskDescriptionMaxLen {
+		desc = desc[:taskDescriptionMaxLen]
+	}
+
+	t, err := b.dao.NewTask(
+		ctx,
+		false, int32(b.conf.Const.TaskCategoryBuiltin), codename, string(desc), fParams, nil, nil,
+		"", 0,
+		0, 0, "", taskCatalogCreator,
+	)
+	// 代号有唯一索引，任务已被其他实例或手动登记时使用已有的任务
+	if orm.IsDuplicateKeyErr(err) {
+		t, err = b.dao.GetTask(ctx, orm.Query{"codename=?": codename})
+		if err == nil && (t == nil || t.Id < 1) {
+			err = fmt.Errorf("task %s not found after duplicate key error", codename)
+		}
+		if err != nil {
+			b.logger.ErrorWithFields(logger.Fields{
+				"task_codename": codename,
+				"error":         err,
+			}, "An error occurred while dao.GetTask in biz.newCatalogTask.")
+			return nil, err
+		}
+		return t, nil
+	}
+	if err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"task_codename": codename,
+			"error":         err,
+		}, "An error occurred while dao.NewTask in biz.newCatalogTask.")
+		return nil, err
+	}
+
+	b.logger.InfoWithFields(logger.Fields{
+		"task_codename": codename,
+		"task_id":       t.Id,
+	}, "New task registered by worker added to catalog.")
+	return t, nil
+}
+
+// ListTaskWorkers 列出实现了指定任务的活跃Worker，脚本任务列出开启了内置脚本任务的Worker
+func (b *Biz) ListTaskWorkers(ctx context.Context, t *model.Task) []*dto.WorkerInfo {
+	split := strings.SplitN(t.Codename, ".", 2)
+	taskName := ""
+	if len(split) == 2 {
+		taskName = split[1]
+	}
+	if t.Category != nil {
+		switch int(*t.Category) {
+		case b.conf.Const.TaskCategoryBash, b.conf.Const.TaskCategoryPython:
+			taskName = dto.ScriptTaskName
+		}
+	}
+
+	wks := make([]*dto.WorkerInfo, 0)
+	for _, wk := range b.workerCli.ListByModular(ctx, split[0]) {
+		if wk.HasCodename(taskName) {
+			wks = append(wks, wk)
+		}
+	}
+	return wks
+}
+
+// RunTaskCatalogSync 周期性同步任务目录，直到ctx结束，多个实例中只有一个实例执行
+func (b *Biz) RunTaskCatalogSync(ctx context.Context) {
+	b.logger.Info("Task catalog sync started.")
+	defer b.logger.Info("Task catalog sync end.")
+
+	b.runAsLeader(ctx, "task_catalog_sync", b.conf.TaskCatalogSyncInterval, b.SyncTaskCatalog)
+}
+
+// taskModular 获得任务代号中的模块名
+func taskModular(codename string) string {
+	return strings.SplitN(codename, ".", 2)[0]
+}
@@ -0,0 +1,54 @@
+package biz
+
+import (
+	"context"
+	"eago/common/logger"
+	"errors"
+)
+
+// ErrWorkerNotFound 找不到Worker
+var ErrWorkerNotFound = errors.New("worker not found")
+
+// SetWorkerCordoned 隔离或取消隔离Worker，隔离后不再向其下发新任务
+func (b *Biz) SetWorkerCordoned(ctx context.Context, workerId string, cordoned bool) error {
+	wk := b.workerCli.GetWorkerById(ctx, workerId)
+	if wk == nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"worker_id": workerId,
+		}, "Can not set worker cordoned, no worker found.")
+		return ErrWorkerNotFound
+	}
+
+	if err := b.workerCli.SetCordoned(b.NewSrvTokenWithCtx(ctx, wk.Address), wk, cordoned); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"worker_id": workerId,
+			"cordoned":  cordoned,
+			"error":     err,
+		}, "An error occurred while workerCli.SetCordoned in biz.SetWorkerCordoned.")
+		return err
+	}
+
+	return nil
+}
+
+// SetWorkerPaused 暂停或恢复Worker，暂停后Worker拒绝新任务调用，运行中的任务继续运行至结束
+func (b *Biz) SetWorkerPaused(ctx context.Context, workerId string, paused bool) error {
+	wk := b.workerCli.GetWorkerById(ctx, workerId)
+	if wk == nil {
+		b.logger.WarnWithFields(logger.Fields{
+			"worker_id": workerId,
+		}, "Can not set worker paused, no worker found.")
+		return ErrWorkerNotFound
+	}
+
+	if err := b.workerCli.SetPaused(b.NewSrvTokenWithCtx(ctx, wk.Address), wk, paused); err != nil {
+		b.logger.ErrorWithFields(logger.Fields{
+			"worker_id": workerId,
+			"paused":    paused,
+			"error":     err,
+		}, "An error occurred while workerCli.SetPaused in biz.SetWorkerPaused.")
+		return err
+	}
+
+	return nil
+}
@@ -13,11 +13,16 @@ type Conf struct {
 
 	SrvListen             string
 	ApiListen             string
+	ApiMetricsListen      string
+	SrvMetricsListen      string
+	SchMetricsListen      string
 	GinMode               string
+	TrustedProxies        []string
 	MicroRegisterTtl      time.Duration
 	MicroRegisterInterval time.Duration
 	SchedulerRegisterTtl  int64
 	SrvTokenTtlSecs       time.Duration
+	SensitiveRequireMfa   bool
 
 	LogLevel string
 	LogPath  string
@@ -38,6 +43,20 @@ type Conf struct {
 	RedisDb       int
 
 	JaegerAddress string
+
+	ArtifactStorage   string
+	ArtifactLocalPath string
+	ArtifactMaxSizeMb int64
+
+	PartitionManageInterval  time.Duration
+	PartitionPreCreateCount  int
+	PartitionRetentionCount  int
+	PartitionRetentionAction string
+
+	TaskCatalogSyncInterval time.Duration
+
+	CallTaskRateLimit  int
+	CallTaskRateWindow time.Duration
 }
 
 func NewConfig(options ...Option) *Conf {
@@ -52,9 +71,13 @@ func NewConfig(options ...Option) *Conf {
 	return &Conf{
 		Const: newConstConf(),
 
-		SrvListen: cfg.MustValue("main", "srv_listen", defaultSrvListen),
-		ApiListen: cfg.MustValue("main", "api_listen", defaultApiListen),
-		GinMode:   cfg.MustValue("main", "gin_mode", defaultGinModel),
+		SrvListen:        cfg.MustValue("main", "srv_listen", defaultSrvListen),
+		ApiListen:        cfg.MustValue("main", "api_listen", defaultApiListen),
+		ApiMetricsListen: cfg.MustValue("main", "api_metrics_listen", defaultApiMetricsListen),
+		SrvMetricsListen: cfg.MustValue("main", "srv_metrics_listen", defaultSrvMetricsListen),
+		SchMetricsListen: cfg.MustValue("main", "scheduler_metrics_listen", defaultSchMetricsListen),
+		GinMode:          cfg.MustValue("main", "gin_mode", defaultGinModel),
+		TrustedProxies:   cfg.MustValueArray("main", "trusted_proxies", global.DefaultConfigSeparator),
 		MicroRegisterTtl: time.Duration(cfg.MustInt(
 			"main", "register_ttl", defaultMicroRegisterTtl,
 		)) * time.Second,
@@ -66,6 +89,8 @@ func NewConfig(options ...Option) *Conf {
 			"main", "srv_token_ttl_secs", defaultSrvTokenTtlSecs,
 		)) * time.Second,
 
+		SensitiveRequireMfa: cfg.MustBool("main", "sensitive_require_mfa", defaultSensitiveRequireMfa),
+
 		LogLevel: cfg.MustValue("log", "level", defaultLogLevel),
 		LogPath:  cfg.MustValue("log", "path", defaultLogPath),
 
@@ -85,5 +110,25 @@ func NewConfig(options ...Option) *Conf {
 		RedisDb:       cfg.MustInt("redis", "db", defaultRedisDb),
 
 		JaegerAddress: cfg.MustValue("tracer", "jaeger_address", defaultJaegerAddress),
+
+		ArtifactStorage:   cfg.MustValue("artifact", "storage", defaultArtifactStorage),
+		ArtifactLocalPath: cfg.MustValue("artifact", "local_path", defaultArtifactLocalPath),
+		ArtifactMaxSizeMb: cfg.MustInt64("artifact", "max_size_mb", defaultArtifactMaxSizeMb),
+
+		PartitionManageInterval: time.Duration(cfg.MustInt(
+			"partition", "manage_interval_secs", defaultPartitionManageIntervalSecs,
+		)) * time.Second,
+		PartitionPreCreateCount:  cfg.MustInt("partition", "pre_create_count", defaultPartitionPreCreateCount),
+		PartitionRetentionCount:  cfg.MustInt("partition", "retention_count", defaultPartitionRetentionCount),
+		PartitionRetentionAction: cfg.MustValue("partition", "retention_action", defaultPartitionRetentionAction),
+
+		TaskCatalogSyncInterval: time.Duration(cfg.MustInt(
+			"catalog", "sync_interval_secs", defaultTaskCatalogSyncIntervalSecs,
+		)) * time.Second,
+
+		CallTaskRateLimit: cfg.MustInt("ratelimit", "call_task_limit", defaultCallTaskRateLimit),
+		CallTaskRateWindow: time.Duration(cfg.MustInt(
+			"ratelimit", "call_task_window_secs", defaultCallTaskRateWindowSecs,
+		)) * time.Second,
 	}
 }
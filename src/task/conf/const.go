@@ -20,6 +20,12 @@ type constConf struct {
 	TaskUniqueIdSeparator       string
 
 	TaskLogRefreshIntervalMs time.Duration
+
+	ArtifactStorageLocal string
+	ArtifactStorageMysql string
+
+	PartitionRetentionActionDrop    string
+	PartitionRetentionActionArchive string
 }
 
 func newConstConf() *constConf {
@@ -38,5 +44,11 @@ func newConstConf() *constConf {
 		TaskUniqueIdSeparator:       "::",
 
 		TaskLogRefreshIntervalMs: 3000,
+
+		ArtifactStorageLocal: "local",
+		ArtifactStorageMysql: "mysql",
+
+		PartitionRetentionActionDrop:    "drop",
+		PartitionRetentionActionArchive: "archive",
 	}
 }
@@ -3,11 +3,15 @@ package conf
 const (
 	defaultSrvListen             = "127.0.0.1:0"
 	defaultApiListen             = "127.0.0.1:0"
+	defaultApiMetricsListen      = ""
+	defaultSrvMetricsListen      = ""
+	defaultSchMetricsListen      = ""
 	defaultGinModel              = "release"
 	defaultMicroRegisterTtl      = 10
 	defaultMicroRegisterInterval = 3
 	defaultSchedulerRegisterTtl  = 10
 	defaultSrvTokenTtlSecs       = 10
+	defaultSensitiveRequireMfa   = false
 
 	defaultLogLevel = "debug"
 	defaultLogPath  = "./logs"
@@ -31,4 +35,22 @@ const (
 
 	// Jaeger默认配置
 	defaultJaegerAddress = "127.0.0.1:5775"
+
+	// Artifact默认配置
+	defaultArtifactStorage   = "local"
+	defaultArtifactLocalPath = "./artifacts"
+	defaultArtifactMaxSizeMb = 16
+
+	// 结果分区管理默认配置
+	defaultPartitionManageIntervalSecs = 3600
+	defaultPartitionPreCreateCount     = 1
+	defaultPartitionRetentionCount     = 0
+	defaultPartitionRetentionAction    = "archive"
+
+	// 任务目录同步默认配置
+	defaultTaskCatalogSyncIntervalSecs = 60
+
+	// 接口限流默认配置
+	defaultCallTaskRateLimit      = 60
+	defaultCallTaskRateWindowSecs = 60
 )
@@ -24,12 +24,12 @@ func NewMenu(conf *Conf) *menu.Menu {
 			{
 				Uri:  "/task/schedules",
 				Name: "计划任务-菜单",
-				Perm: menu.NewPermIsRole(conf.Const.AdminRole),
+				Perm: menu.NewPermHasPermOrRole(PermScheduleRead, conf.Const.AdminRole),
 				Buttons: []*menu.Button{
 					{
 						"POST_task/schedules",
 						"计划任务-新增-按钮",
-						menu.NewPermIsRole(conf.Const.AdminRole),
+						menu.NewPermHasPermOrRole(PermScheduleWrite, conf.Const.AdminRole),
 					},
 				},
 			},
@@ -6,9 +6,12 @@ import (
 
 var (
 	// Task 1300xx
-	MsgCallTaskFailed           = cMsg.NewCodeMsg(130000, "调用任务失败，请先尝试重试，若无效请联系管理员")
-	MsgTaskUniqueIdDecodeFailed = cMsg.NewCodeMsg(130001, "将任务唯一Id解码为任务结果Id和分区时失败")
-	MsgAssociatedScheduleFailed = cMsg.NewCodeMsg(130302, "无法执行操作，仍有计划任务与该任务关联")
+	MsgCallTaskFailed            = cMsg.NewCodeMsg(130000, "调用任务失败，请先尝试重试，若无效请联系管理员")
+	MsgTaskUniqueIdDecodeFailed  = cMsg.NewCodeMsg(130001, "将任务唯一Id解码为任务结果Id和分区时失败")
+	MsgAssociatedScheduleFailed  = cMsg.NewCodeMsg(130302, "无法执行操作，仍有计划任务与该任务关联")
+	MsgCallTaskArgumentsFailed   = cMsg.NewCodeMsg(130002, "调用任务失败，任务参数校验未通过")
+	MsgInvalidFormalParamsFailed = cMsg.NewCodeMsg(130003, "任务形参不是合法的JSON Schema")
+	MsgTaskNotOwnedFailed        = cMsg.NewCodeMsg(130004, "无权限，任务不属于指定的产品线、组或角色")
 
 	// Result 1301xx
 	MsgSetResultStatusTaskEndFailed       = cMsg.NewCodeMsg(130100, "结束任务失败，任务已经结束")
@@ -16,6 +19,9 @@ var (
 	MsgKillTaskPartitionNotFoundFailed    = cMsg.NewCodeMsg(130102, "结束任务失败，没有找到对应的分区")
 	MsgKillTaskFailed                     = cMsg.NewCodeMsg(130103, "结束任务失败，请先尝试重试，若无效请联系管理员")
 	MsgListResultsPartNotFoundFailed      = cMsg.NewCodeMsg(130104, "无法列出任务结果，没有找到对应的分区")
+	MsgSetResultReturnValueInvalidFailed  = cMsg.NewCodeMsg(130105, "设置任务返回值失败，返回值不是合法的JSON")
+	MsgSetResultStatusIllegalFailed       = cMsg.NewCodeMsg(130106, "变更任务结果状态失败，不允许从当前状态变更为目标状态")
+	MsgSetResultStatusPartNotFoundFailed  = cMsg.NewCodeMsg(130107, "变更任务结果状态失败，没有找到对应的分区")
 
 	// Log 1302xx
 	MsgBizNewLogFailed            = cMsg.NewCodeMsg(130200, "新增任务日志失败")
@@ -23,6 +29,16 @@ var (
 	MsgNewLogStreamCloseFailed    = cMsg.NewCodeMsg(130202, "新增任务日志时，关闭客户端通讯流失败")
 	MsgListLogsPartNotFoundFailed = cMsg.NewCodeMsg(130203, "无法列出任务结果日志，没有找到对应的分区")
 
+	// Artifact 1304xx
+	MsgArtifactReadFileFailed = cMsg.NewCodeMsg(130400, "读取上传的制品文件失败")
+	MsgArtifactTooLargeFailed = cMsg.NewCodeMsg(130401, "上传的制品文件过大，最大允许(MB):")
+	MsgArtifactStorageErr     = cMsg.NewCodeMsg(130402, "制品存储发生意外，请先尝试重试，若无效请联系管理员")
+	MsgArtifactNotFoundFailed = cMsg.NewCodeMsg(130403, "找不到对应的制品")
+
+	// Worker 1305xx
+	MsgWorkerNotFoundFailed = cMsg.NewCodeMsg(130500, "找不到对应的Worker")
+	MsgSetWorkerStateFailed = cMsg.NewCodeMsg(130501, "设置Worker状态失败，请先尝试重试，若无效请联系管理员")
+
 	// Others
 	MsgTaskDaoErr   = cMsg.NewCodeMsg(139900, "Task服务的DAO层发生意外，请先尝试重试，若无效请联系管理员")
 	MsgTaskCacheErr = cMsg.NewCodeMsg(139901, "Task服务的Cache发生意外，请先尝试重试，若无效请联系管理员")
@@ -0,0 +1,10 @@
+package conf
+
+// 任务模块的权限，格式为service:resource:action，可在运行时授予角色
+// 授予产品线范围的权限时只对归属于该产品线的任务和计划任务生效
+const (
+	PermTaskRead      = "task:task:read"
+	PermTaskCall      = "task:task:call"
+	PermScheduleRead  = "task:schedule:read"
+	PermScheduleWrite = "task:schedule:write"
+)
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/common/utils"
+	"eago/task/model"
+	"gorm.io/gorm"
+)
+
+// newArtifactMaxRetries 新建制品时版本号冲突的最大重试次数
+const newArtifactMaxRetries = 5
+
+// NewArtifact 新建制品，同名制品的版本号自动递增
+func (d *Dao) NewArtifact(
+	ctx context.Context, name, description string, content []byte, createdBy string,
+) (*model.Artifact, error) {
+	sha := utils.GenSha256HashCode(string(content))
+
+	// 先写入内容，相同内容只存储一份
+	if err := d.PutArtifactContent(ctx, sha, content); err != nil {
+		d.lg.ErrorWithFields(logger.Fields{
+			"name":   name,
+			"sha256": sha,
+			"error":  err,
+		}, "An error occurred while dao.PutArtifactContent in dao.NewArtifact.")
+		return nil, err
+	}
+
+	a := &model.Artifact{
+		Name:        name,
+		Sha256:      sha,
+		Size:        int64(len(content)),
+		Storage:     d.conf.ArtifactStorage,
+		Description: &description,
+		CreatedBy:   createdBy,
+	}
+
+	// 同名制品并发上传时会取得相同的版本号，由唯一索引拒绝后重试
+	var err error
+	for i := 0; i < newArtifactMaxRetries; i++ {
+		a.Id = 0
+		if err = d.newArtifactVersion(ctx, a); !orm.IsDuplicateKeyErr(err) {
+			break
+		}
+		d.lg.WarnWithFields(logger.Fields{
+			"name":    name,
+			"version": a.Version,
+		}, "Artifact version conflicted, retry it.")
+	}
+
+	return a, err
+}
+
+// newArtifactVersion 以同名制品的最大版本号加一新建制品
+func (d *Dao) newArtifactVersion(ctx context.Context, a *model.Artifact) error {
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		var maxVer uint32
+		res := tx.Model(&model.Artifact{}).
+			Where("name=?", a.Name).
+			Select("IFNULL(MAX(version), 0)").
+			Scan(&maxVer)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		a.Version = maxVer + 1
+		return tx.Create(a).Error
+	})
+}
+
+// GetArtifact 查询单个制品
+func (d *Dao) GetArtifact(ctx context.Context, q orm.Query) (a *model.Artifact, err error) {
+	res := q.Where(d.getDbWithCtx(ctx)).Limit(1).Find(&a)
+	return a, res.Error
+}
+
+// GetArtifactCount 查询制品数量
+func (d *Dao) GetArtifactCount(ctx context.Context, q orm.Query) (count int64, err error) {
+	res := q.Where(d.getDbWithCtx(ctx).Model(&model.Artifact{})).Count(&count)
+	return count, res.Error
+}
+
+// IsArtifactExist 查询制品是否存在
+func (d *Dao) IsArtifactExist(ctx context.Context, q orm.Query) (bool, error) {
+	count, err := d.GetArtifactCount(ctx, q)
+	return count > 0, err
+}
+
+// PagedListArtifacts 查询制品-分页
+func (d *Dao) PagedListArtifacts(
+	ctx context.Context, q orm.Query, page, pageSize int, orderBy ...string,
+) (*orm.Paginator, error) {
+	as := make([]*model.Artifact, pageSize)
+	db := q.Where(d.getDbWithCtx(ctx).Model(&model.Artifact{}))
+	return orm.PagingQuery(db, page, pageSize, &as, orderBy...)
+}
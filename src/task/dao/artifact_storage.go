@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"eago/task/model"
+	"errors"
+	"fmt"
+	"gorm.io/gorm/clause"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// PutArtifactContent 按配置的存储方式写入制品内容
+func (d *Dao) PutArtifactContent(ctx context.Context, sha256 string, content []byte) error {
+	switch d.conf.ArtifactStorage {
+	case d.conf.Const.ArtifactStorageLocal:
+		return d.putLocalArtifactContent(sha256, content)
+	case d.conf.Const.ArtifactStorageMysql:
+		return d.putMysqlArtifactContent(ctx, sha256, content)
+	}
+
+	return fmt.Errorf("unsupported artifact storage: %s", d.conf.ArtifactStorage)
+}
+
+// GetArtifactContent 按制品写入时的存储方式读取制品内容，修改配置的存储方式后仍可读取已有制品
+func (d *Dao) GetArtifactContent(ctx context.Context, a *model.Artifact) ([]byte, error) {
+	switch a.Storage {
+	case d.conf.Const.ArtifactStorageLocal:
+		return d.getLocalArtifactContent(a.Sha256)
+	case d.conf.Const.ArtifactStorageMysql:
+		return d.getMysqlArtifactContent(ctx, a.Sha256)
+	}
+
+	return nil, fmt.Errorf("unsupported artifact storage: %s", a.Storage)
+}
+
+// getLocalArtifactPath 获得制品在本地存储中的路径，按sha256前两位分目录
+func (d *Dao) getLocalArtifactPath(sha256 string) (string, error) {
+	if len(sha256) < 3 || filepath.Base(sha256) != sha256 {
+		return "", errors.New("invalid artifact sha256")
+	}
+	return filepath.Join(d.conf.ArtifactLocalPath, sha256[:2], sha256), nil
+}
+
+func (d *Dao) putLocalArtifactContent(sha256 string, content []byte) error {
+	p, err := d.getLocalArtifactPath(sha256)
+	if err != nil {
+		return err
+	}
+	// 相同内容已存在则跳过
+	if _, err = os.Stat(p); err == nil {
+		return nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再改名，避免读到写了一半的内容
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".tmp-"+sha256)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (d *Dao) getLocalArtifactContent(sha256 string) ([]byte, error) {
+	p, err := d.getLocalArtifactPath(sha256)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(p)
+}
+
+func (d *Dao) putMysqlArtifactContent(ctx context.Context, sha256 string, content []byte) error {
+	res := d.getDbWithCtx(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ArtifactBlob{Sha256: sha256, Content: content})
+	return res.Error
+}
+
+func (d *Dao) getMysqlArtifactContent(ctx context.Context, sha256 string) ([]byte, error) {
+	blob := &model.ArtifactBlob{}
+	res := d.getDbWithCtx(ctx).Where("sha256=?", sha256).Limit(1).Find(blob)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected < 1 {
+		return nil, errors.New("artifact content not found")
+	}
+
+	return blob.Content, nil
+}
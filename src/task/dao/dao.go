@@ -5,6 +5,7 @@ import (
 	"eago/common/logger"
 	"eago/task/conf"
 	"gorm.io/gorm"
+	"sync"
 )
 
 type Dao struct {
@@ -13,6 +14,9 @@ type Dao struct {
 	conf *conf.Conf
 
 	lg *logger.Logger
+
+	// 已确认存在的结果分区
+	partitions sync.Map
 }
 
 func NewDao(d *gorm.DB, _conf *conf.Conf, lg *logger.Logger) *Dao {
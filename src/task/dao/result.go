@@ -5,9 +5,11 @@ import (
 	"eago/common/logger"
 	"eago/common/orm"
 	"eago/common/utils"
+	"eago/task/dto"
 	"eago/task/model"
-	"errors"
 	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -29,60 +31,101 @@ func (d *Dao) NewResult(
 
 	partitionName := currTime.Format(d.conf.Const.TaskResultPartitionTsFormat)
 
-	// 检测分区是否存在
-	resPartObj, err := d.GetResultPartition(ctx, orm.Query{"partition": partitionName})
-	// 获取分区错误
-	if err != nil {
+	// 确保分区存在，分区通常已由分区管理提前创建
+	if err := d.EnsureResultPartition(ctx, partitionName); err != nil {
 		d.lg.ErrorWithFields(logger.Fields{
 			"partition": partitionName,
 			"error":     err,
-		}, "An error occurred while dao.GetLogPartition in dao.NewLog.")
+		}, "An error occurred while dao.EnsureResultPartition in dao.NewResult.")
 		return nil, err
 	}
 
-	// 如果获取不到分区，则创建
-	if resPartObj == nil || resPartObj.Id < 1 {
-		// 检测创建是否成功
-		resPartObj, err = d.NewResultPartitionWithCreateTables(ctx, partitionName)
-		if err != nil {
-			d.lg.ErrorWithFields(logger.Fields{
-				"partition": partitionName,
-				"error":     err,
-			}, "An error occurred while dao.NewResultPartitionWithCreateTables in dao.NewResult.")
-			return nil, err
+	// 创建结果，并以调用者为来源记录初始状态
+	err := d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		if res := tx.Table(d.getResultTableNameByPartition(partitionName)).Create(&modelRes); res.Error != nil {
+			return res.Error
 		}
-		if resPartObj == nil {
-			return nil, errors.New("failed NewResultPartitionWithCreateTables in dao.")
-		}
-	}
-
-	// 创建结果
-	res := d.getDbWithCtx(ctx).Table(d.getResultTableNameByPartition(partitionName)).Create(&modelRes)
-	return modelRes, res.Error
+		return d.newResultTransition(tx, partitionName, modelRes.Id, nil, status, caller, currTime)
+	})
+	return modelRes, err
 }
 
-// SetResultStatus 更新任务状态
-func (d *Dao) SetResultStatus(ctx context.Context, partition string, id uint32, status int32, end bool) error {
-	updates := map[string]interface{}{"status": status}
+// unknownResultStatusSource 无法确定状态变更来源时记录的来源
+const unknownResultStatusSource = "unknown"
 
-	if end {
-		updates["end_at"] = &utils.CustomTime{Time: time.Now()}
+// SetResultStatus 按状态机更新任务状态并记录状态变更，重复设置相同状态时忽略
+// source为发起变更的来源，为空时使用任务结果记录的WorkerId
+func (d *Dao) SetResultStatus(ctx context.Context, partition string, id uint32, status int32, source string) error {
+	if err := d.checkResultPartition(ctx, partition); err != nil {
+		return err
 	}
 
+	return d.getDbWithCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定结果记录，避免并发变更互相覆盖
+		r := &model.Result{}
+		res := tx.Table(d.getResultTableNameByPartition(partition)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id=?", id).
+			Limit(1).
+			Find(r)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected < 1 {
+			return fmt.Errorf("result %s-%d not found", partition, id)
+		}
+
+		if r.Status == status {
+			return nil
+		}
+		if !dto.IsResultStatusTransitionAllowed(r.Status, status) {
+			return fmt.Errorf("%w: %d -> %d", dto.ErrIllegalResultStatusTransition, r.Status, status)
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{"status": status}
+		if dto.IsResultStatusEnd(status) {
+			updates["end_at"] = &utils.CustomTime{Time: now}
+		} else if dto.IsResultStatusEnd(r.Status) {
+			// 离开结束状态时清空结束时间，如调用超时后Worker又上报了运行中
+			updates["end_at"] = nil
+		}
+		res = tx.Table(d.getResultTableNameByPartition(partition)).Where("id=?", id).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if source == "" {
+			source = r.Worker
+		}
+		// 调度时未能记录执行Worker
+		if source == "" {
+			source = unknownResultStatusSource
+		}
+		return d.newResultTransition(tx, partition, id, &r.Status, status, source, now)
+	})
+}
+
+// SetResultWorker 更新执行器信息
+func (d *Dao) SetResultWorker(ctx context.Context, partition string, id uint32, worker string) error {
 	res := d.getDbWithCtx(ctx).
 		Table(d.getResultTableNameByPartition(partition)).
 		Where("id=?", id).
-		Updates(updates)
+		Update("worker", worker)
 
 	return res.Error
 }
 
-// SetResultWorker 更新执行器信息
-func (d *Dao) SetResultWorker(ctx context.Context, partition string, id uint32, worker string) error {
+// SetResultReturnValue 更新任务返回值
+func (d *Dao) SetResultReturnValue(ctx context.Context, partition string, id uint32, returnValue string) error {
+	if err := d.checkResultPartition(ctx, partition); err != nil {
+		return err
+	}
+
 	res := d.getDbWithCtx(ctx).
 		Table(d.getResultTableNameByPartition(partition)).
 		Where("id=?", id).
-		Update("worker", worker)
+		Update("return_value", returnValue)
 
 	return res.Error
 }
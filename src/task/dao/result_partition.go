@@ -4,8 +4,12 @@ import (
 	"context"
 	"eago/common/logger"
 	"eago/common/orm"
+	"eago/task/dto"
 	"eago/task/model"
 	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // NewResultPartition 新建结果分区
@@ -20,48 +24,226 @@ func (d *Dao) NewResultPartition(ctx context.Context, partition string) (*model.
 
 // NewResultPartitionWithCreateTables 新建结果分区并建立结果表和日志表
 func (d *Dao) NewResultPartitionWithCreateTables(ctx context.Context, partition string) (*model.ResultPartition, error) {
-	tx := d.getDbWithCtx(ctx).Begin()
-	defer tx.Rollback()
-
-	resPart := &model.ResultPartition{
-		Partition: partition,
+	if err := d.EnsureResultPartition(ctx, partition); err != nil {
+		return nil, err
 	}
 
-	// 创建记录
-	if res := tx.Create(&resPart); res.Error != nil {
-		return nil, res.Error
-	}
+	return d.GetResultPartition(ctx, orm.Query{"`partition`=?": partition})
+}
 
-	modelRes := &model.Log{}
-	// 创建Log表
-	// 判断默认名称的Log表是否存在
-	if !tx.Migrator().HasTable(modelRes) {
-		// 如果不存在，则创建默认名称的Result表
-		err := tx.Migrator().CreateTable(modelRes)
-		if err != nil {
-			d.lg.ErrorWithFields(logger.Fields{
-				"partition": partition,
-				"action":    "CreateTable",
-				"table":     "Result",
-				"error":     err,
-			}, "An error occurred while tx.Migrator.CreateTable in dao.NewResultPartitionWithCreateTables.")
-			return nil, err
-		}
+// EnsureResultPartition 确保结果分区及其结果表、日志表和状态变更记录表存在且包含模型的全部字段，可重复及并发调用
+// 升级前创建的分区缺少新增的字段或表，由分区管理补齐，分区名不能来自调用方
+func (d *Dao) EnsureResultPartition(ctx context.Context, partition string) error {
+	// 已确认存在的分区直接返回
+	if _, ok := d.partitions.Load(partition); ok {
+		return nil
 	}
 
-	// 将建默认名称的Log表改名为当前新建分区对应的表Log名
-	if err := tx.Migrator().RenameTable(modelRes.TableName(), d.getResultTableNameByPartition(partition)); err != nil {
+	db := d.getDbWithCtx(ctx)
+
+	// 建表语句在MySQL中会隐式提交事务，因此不使用事务，而是保证每一步可重复执行
+	if err := d.migrateTable(db, d.getResultTableNameByPartition(partition), &model.Result{}); err != nil {
+		d.lg.ErrorWithFields(logger.Fields{
+			"partition": partition,
+			"table":     "Result",
+			"error":     err,
+		}, "An error occurred while dao.migrateTable in dao.EnsureResultPartition.")
+		return err
+	}
+	if err := d.migrateTable(db, d.getLogTableNameByPartition(partition), &model.Log{}); err != nil {
 		d.lg.ErrorWithFields(logger.Fields{
 			"partition": partition,
-			"action":    "RenameTable",
 			"table":     "Log",
 			"error":     err,
-		}, "An error occurred while tx.Migrator.RenameTable in dao.NewResultPartitionWithCreateTables.")
+		}, "An error occurred while dao.migrateTable in dao.EnsureResultPartition.")
+		return err
+	}
+	if err := d.migrateTable(
+		db, d.getResultTransitionTableNameByPartition(partition), &model.ResultTransition{},
+	); err != nil {
+		d.lg.ErrorWithFields(logger.Fields{
+			"partition": partition,
+			"table":     "ResultTransition",
+			"error":     err,
+		}, "An error occurred while dao.migrateTable in dao.EnsureResultPartition.")
+		return err
+	}
+
+	// 创建分区记录，已存在时忽略
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ResultPartition{Partition: partition})
+	if res.Error != nil {
+		d.lg.ErrorWithFields(logger.Fields{
+			"partition": partition,
+			"error":     res.Error,
+		}, "An error occurred while creating result partition record in dao.EnsureResultPartition.")
+		return res.Error
+	}
+
+	d.partitions.Store(partition, struct{}{})
+	return nil
+}
+
+// checkResultPartition 确认结果分区记录存在，分区不存在时返回dto.ErrResultPartitionNotFound
+// 状态上报等写入路径的分区来自调用方，只校验不创建，建表和补齐字段由分区管理负责
+func (d *Dao) checkResultPartition(ctx context.Context, partition string) error {
+	resPart, err := d.GetResultPartition(ctx, orm.Query{"`partition`=?": partition})
+	if err != nil {
+		return err
+	}
+	if resPart == nil || resPart.Id < 1 {
+		return fmt.Errorf("%w: %s", dto.ErrResultPartitionNotFound, partition)
+	}
+	return nil
+}
+
+// migrateTable 以指定表名创建表，表已存在时补充缺少的字段，不修改或删除已有字段
+func (d *Dao) migrateTable(db *gorm.DB, table string, mdl interface{}) error {
+	if !db.Migrator().HasTable(table) {
+		if err := db.Table(table).Migrator().CreateTable(mdl); err != nil {
+			// 并发创建时表可能已被其他请求创建
+			if !db.Migrator().HasTable(table) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(mdl); err != nil {
+		return err
+	}
+
+	m := db.Table(table).Migrator()
+	for _, column := range stmt.Schema.DBNames {
+		if m.HasColumn(mdl, column) {
+			continue
+		}
+		if err := m.AddColumn(mdl, column); err != nil {
+			// 并发添加时字段可能已被其他请求添加
+			if m.HasColumn(mdl, column) {
+				continue
+			}
+			return err
+		}
+		d.lg.InfoWithFields(logger.Fields{
+			"table":  table,
+			"column": column,
+		}, "Missing column added to result partition table.")
+	}
+
+	return nil
+}
+
+// DropResultPartition 删除结果分区及其结果表、日志表和状态变更记录表
+func (d *Dao) DropResultPartition(ctx context.Context, partition string) error {
+	db := d.getDbWithCtx(ctx)
+
+	// 先删除分区记录，避免删表后仍能查到分区
+	if res := db.Where("`partition`=?", partition).Delete(&model.ResultPartition{}); res.Error != nil {
+		return res.Error
+	}
+	d.partitions.Delete(partition)
+
+	return db.Migrator().DropTable(
+		d.getResultTableNameByPartition(partition),
+		d.getLogTableNameByPartition(partition),
+		d.getResultTransitionTableNameByPartition(partition),
+	)
+}
+
+// ArchiveResultPartition 归档结果分区，将结果表、日志表和状态变更记录表改名为归档表并删除分区记录
+func (d *Dao) ArchiveResultPartition(ctx context.Context, partition string) error {
+	db := d.getDbWithCtx(ctx)
+
+	if res := db.Where("`partition`=?", partition).Delete(&model.ResultPartition{}); res.Error != nil {
+		return res.Error
+	}
+	d.partitions.Delete(partition)
+
+	for _, table := range []string{
+		d.getResultTableNameByPartition(partition),
+		d.getLogTableNameByPartition(partition),
+		d.getResultTransitionTableNameByPartition(partition),
+	} {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := db.Migrator().RenameTable(table, archivedTableName(table)); err != nil {
+			// 表可能已被其他请求归档
+			if !db.Migrator().HasTable(table) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archivedTableName 获得归档表名
+func archivedTableName(table string) string {
+	return fmt.Sprintf("archived_%s", table)
+}
+
+// ListResultPartitionStats 获得各结果分区的结果表、日志表和状态变更记录表行数及大小，行数为InnoDB统计的估算值
+func (d *Dao) ListResultPartitionStats(ctx context.Context) ([]*dto.ResultPartitionStat, error) {
+	resParts, err := d.ListResultPartitions(ctx, orm.Query{})
+	if err != nil {
 		return nil, err
 	}
 
-	tx.Commit()
-	return resPart, nil
+	stats := make([]*dto.ResultPartitionStat, 0, len(resParts))
+	if len(resParts) < 1 {
+		return stats, nil
+	}
+
+	tables := make([]string, 0, len(resParts)*3)
+	for _, p := range resParts {
+		tables = append(tables,
+			d.getResultTableNameByPartition(p.Partition),
+			d.getLogTableNameByPartition(p.Partition),
+			d.getResultTransitionTableNameByPartition(p.Partition),
+		)
+	}
+
+	type tableStat struct {
+		TableName string
+		TableRows int64
+		Size      int64
+	}
+	var tStats []*tableStat
+	res := d.getDbWithCtx(ctx).
+		Table("information_schema.TABLES").
+		Select("TABLE_NAME AS table_name, TABLE_ROWS AS table_rows, DATA_LENGTH + INDEX_LENGTH AS size").
+		Where("TABLE_SCHEMA = DATABASE() AND TABLE_NAME IN ?", tables).
+		Scan(&tStats)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	tsMap := make(map[string]*tableStat, len(tStats))
+	for _, ts := range tStats {
+		tsMap[ts.TableName] = ts
+	}
+
+	for _, p := range resParts {
+		s := &dto.ResultPartitionStat{Id: p.Id, Partition: p.Partition}
+		if ts, ok := tsMap[d.getResultTableNameByPartition(p.Partition)]; ok {
+			s.ResultRows = ts.TableRows
+			s.ResultSize = ts.Size
+		}
+		if ts, ok := tsMap[d.getLogTableNameByPartition(p.Partition)]; ok {
+			s.LogRows = ts.TableRows
+			s.LogSize = ts.Size
+		}
+		if ts, ok := tsMap[d.getResultTransitionTableNameByPartition(p.Partition)]; ok {
+			s.TransitionRows = ts.TableRows
+			s.TransitionSize = ts.Size
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
 }
 
 // GetResultPartition 查询单个结果分区
@@ -95,6 +277,6 @@ func (d *Dao) PagedListResultPartitions(
 	ctx context.Context, q orm.Query, page, pageSize int, orderBy ...string,
 ) (*orm.Paginator, error) {
 	resParts := make([]*model.ResultPartition, pageSize)
-	db := q.Where(d.getDbWithCtx(ctx).Model(&model.Task{}))
+	db := q.Where(d.getDbWithCtx(ctx).Model(&model.ResultPartition{}))
 	return orm.PagingQuery(db, page, pageSize, &resParts, orderBy...)
 }
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"eago/common/orm"
+	"eago/task/dto"
+	"eago/task/model"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SearchResults 跨分区查询结果，按开始时间倒序合并各分区结果，使用游标分页
+func (d *Dao) SearchResults(
+	ctx context.Context, q orm.Query, from, to *time.Time, cursor *dto.ResultSearchCursor, limit int,
+) (results []*model.PartitionedResult, next *dto.ResultSearchCursor, err error) {
+	parts, err := d.listSearchPartitions(ctx, from, to, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results = make([]*model.PartitionedResult, 0, limit)
+	// 多取一条用于判断是否还有下一页
+	for _, p := range parts {
+		remain := limit + 1 - len(results)
+		if remain < 1 {
+			break
+		}
+
+		db := q.Where(d.getDbWithCtx(ctx).Table(d.getResultTableNameByPartition(p)))
+		if from != nil {
+			db = db.Where("start_at>=?", *from)
+		}
+		if to != nil {
+			db = db.Where("start_at<?", *to)
+		}
+		// 游标所在分区只取游标之后的结果
+		if cursor != nil && cursor.Partition == p {
+			cTime := time.Unix(cursor.StartAt, 0)
+			db = db.Where("(start_at<? OR (start_at=? AND id<?))", cTime, cTime, cursor.Id)
+		}
+
+		var rs []*model.Result
+		if res := db.Order("start_at DESC, id DESC").Limit(remain).Find(&rs); res.Error != nil {
+			return nil, nil, res.Error
+		}
+		for _, r := range rs {
+			results = append(results, &model.PartitionedResult{
+				Result:       *r,
+				Partition:    p,
+				TaskUniqueId: fmt.Sprintf("%s%s%d", p, d.conf.Const.TaskUniqueIdSeparator, r.Id),
+			})
+		}
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+		last := results[len(results)-1]
+		next = &dto.ResultSearchCursor{Partition: last.Partition, StartAt: last.StartAt.Unix(), Id: last.Id}
+	}
+
+	return results, next, nil
+}
+
+// listSearchPartitions 列出时间范围内的分区，按时间倒序排列；有游标时只保留游标所在及更早的分区
+func (d *Dao) listSearchPartitions(
+	ctx context.Context, from, to *time.Time, cursor *dto.ResultSearchCursor,
+) ([]string, error) {
+	query := orm.Query{}
+	if from != nil {
+		query["`partition`>=?"] = from.Format(d.conf.Const.TaskResultPartitionTsFormat)
+	}
+
+	upper := ""
+	if to != nil {
+		upper = to.Format(d.conf.Const.TaskResultPartitionTsFormat)
+	}
+	if cursor != nil && (upper == "" || cursor.Partition < upper) {
+		upper = cursor.Partition
+	}
+	if upper != "" {
+		query["`partition`<=?"] = upper
+	}
+
+	resParts, err := d.ListResultPartitions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分区名按时间格式生成，按字符串倒序即为时间倒序
+	parts := make([]string, 0, len(resParts))
+	for _, rp := range resParts {
+		parts = append(parts, rp.Partition)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(parts)))
+
+	return parts, nil
+}
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"eago/common/orm"
+	"eago/task/dto"
+	"fmt"
+	"time"
+)
+
+// resultStatsBucketFormats 将开始时间截断到时间桶起点的MySQL格式
+var resultStatsBucketFormats = map[string]string{
+	dto.ResultStatsBucketHour: "%Y-%m-%d %H:00:00",
+	dto.ResultStatsBucketDay:  "%Y-%m-%d 00:00:00",
+}
+
+// ListResultStatCounts 在各分区中按分组字段、时间桶及状态聚合时间范围内的结果数量及最大耗时
+func (d *Dao) ListResultStatCounts(
+	ctx context.Context, q orm.Query, groupBy, bucket string, from, to time.Time,
+) ([]*dto.ResultStatCount, error) {
+	format, ok := resultStatsBucketFormats[bucket]
+	if !ok {
+		return nil, fmt.Errorf("unsupported result stats bucket: %s", bucket)
+	}
+
+	parts, err := d.listSearchPartitions(ctx, &from, &to, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	selects := fmt.Sprintf(
+		"CAST(`%s` AS CHAR) AS `key`, DATE_FORMAT(start_at, ?) AS bucket, status, COUNT(*) AS count, "+
+			"MAX(TIMESTAMPDIFF(SECOND, start_at, end_at)) AS duration_max",
+		groupBy,
+	)
+	counts := make([]*dto.ResultStatCount, 0)
+	for _, p := range parts {
+		var pCounts []*dto.ResultStatCount
+		res := q.Where(d.getDbWithCtx(ctx).Table(d.getResultTableNameByPartition(p))).
+			Select(selects, format).
+			Where("start_at>=? AND start_at<?", from, to).
+			Group("`key`, bucket, status").
+			Scan(&pCounts)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		counts = append(counts, pCounts...)
+	}
+
+	return counts, nil
+}
+
+// ListResultDurationSamples 按开始时间倒序读取时间范围内已结束结果的耗时(秒)，按分组字段归类，每个分组最多limit条
+// 在每个分组内分别取样，避免结果较多的分组挤占其他分组的样本，窗口函数需要MySQL 8.0及以上
+func (d *Dao) ListResultDurationSamples(
+	ctx context.Context, q orm.Query, groupBy string, from, to time.Time, limit int,
+) (map[string][]int64, error) {
+	parts, err := d.listSearchPartitions(ctx, &from, &to, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	selects := fmt.Sprintf(
+		"CAST(`%s` AS CHAR) AS `key`, TIMESTAMPDIFF(SECOND, start_at, end_at) AS duration, "+
+			"ROW_NUMBER() OVER (PARTITION BY `%s` ORDER BY start_at DESC) AS row_num",
+		groupBy, groupBy,
+	)
+	samples := make(map[string][]int64)
+	// 分区按时间倒序，已取够的分组忽略更早分区中的结果
+	for _, p := range parts {
+		sub := q.Where(d.getDbWithCtx(ctx).Table(d.getResultTableNameByPartition(p))).
+			Select(selects).
+			Where("start_at>=? AND start_at<?", from, to).
+			Where("status<=? AND end_at IS NOT NULL", dto.TaskResultStatusSuccessEnd)
+
+		var rows []*dto.ResultDurationSample
+		res := d.getDbWithCtx(ctx).Table("(?) AS samples", sub).
+			Select("`key`, duration").
+			Where("row_num<=?", limit).
+			Scan(&rows)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+
+		for _, r := range rows {
+			if len(samples[r.Key]) < limit {
+				samples[r.Key] = append(samples[r.Key], r.Duration)
+			}
+		}
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"context"
+	"eago/common/utils"
+	"eago/task/model"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ListResultTransitions 按变更顺序列出任务结果的状态变更记录（需指定分区）
+func (d *Dao) ListResultTransitions(
+	ctx context.Context, partition string, resId uint32,
+) (trans []*model.ResultTransition, err error) {
+	db := d.getDbWithCtx(ctx)
+	table := d.getResultTransitionTableNameByPartition(partition)
+
+	// 早于状态变更记录功能的归档分区没有变更记录表
+	if !db.Migrator().HasTable(table) {
+		return make([]*model.ResultTransition, 0), nil
+	}
+
+	res := db.Table(table).
+		Where("result_id=?", resId).
+		Order("id").
+		Find(&trans)
+	return trans, res.Error
+}
+
+// newResultTransition 新增状态变更记录，from为空表示任务结果新建
+func (d *Dao) newResultTransition(
+	db *gorm.DB, partition string, resId uint32, from *int32, to int32, source string, at time.Time,
+) error {
+	t := &model.ResultTransition{
+		ResultId:   resId,
+		FromStatus: from,
+		ToStatus:   to,
+		Source:     source,
+		CreatedAt:  &utils.CustomTime{Time: at},
+	}
+
+	return db.Table(d.getResultTransitionTableNameByPartition(partition)).Create(t).Error
+}
+
+// getResultTransitionTableNameByPartition 按分区获得状态变更记录表名
+func (d *Dao) getResultTransitionTableNameByPartition(partition string) string {
+	return fmt.Sprintf("result_transitions_%s", partition)
+}
@@ -8,7 +8,9 @@ import (
 
 // NewSchedule 新建计划任务
 func (d *Dao) NewSchedule(
-	ctx context.Context, tCodeName, expr, args, description string, timeout int64, disabled bool, createdBy string,
+	ctx context.Context,
+	tCodeName, expr, args, description string, timeout int64, disabled bool,
+	productId, groupId uint32, roleName, createdBy string,
 ) (*model.Schedule, error) {
 	sch := &model.Schedule{
 		TaskCodename: tCodeName,
@@ -17,6 +19,9 @@ func (d *Dao) NewSchedule(
 		Timeout:      &timeout,
 		Arguments:    args,
 		Disabled:     &disabled,
+		ProductId:    productId,
+		GroupId:      groupId,
+		RoleName:     roleName,
 		CreatedBy:    createdBy,
 	}
 
@@ -33,7 +38,8 @@ func (d *Dao) RemoveSchedule(ctx context.Context, schId uint32) error {
 // SetSchedule 更新计划任务
 func (d *Dao) SetSchedule(
 	ctx context.Context,
-	id uint32, tCodeName, expr, args, description string, timeout int64, disabled bool, updatedBy string,
+	id uint32, tCodeName, expr, args, description string, timeout int64, disabled bool,
+	productId, groupId uint32, roleName, updatedBy string,
 ) (sch *model.Schedule, err error) {
 	res := d.getDbWithCtx(ctx).Model(&model.Schedule{}).
 		Where("id=?", id).
@@ -44,6 +50,9 @@ func (d *Dao) SetSchedule(
 			"arguments":     args,
 			"disabled":      disabled,
 			"description":   description,
+			"product_id":    productId,
+			"group_id":      groupId,
+			"role_name":     roleName,
 			"updated_by":    updatedBy,
 		}).
 		Limit(1).Find(&sch)
@@ -51,7 +60,7 @@ func (d *Dao) SetSchedule(
 }
 
 // GetSchedule 查询单个计划任务
-func (d *Dao) GetSchedule(ctx context.Context, q orm.Query) (sch *model.Task, err error) {
+func (d *Dao) GetSchedule(ctx context.Context, q orm.Query) (sch *model.Schedule, err error) {
 	res := q.Where(d.getDbWithCtx(ctx)).Limit(1).Find(&sch)
 	return sch, res.Error
 }
@@ -4,19 +4,30 @@ import (
 	"context"
 	"eago/common/orm"
 	"eago/task/model"
+	"time"
 )
 
 // NewTask 新建任务
 func (d *Dao) NewTask(
-	ctx context.Context, disabled bool, category int32, codename, description, fParams, createdBy string,
+	ctx context.Context,
+	disabled bool, category int32, codename, description, fParams string, script, scriptEnv *string,
+	artifactName string, artifactVersion uint32,
+	productId, groupId uint32, roleName, createdBy string,
 ) (*model.Task, error) {
 	t := &model.Task{
-		Category:     &category,
-		Codename:     codename,
-		Description:  &description,
-		FormalParams: fParams,
-		Disabled:     &disabled,
-		CreatedBy:    createdBy,
+		Category:        &category,
+		Codename:        codename,
+		Description:     &description,
+		FormalParams:    fParams,
+		Script:          script,
+		ScriptEnv:       scriptEnv,
+		ArtifactName:    artifactName,
+		ArtifactVersion: artifactVersion,
+		Disabled:        &disabled,
+		ProductId:       productId,
+		GroupId:         groupId,
+		RoleName:        roleName,
+		CreatedBy:       createdBy,
 	}
 
 	res := d.getDbWithCtx(ctx).Create(&t)
@@ -31,22 +42,59 @@ func (d *Dao) RemoveTask(ctx context.Context, taskId uint32) error {
 
 // SetTask 更新任务
 func (d *Dao) SetTask(
-	ctx context.Context, id uint32, disabled bool, category int32, codename, description, fParams, updatedBy string,
+	ctx context.Context,
+	id uint32, disabled bool, category int32, codename, description, fParams string, script, scriptEnv *string,
+	artifactName string, artifactVersion uint32,
+	productId, groupId uint32, roleName, updatedBy string,
 ) (t *model.Task, err error) {
 	res := d.getDbWithCtx(ctx).Model(&model.Task{}).
 		Where("id=?", id).
 		Updates(map[string]interface{}{
-			"category":      category,
-			"codename":      codename,
-			"formal_params": fParams,
-			"disabled":      disabled,
-			"description":   description,
-			"updated_by":    updatedBy,
+			"category":         category,
+			"codename":         codename,
+			"formal_params":    fParams,
+			"script":           script,
+			"script_env":       scriptEnv,
+			"artifact_name":    artifactName,
+			"artifact_version": artifactVersion,
+			"disabled":         disabled,
+			"description":      description,
+			"product_id":       productId,
+			"group_id":         groupId,
+			"role_name":        roleName,
+			"updated_by":       updatedBy,
 		}).
 		Limit(1).Find(&t)
 	return t, res.Error
 }
 
+// SetTasksSeen 标记任务存在活跃Worker，同时取消孤立状态
+func (d *Dao) SetTasksSeen(ctx context.Context, ids []uint32, seenAt time.Time) error {
+	if len(ids) < 1 {
+		return nil
+	}
+
+	res := d.getDbWithCtx(ctx).Model(&model.Task{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"orphaned":     false,
+			"last_seen_at": seenAt,
+		})
+	return res.Error
+}
+
+// SetTasksOrphaned 标记任务为孤立状态，即没有任何活跃Worker注册了该任务
+func (d *Dao) SetTasksOrphaned(ctx context.Context, ids []uint32) error {
+	if len(ids) < 1 {
+		return nil
+	}
+
+	res := d.getDbWithCtx(ctx).Model(&model.Task{}).
+		Where("id IN ?", ids).
+		Update("orphaned", true)
+	return res.Error
+}
+
 // GetTask 查询单个任务
 func (d *Dao) GetTask(ctx context.Context, q orm.Query) (t *model.Task, err error) {
 	res := q.Where(d.getDbWithCtx(ctx)).Limit(1).Find(&t)
@@ -0,0 +1,13 @@
+package dto
+
+// ResultPartitionStat 结果分区统计，行数为估算值，大小单位为字节
+type ResultPartitionStat struct {
+	Id             uint32 `json:"id"`
+	Partition      string `json:"partition"`
+	ResultRows     int64  `json:"result_rows"`
+	ResultSize     int64  `json:"result_size"`
+	LogRows        int64  `json:"log_rows"`
+	LogSize        int64  `json:"log_size"`
+	TransitionRows int64  `json:"transition_rows"`
+	TransitionSize int64  `json:"transition_size"`
+}
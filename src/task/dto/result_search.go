@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// ResultSearchCursor 跨分区查询结果的游标，记录上一页最后一条结果的位置
+type ResultSearchCursor struct {
+	Partition string `json:"p"`
+	StartAt   int64  `json:"s"`
+	Id        uint32 `json:"i"`
+}
+
+// Encode 将游标编码为字符串
+func (c *ResultSearchCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeResultSearchCursor 将字符串解码为游标
+func DecodeResultSearchCursor(s string) (*ResultSearchCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ResultSearchCursor{}
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
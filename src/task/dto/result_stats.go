@@ -0,0 +1,61 @@
+package dto
+
+const (
+	ResultStatsGroupByTaskCodename = "task_codename"
+	ResultStatsGroupByStatus       = "status"
+	ResultStatsGroupByCaller       = "caller"
+	ResultStatsGroupByWorker       = "worker"
+
+	ResultStatsBucketHour = "hour"
+	ResultStatsBucketDay  = "day"
+)
+
+// ResultStatCount 按分组字段、时间桶及状态聚合的结果数量，DurationMax为已结束结果的最大耗时(秒)
+type ResultStatCount struct {
+	Key         string
+	Bucket      string
+	Status      int32
+	Count       int64
+	DurationMax *int64
+}
+
+// ResultDurationSample 用于估算耗时百分位数的已结束结果耗时(秒)
+type ResultDurationSample struct {
+	Key      string
+	Duration int64
+}
+
+// ResultStats 结果统计
+type ResultStats struct {
+	StartFrom string               `json:"start_from"`
+	StartTo   string               `json:"start_to"`
+	GroupBy   string               `json:"group_by"`
+	Bucket    string               `json:"bucket"`
+	Groups    []*ResultStatsGroup  `json:"groups"`
+	Histogram []*ResultStatsBucket `json:"histogram"`
+}
+
+// ResultStatsGroup 按分组字段聚合的结果统计，耗时单位为秒
+// DurationP50及DurationP95按该分组最近DurationSampled条已结束结果估算，没有样本时为null，DurationMax为精确值
+type ResultStatsGroup struct {
+	Key         string          `json:"key"`
+	Total       int64           `json:"total"`
+	Unfinished  int64           `json:"unfinished"`
+	Success     int64           `json:"success"`
+	SuccessRate float64         `json:"success_rate"`
+	Failures    map[int32]int64 `json:"failures"`
+	DurationP50 *int64          `json:"duration_p50"`
+	DurationP95 *int64          `json:"duration_p95"`
+	DurationMax int64           `json:"duration_max"`
+	// DurationSampled 估算耗时百分位数使用的结果数
+	DurationSampled int64 `json:"duration_sampled"`
+}
+
+// ResultStatsBucket 按时间分桶的结果数量
+type ResultStatsBucket struct {
+	StartAt    string `json:"start_at"`
+	Total      int64  `json:"total"`
+	Unfinished int64  `json:"unfinished"`
+	Success    int64  `json:"success"`
+	Failed     int64  `json:"failed"`
+}
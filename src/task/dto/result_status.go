@@ -0,0 +1,60 @@
+package dto
+
+import "errors"
+
+// ErrIllegalResultStatusTransition 不合法的任务结果状态变更
+var ErrIllegalResultStatusTransition = errors.New("illegal result status transition")
+
+// ErrResultPartitionNotFound 任务结果分区不存在
+var ErrResultPartitionNotFound = errors.New("result partition not found")
+
+// resultStatusWorkerEnds Worker上报的结束状态
+var resultStatusWorkerEnds = []int32{
+	TaskResultStatusSuccessEnd,
+	TaskResultStatusFailedEnd,
+	TaskResultStatusTimeoutEnd,
+	TaskResultStatusManualEnd,
+	TaskResultStatusPanicEnd,
+	TaskResultStatusWorkerTaskNotFoundErrEnd,
+}
+
+// resultStatusTransitions 合法的任务结果状态变更，Key为变更前状态
+var resultStatusTransitions = map[int32][]int32{
+	TaskResultStatusInitialization: append([]int32{
+		TaskResultStatusPending,
+		TaskResultStatusRunning,
+		TaskResultStatusCallErrEnd,
+		TaskResultStatusNoWorkerErrEnd,
+	}, resultStatusWorkerEnds...),
+	TaskResultStatusPending: append([]int32{
+		TaskResultStatusRunning,
+		TaskResultStatusNoWorkerErrEnd,
+	}, resultStatusWorkerEnds...),
+	TaskResultStatusRunning: append([]int32{
+		TaskResultStatusNoWorkerErrEnd,
+	}, resultStatusWorkerEnds...),
+	// 调用Worker超时时任务可能已被Worker接收，以Worker之后上报的状态为准
+	TaskResultStatusCallErrEnd: append([]int32{
+		TaskResultStatusPending,
+		TaskResultStatusRunning,
+	}, resultStatusWorkerEnds...),
+}
+
+// IsResultStatusEnd 任务结果状态是否为结束状态
+func IsResultStatusEnd(status int32) bool {
+	return status <= TaskResultStatusSuccessEnd
+}
+
+// IsResultStatusTransitionAllowed 任务结果状态能否从from变更为to，相同状态视为合法的重复上报
+func IsResultStatusTransitionAllowed(from, to int32) bool {
+	if from == to {
+		return true
+	}
+
+	for _, s := range resultStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"testing"
+)
+
+func TestIsResultStatusTransitionAllowed(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to int32
+		want     bool
+	}{
+		{"same status", TaskResultStatusRunning, TaskResultStatusRunning, true},
+		{"same end status", TaskResultStatusSuccessEnd, TaskResultStatusSuccessEnd, true},
+		{"init to pending", TaskResultStatusInitialization, TaskResultStatusPending, true},
+		{"init to call error", TaskResultStatusInitialization, TaskResultStatusCallErrEnd, true},
+		{"pending to running", TaskResultStatusPending, TaskResultStatusRunning, true},
+		{"pending to init", TaskResultStatusPending, TaskResultStatusInitialization, false},
+		{"pending to call error", TaskResultStatusPending, TaskResultStatusCallErrEnd, false},
+		{"running to success", TaskResultStatusRunning, TaskResultStatusSuccessEnd, true},
+		{"running to no worker", TaskResultStatusRunning, TaskResultStatusNoWorkerErrEnd, true},
+		{"running to pending", TaskResultStatusRunning, TaskResultStatusPending, false},
+		{"call error to pending", TaskResultStatusCallErrEnd, TaskResultStatusPending, true},
+		{"call error to running", TaskResultStatusCallErrEnd, TaskResultStatusRunning, true},
+		{"call error to success", TaskResultStatusCallErrEnd, TaskResultStatusSuccessEnd, true},
+		{"call error to no worker", TaskResultStatusCallErrEnd, TaskResultStatusNoWorkerErrEnd, false},
+		{"success to running", TaskResultStatusSuccessEnd, TaskResultStatusRunning, false},
+		{"success to failed", TaskResultStatusSuccessEnd, TaskResultStatusFailedEnd, false},
+		{"manual to running", TaskResultStatusManualEnd, TaskResultStatusRunning, false},
+		{"timeout to success", TaskResultStatusTimeoutEnd, TaskResultStatusSuccessEnd, false},
+		{"no worker to pending", TaskResultStatusNoWorkerErrEnd, TaskResultStatusPending, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsResultStatusTransitionAllowed(tc.from, tc.to); got != tc.want {
+				t.Errorf("IsResultStatusTransitionAllowed(%d, %d) = %v, want %v", tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsResultStatusEnd(t *testing.T) {
+	ends := []int32{
+		TaskResultStatusPanicEnd,
+		TaskResultStatusCallErrEnd,
+		TaskResultStatusNoWorkerErrEnd,
+		TaskResultStatusWorkerTaskNotFoundErrEnd,
+		TaskResultStatusFailedEnd,
+		TaskResultStatusTimeoutEnd,
+		TaskResultStatusManualEnd,
+		TaskResultStatusSuccessEnd,
+	}
+	for _, s := range ends {
+		if !IsResultStatusEnd(s) {
+			t.Errorf("IsResultStatusEnd(%d) = false, want true", s)
+		}
+	}
+
+	for _, s := range []int32{TaskResultStatusInitialization, TaskResultStatusPending, TaskResultStatusRunning} {
+		if IsResultStatusEnd(s) {
+			t.Errorf("IsResultStatusEnd(%d) = true, want false", s)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	ScriptTaskName = "builtin_script" // Worker内置脚本任务名
+
+	ScriptInterpreterBash   = "bash"   // Bash脚本
+	ScriptInterpreterPython = "python" // Python脚本
+)
+
+// scriptEnvKeyRegexp 脚本环境变量名
+var scriptEnvKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ScriptTaskArguments 内置脚本任务的参数，由Task模块根据存储的脚本生成
+type ScriptTaskArguments struct {
+	Interpreter string            `json:"interpreter"`
+	Script      string            `json:"script"`
+	Arguments   json.RawMessage   `json:"arguments"`
+	Env         map[string]string `json:"env,omitempty"` // 任务定义中的环境变量，不能覆盖Worker设置的保留变量
+	Artifact    *ScriptArtifact   `json:"artifact,omitempty"`
+}
+
+// ScriptArtifact 脚本任务引用的制品，Worker按sha256拉取并缓存
+type ScriptArtifact struct {
+	Name    string `json:"name"`
+	Version uint32 `json:"version"`
+	Sha256  string `json:"sha256"`
+}
+
+// IsReservedScriptEnv 是否为Worker为脚本设置的保留环境变量
+func IsReservedScriptEnv(key string) bool {
+	switch key {
+	case "PATH", "HOME", "TMPDIR":
+		return true
+	}
+	return strings.HasPrefix(key, "EAGO_")
+}
+
+// ParseScriptEnv 解析任务定义中JSON对象格式的脚本环境变量，空字符串视为未设置
+func ParseScriptEnv(raw string) (map[string]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	env := make(map[string]string)
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		return nil, fmt.Errorf("script env must be a JSON object of strings: %v", err)
+	}
+	for k := range env {
+		if !scriptEnvKeyRegexp.MatchString(k) {
+			return nil, fmt.Errorf("invalid script env name: %q", k)
+		}
+		if IsReservedScriptEnv(k) {
+			return nil, fmt.Errorf("script env %s is reserved", k)
+		}
+	}
+
+	return env, nil
+}
@@ -0,0 +1,6 @@
+package dto
+
+import "errors"
+
+// ErrInvalidFormalParams 任务形参不是合法的JSON Schema
+var ErrInvalidFormalParams = errors.New("invalid task formal params")
@@ -5,4 +5,47 @@ type WorkerInfo struct {
 	Address   string `json:"address"`
 	WorkerId  string `json:"worker_id"`
 	StartTime string `json:"start_time"`
+
+	// 以下字段由Worker心跳定期更新，旧版本Worker不上报时为空
+	Codenames     []string          `json:"codenames"`
+	Tasks         []*WorkerTaskMeta `json:"tasks"`
+	RunningTasks  int               `json:"running_tasks"`
+	LoadAvg       [3]float64        `json:"load_avg"`
+	MemTotal      uint64            `json:"mem_total"`
+	MemAvailable  uint64            `json:"mem_available"`
+	ProcessMemSys uint64            `json:"process_mem_sys"`
+	GoVersion     string            `json:"go_version"`
+	BuildVersion  string            `json:"build_version"`
+	HeartbeatTime string            `json:"heartbeat_time"`
+
+	// Cordoned 已隔离，调度中心不再下发新任务
+	Cordoned bool `json:"cordoned"`
+	// Paused 已暂停，Worker拒绝新任务调用，运行中的任务继续运行至结束
+	Paused bool `json:"paused"`
+}
+
+// Schedulable Worker是否可以接收新任务
+func (wi *WorkerInfo) Schedulable() bool {
+	return !wi.Cordoned && !wi.Paused
+}
+
+// HasCodename Worker是否注册了指定任务，未上报任务列表的旧版本Worker视为已注册
+func (wi *WorkerInfo) HasCodename(codename string) bool {
+	if wi.Codenames == nil {
+		return true
+	}
+
+	for _, c := range wi.Codenames {
+		if c == codename {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkerTaskMeta Worker注册任务的元信息，用于同步任务目录
+type WorkerTaskMeta struct {
+	Codename     string `json:"codename"`
+	Description  string `json:"description"`
+	FormalParams string `json:"formal_params"`
 }
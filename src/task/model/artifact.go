@@ -0,0 +1,24 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// Artifact struct
+type Artifact struct {
+	Id          uint32            `json:"id"`
+	Name        string            `json:"name"`
+	Version     uint32            `json:"version"`
+	Sha256      string            `json:"sha256"`
+	Size        int64             `json:"size"`
+	Storage     string            `json:"storage"`
+	Description *string           `json:"description"`
+	CreatedAt   *utils.CustomTime `json:"created_at"`
+	CreatedBy   string            `json:"created_by"`
+}
+
+// ArtifactBlob struct
+type ArtifactBlob struct {
+	Sha256  string `json:"sha256" gorm:"primaryKey"`
+	Content []byte `json:"-"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// PartitionedResult 带有分区信息的结果，用于跨分区查询
+type PartitionedResult struct {
+	Result
+	Partition    string `json:"partition" gorm:"-"`
+	TaskUniqueId string `json:"task_unique_id" gorm:"-"`
+}
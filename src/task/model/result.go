@@ -13,6 +13,7 @@ type Result struct {
 	Worker       string            `json:"worker" gorm:"type:varchar(100) NOT NULL;default:'';index"`
 	Timeout      *int64            `json:"timeout" gorm:"type:bigint(20) NOT NULL"`
 	Arguments    string            `json:"arguments" gorm:"type:json NOT NULL"`
+	ReturnValue  *string           `json:"return_value" gorm:"type:json"`
 	StartAt      *utils.CustomTime `json:"start_at" gorm:"type:datetime NOT NULL;index"`
 	EndAt        *utils.CustomTime `json:"end_at" gorm:"type:datetime"`
 }
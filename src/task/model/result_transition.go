@@ -0,0 +1,20 @@
+package model
+
+import (
+	"eago/common/utils"
+)
+
+// ResultTransition 任务结果状态变更记录
+type ResultTransition struct {
+	Id         uint64            `json:"id" gorm:"type:bigint(20) unsigned NOT NULL AUTO_INCREMENT;primaryKey"`
+	ResultId   uint32            `json:"result_id" gorm:"type:int(11) unsigned NOT NULL;index"`
+	FromStatus *int32            `json:"from_status" gorm:"type:int(11)"`
+	ToStatus   int32             `json:"to_status" gorm:"type:int(11) NOT NULL"`
+	Source     string            `json:"source" gorm:"type:varchar(150) NOT NULL;default:''"`
+	CreatedAt  *utils.CustomTime `json:"created_at" gorm:"type:datetime NOT NULL"`
+}
+
+// TableName 获取数据库表名
+func (*ResultTransition) TableName() string {
+	return "_tmp_result_transitions"
+}
@@ -12,6 +12,9 @@ type Schedule struct {
 	Timeout      *int64            `json:"timeout"`
 	Arguments    string            `json:"arguments"`
 	Disabled     *bool             `json:"disabled"`
+	ProductId    uint32            `json:"product_id" gorm:"default:0"`
+	GroupId      uint32            `json:"group_id" gorm:"default:0"`
+	RoleName     string            `json:"role_name" gorm:"default:''"`
 	Description  *string           `json:"description"`
 	CreatedAt    *utils.CustomTime `json:"created_at"`
 	CreatedBy    string            `json:"created_by"`
@@ -6,14 +6,23 @@ import (
 
 // Task struct
 type Task struct {
-	Id           uint32            `json:"id"`
-	Category     *int32            `json:"category"`
-	Codename     string            `json:"codename"`
-	FormalParams string            `json:"formal_params"`
-	Disabled     *bool             `json:"disabled" gorm:"default:0"`
-	Description  *string           `json:"description"`
-	CreatedAt    *utils.CustomTime `json:"created_at"`
-	CreatedBy    string            `json:"created_by"`
-	UpdatedAt    *utils.CustomTime `json:"updated_at"`
-	UpdatedBy    *string           `json:"updated_by" gorm:"default:''"`
+	Id              uint32            `json:"id"`
+	Category        *int32            `json:"category"`
+	Codename        string            `json:"codename"`
+	FormalParams    string            `json:"formal_params"`
+	Script          *string           `json:"script"`
+	ScriptEnv       *string           `json:"script_env"`
+	ArtifactName    string            `json:"artifact_name" gorm:"default:''"`
+	ArtifactVersion uint32            `json:"artifact_version" gorm:"default:0"`
+	Disabled        *bool             `json:"disabled" gorm:"default:0"`
+	ProductId       uint32            `json:"product_id" gorm:"default:0"`
+	GroupId         uint32            `json:"group_id" gorm:"default:0"`
+	RoleName        string            `json:"role_name" gorm:"default:''"`
+	Description     *string           `json:"description"`
+	Orphaned        *bool             `json:"orphaned" gorm:"default:0"`
+	LastSeenAt      *utils.CustomTime `json:"last_seen_at"`
+	CreatedAt       *utils.CustomTime `json:"created_at"`
+	CreatedBy       string            `json:"created_by"`
+	UpdatedAt       *utils.CustomTime `json:"updated_at"`
+	UpdatedBy       *string           `json:"updated_by" gorm:"default:''"`
 }
@@ -314,6 +314,7 @@ type Result struct {
 	Worker               string   `protobuf:"bytes,4,opt,name=worker,proto3" json:"worker,omitempty"`
 	StartAt              string   `protobuf:"bytes,5,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
 	EndAt                string   `protobuf:"bytes,6,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	ReturnValue          string   `protobuf:"bytes,7,opt,name=return_value,json=returnValue,proto3" json:"return_value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -386,6 +387,13 @@ func (m *Result) GetEndAt() string {
 	return ""
 }
 
+func (m *Result) GetReturnValue() string {
+	if m != nil {
+		return m.ReturnValue
+	}
+	return ""
+}
+
 type CallTaskReq struct {
 	TaskCodename         string   `protobuf:"bytes,1,opt,name=task_codename,json=taskCodename,proto3" json:"task_codename,omitempty"`
 	Timeout              int64    `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
@@ -488,9 +496,59 @@ func (m *TaskUniqueId) GetTaskUniqueId() string {
 	return ""
 }
 
+type SetWorkerStateReq struct {
+	WorkerId             string   `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Value                bool     `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetWorkerStateReq) Reset()         { *m = SetWorkerStateReq{} }
+func (m *SetWorkerStateReq) String() string { return proto.CompactTextString(m) }
+func (*SetWorkerStateReq) ProtoMessage()    {}
+func (*SetWorkerStateReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be9fd9d528dc9070, []int{7}
+}
+
+func (m *SetWorkerStateReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetWorkerStateReq.Unmarshal(m, b)
+}
+func (m *SetWorkerStateReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetWorkerStateReq.Marshal(b, m, deterministic)
+}
+func (m *SetWorkerStateReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetWorkerStateReq.Merge(m, src)
+}
+func (m *SetWorkerStateReq) XXX_Size() int {
+	return xxx_messageInfo_SetWorkerStateReq.Size(m)
+}
+func (m *SetWorkerStateReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetWorkerStateReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetWorkerStateReq proto.InternalMessageInfo
+
+func (m *SetWorkerStateReq) GetWorkerId() string {
+	if m != nil {
+		return m.WorkerId
+	}
+	return ""
+}
+
+func (m *SetWorkerStateReq) GetValue() bool {
+	if m != nil {
+		return m.Value
+	}
+	return false
+}
+
 type SetResultStatusReq struct {
-	TaskUniqueId         string   `protobuf:"bytes,1,opt,name=task_unique_id,json=taskUniqueId,proto3" json:"task_unique_id,omitempty"`
-	Status               int32    `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	TaskUniqueId string `protobuf:"bytes,1,opt,name=task_unique_id,json=taskUniqueId,proto3" json:"task_unique_id,omitempty"`
+	Status       int32  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	ReturnValue  []byte `protobuf:"bytes,3,opt,name=return_value,json=returnValue,proto3" json:"return_value,omitempty"`
+	// source 已废弃，服务端忽略该字段，状态变更来源取任务结果记录的执行Worker
+	Source               string   `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -500,7 +558,7 @@ func (m *SetResultStatusReq) Reset()         { *m = SetResultStatusReq{} }
 func (m *SetResultStatusReq) String() string { return proto.CompactTextString(m) }
 func (*SetResultStatusReq) ProtoMessage()    {}
 func (*SetResultStatusReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be9fd9d528dc9070, []int{7}
+	return fileDescriptor_be9fd9d528dc9070, []int{8}
 }
 
 func (m *SetResultStatusReq) XXX_Unmarshal(b []byte) error {
@@ -535,6 +593,20 @@ func (m *SetResultStatusReq) GetStatus() int32 {
 	return 0
 }
 
+func (m *SetResultStatusReq) GetReturnValue() []byte {
+	if m != nil {
+		return m.ReturnValue
+	}
+	return nil
+}
+
+func (m *SetResultStatusReq) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
 type AppendTaskLogReq struct {
 	TaskUniqueId         string   `protobuf:"bytes,1,opt,name=task_unique_id,json=taskUniqueId,proto3" json:"task_unique_id,omitempty"`
 	Content              string   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
@@ -547,7 +619,7 @@ func (m *AppendTaskLogReq) Reset()         { *m = AppendTaskLogReq{} }
 func (m *AppendTaskLogReq) String() string { return proto.CompactTextString(m) }
 func (*AppendTaskLogReq) ProtoMessage()    {}
 func (*AppendTaskLogReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be9fd9d528dc9070, []int{8}
+	return fileDescriptor_be9fd9d528dc9070, []int{9}
 }
 
 func (m *AppendTaskLogReq) XXX_Unmarshal(b []byte) error {
@@ -593,7 +665,7 @@ func (m *SrvTokenQuery) Reset()         { *m = SrvTokenQuery{} }
 func (m *SrvTokenQuery) String() string { return proto.CompactTextString(m) }
 func (*SrvTokenQuery) ProtoMessage()    {}
 func (*SrvTokenQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be9fd9d528dc9070, []int{9}
+	return fileDescriptor_be9fd9d528dc9070, []int{10}
 }
 
 func (m *SrvTokenQuery) XXX_Unmarshal(b []byte) error {
@@ -621,6 +693,92 @@ func (m *SrvTokenQuery) GetValue() string {
 	return ""
 }
 
+type ArtifactSha256 struct {
+	Sha256               string   `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArtifactSha256) Reset()         { *m = ArtifactSha256{} }
+func (m *ArtifactSha256) String() string { return proto.CompactTextString(m) }
+func (*ArtifactSha256) ProtoMessage()    {}
+func (*ArtifactSha256) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be9fd9d528dc9070, []int{11}
+}
+
+func (m *ArtifactSha256) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArtifactSha256.Unmarshal(m, b)
+}
+func (m *ArtifactSha256) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArtifactSha256.Marshal(b, m, deterministic)
+}
+func (m *ArtifactSha256) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArtifactSha256.Merge(m, src)
+}
+func (m *ArtifactSha256) XXX_Size() int {
+	return xxx_messageInfo_ArtifactSha256.Size(m)
+}
+func (m *ArtifactSha256) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArtifactSha256.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArtifactSha256 proto.InternalMessageInfo
+
+func (m *ArtifactSha256) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+type ArtifactContent struct {
+	Sha256               string   `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Content              []byte   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArtifactContent) Reset()         { *m = ArtifactContent{} }
+func (m *ArtifactContent) String() string { return proto.CompactTextString(m) }
+func (*ArtifactContent) ProtoMessage()    {}
+func (*ArtifactContent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be9fd9d528dc9070, []int{12}
+}
+
+func (m *ArtifactContent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArtifactContent.Unmarshal(m, b)
+}
+func (m *ArtifactContent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArtifactContent.Marshal(b, m, deterministic)
+}
+func (m *ArtifactContent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArtifactContent.Merge(m, src)
+}
+func (m *ArtifactContent) XXX_Size() int {
+	return xxx_messageInfo_ArtifactContent.Size(m)
+}
+func (m *ArtifactContent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArtifactContent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArtifactContent proto.InternalMessageInfo
+
+func (m *ArtifactContent) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *ArtifactContent) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*PagedTasks)(nil), "eago.task.PagedTasks")
 	proto.RegisterType((*PagedSchedules)(nil), "eago.task.PagedSchedules")
@@ -629,62 +787,74 @@ func init() {
 	proto.RegisterType((*Result)(nil), "eago.task.Result")
 	proto.RegisterType((*CallTaskReq)(nil), "eago.task.CallTaskReq")
 	proto.RegisterType((*TaskUniqueId)(nil), "eago.task.TaskUniqueId")
+	proto.RegisterType((*SetWorkerStateReq)(nil), "eago.task.SetWorkerStateReq")
 	proto.RegisterType((*SetResultStatusReq)(nil), "eago.task.SetResultStatusReq")
 	proto.RegisterType((*AppendTaskLogReq)(nil), "eago.task.AppendTaskLogReq")
 	proto.RegisterType((*SrvTokenQuery)(nil), "eago.task.SrvTokenQuery")
+	proto.RegisterType((*ArtifactSha256)(nil), "eago.task.ArtifactSha256")
+	proto.RegisterType((*ArtifactContent)(nil), "eago.task.ArtifactContent")
 }
 
 func init() { proto.RegisterFile("eago_task.proto", fileDescriptor_be9fd9d528dc9070) }
 
 var fileDescriptor_be9fd9d528dc9070 = []byte{
-	// 774 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xc1, 0x52, 0xdb, 0x48,
-	0x10, 0xb5, 0x8c, 0x6d, 0xac, 0xb6, 0x8d, 0x77, 0x67, 0x17, 0x56, 0x88, 0x5d, 0xca, 0x25, 0x96,
-	0x2a, 0x9f, 0xcc, 0x2e, 0xbb, 0x97, 0x1c, 0x72, 0x00, 0x8a, 0x4a, 0x91, 0x90, 0x2a, 0x22, 0x13,
-	0x52, 0x95, 0x8b, 0x6b, 0x6c, 0x35, 0x46, 0x65, 0x49, 0x23, 0x34, 0x23, 0x08, 0x1c, 0xf3, 0x05,
-	0xf9, 0x84, 0xdc, 0xf2, 0x01, 0xf9, 0x80, 0xfc, 0x5a, 0x6a, 0x66, 0x24, 0x5b, 0xd8, 0x31, 0xc5,
-	0x25, 0x27, 0xe9, 0xbd, 0xee, 0x56, 0xbf, 0x79, 0x3d, 0x6a, 0x68, 0x23, 0x1d, 0xb3, 0x81, 0xa0,
-	0x7c, 0xd2, 0x8b, 0x13, 0x26, 0x18, 0x31, 0x25, 0xd1, 0x93, 0x84, 0xbd, 0x35, 0x66, 0x6c, 0x1c,
-	0xe0, 0x9e, 0x0a, 0x0c, 0xd3, 0xcb, 0x3d, 0x0c, 0x63, 0x71, 0xa7, 0xf3, 0xec, 0xed, 0xf9, 0xe0,
-	0x6d, 0x42, 0xe3, 0x18, 0x13, 0x9e, 0xc5, 0x37, 0x47, 0x2c, 0x0c, 0x59, 0xa4, 0xe3, 0x7b, 0x1a,
-	0xe8, 0x90, 0xf3, 0xc9, 0x00, 0x38, 0xa3, 0x63, 0xf4, 0xce, 0x29, 0x9f, 0x70, 0xb2, 0x0b, 0x55,
-	0xd9, 0x8e, 0x5b, 0x46, 0x67, 0xa5, 0xdb, 0xd8, 0x6f, 0xf7, 0xa6, 0x0a, 0x7a, 0x32, 0xc1, 0xd5,
-	0x51, 0x42, 0xa0, 0x12, 0xd3, 0x31, 0x5a, 0xe5, 0x8e, 0xd1, 0x6d, 0xb9, 0xea, 0x9d, 0xfc, 0x0e,
-	0x55, 0xf9, 0xe4, 0xd6, 0x8a, 0x22, 0x35, 0x20, 0x5b, 0x60, 0xca, 0x97, 0x01, 0xf7, 0xef, 0xd1,
-	0xaa, 0xa8, 0x48, 0x5d, 0x12, 0x7d, 0xff, 0x5e, 0x95, 0x08, 0x26, 0x68, 0x60, 0x55, 0x75, 0x89,
-	0x02, 0xce, 0x67, 0x03, 0xd6, 0x94, 0xa4, 0xfe, 0xe8, 0x0a, 0xbd, 0x34, 0x40, 0x4e, 0xfe, 0x05,
-	0x93, 0xe7, 0x20, 0x93, 0xf6, 0x5b, 0x41, 0x5a, 0x9e, 0xe8, 0xce, 0xb2, 0x7e, 0xae, 0xc4, 0x3b,
-	0xa8, 0x48, 0x3b, 0xc8, 0x1a, 0x94, 0x7d, 0xcf, 0x32, 0x54, 0xa8, 0xec, 0x7b, 0xc4, 0x86, 0xfa,
-	0x88, 0x79, 0x18, 0xd1, 0x50, 0x37, 0x36, 0xdd, 0x29, 0x26, 0x3b, 0xd0, 0xba, 0x64, 0x49, 0x48,
-	0x83, 0x41, 0x4c, 0x13, 0x1a, 0x6a, 0x11, 0xa6, 0xdb, 0xd4, 0xe4, 0x99, 0xe2, 0x48, 0x07, 0x1a,
-	0x1e, 0xf2, 0x51, 0xe2, 0xc7, 0xc2, 0x67, 0x91, 0x52, 0x63, 0xba, 0x45, 0xca, 0xf9, 0x6a, 0x40,
-	0x3d, 0x3f, 0xef, 0x42, 0xff, 0x1d, 0x68, 0x49, 0x43, 0x06, 0x73, 0x22, 0x9a, 0x92, 0x3c, 0xca,
-	0x85, 0x6c, 0x03, 0xe0, 0x87, 0x38, 0x41, 0xce, 0x65, 0x0b, 0xad, 0xa2, 0xc0, 0x10, 0x0b, 0x56,
-	0x85, 0x1f, 0x22, 0x4b, 0x85, 0xea, 0xbf, 0xe2, 0xe6, 0x90, 0xfc, 0x09, 0x26, 0x4d, 0xc6, 0x69,
-	0x88, 0x91, 0xe0, 0xca, 0x10, 0xd3, 0x9d, 0x11, 0xf2, 0xf0, 0x9e, 0xcf, 0xe9, 0x30, 0x40, 0xcf,
-	0xaa, 0x75, 0x8c, 0x6e, 0xdd, 0x9d, 0x62, 0xe7, 0x8b, 0x01, 0x35, 0x17, 0x79, 0x1a, 0x88, 0x45,
-	0x8d, 0xc6, 0x0f, 0x34, 0x6e, 0x40, 0x8d, 0x0b, 0x2a, 0x52, 0xae, 0x4e, 0x50, 0x75, 0x33, 0x24,
-	0xf9, 0x11, 0x0d, 0x02, 0x4c, 0x32, 0xdd, 0x19, 0x92, 0xfc, 0x2d, 0x4b, 0x26, 0x98, 0x64, 0x96,
-	0x65, 0x88, 0x6c, 0x42, 0x9d, 0x0b, 0x9a, 0x88, 0x01, 0x15, 0x99, 0xe0, 0x55, 0x85, 0x0f, 0x04,
-	0x59, 0x87, 0x1a, 0x46, 0x9e, 0x0c, 0xd4, 0x54, 0xa0, 0x8a, 0x91, 0x77, 0x20, 0x9c, 0x8f, 0x06,
-	0x34, 0x8e, 0x68, 0x10, 0xa8, 0xeb, 0x8e, 0xd7, 0x4f, 0x93, 0x5b, 0xb0, 0xac, 0xfc, 0xd0, 0xb2,
-	0x65, 0x82, 0x1f, 0x58, 0x29, 0x35, 0x37, 0x0b, 0x56, 0x3a, 0xff, 0x43, 0x53, 0xf6, 0x7f, 0x1b,
-	0xf9, 0xd7, 0x29, 0x9e, 0x78, 0xe4, 0x6f, 0x58, 0x53, 0x22, 0x52, 0x45, 0x0c, 0xb2, 0x99, 0x67,
-	0x2a, 0xf2, 0x2c, 0xc7, 0x05, 0xd2, 0x47, 0xa1, 0x6d, 0xee, 0x2b, 0xbf, 0xe4, 0x01, 0x9e, 0x54,
-	0xbb, 0xcc, 0x70, 0xc7, 0x85, 0x5f, 0x0e, 0xe2, 0x18, 0x23, 0xb5, 0x1f, 0x4e, 0xd9, 0xf8, 0xe9,
-	0x5f, 0xb4, 0x60, 0x75, 0xc4, 0x22, 0x81, 0x91, 0xc8, 0x6e, 0x61, 0x0e, 0x9d, 0x5d, 0x68, 0xf5,
-	0x93, 0x9b, 0x73, 0x36, 0xc1, 0xe8, 0x4d, 0x8a, 0xc9, 0x9d, 0xfc, 0xc9, 0x6e, 0x68, 0x90, 0xe6,
-	0xde, 0x6a, 0xb0, 0xff, 0xad, 0x02, 0x0d, 0xd9, 0xb5, 0x8f, 0xc9, 0x8d, 0x3f, 0x42, 0xf2, 0x1c,
-	0xea, 0xf9, 0x60, 0xc8, 0x46, 0xe1, 0xef, 0x2f, 0x4c, 0xcb, 0xfe, 0x63, 0x6e, 0x61, 0x4d, 0xbd,
-	0x29, 0xc9, 0xf2, 0x57, 0x7e, 0x56, 0xbe, 0x2c, 0xcd, 0xde, 0xe8, 0xe9, 0x55, 0xda, 0xcb, 0x57,
-	0x69, 0xef, 0x58, 0xee, 0x59, 0xa7, 0x44, 0x8e, 0xb3, 0xa5, 0x74, 0xea, 0x73, 0xa1, 0x77, 0xa5,
-	0xad, 0x3f, 0x92, 0xad, 0x53, 0x75, 0x92, 0x77, 0xbe, 0xb8, 0x92, 0x59, 0xf6, 0x7a, 0xa1, 0xc1,
-	0x6c, 0xbd, 0x3a, 0x25, 0xf2, 0x12, 0xda, 0x73, 0x33, 0x22, 0x7f, 0x15, 0x37, 0xd9, 0xc2, 0xfc,
-	0x1e, 0x91, 0xf4, 0x0c, 0xcc, 0x17, 0x79, 0xfe, 0xf2, 0x23, 0xfd, 0x5a, 0x08, 0xe8, 0x5c, 0x25,
-	0xa3, 0xf5, 0x60, 0xac, 0x64, 0xab, 0x90, 0x35, 0x3f, 0xf0, 0xe5, 0x12, 0xba, 0xc6, 0x3f, 0x06,
-	0x79, 0x0d, 0x64, 0xea, 0xcc, 0x6c, 0x65, 0x3f, 0xe6, 0xce, 0xe6, 0xbc, 0x3b, 0xd3, 0x32, 0xa7,
-	0x44, 0x4e, 0xa0, 0x7d, 0xc2, 0x2f, 0x68, 0xe0, 0x7b, 0xf9, 0x25, 0x21, 0x56, 0xd1, 0xa1, 0xe2,
-	0xcd, 0xb1, 0xed, 0x05, 0x65, 0x87, 0x8c, 0x05, 0x17, 0xf2, 0xfe, 0x38, 0xa5, 0xc3, 0xfa, 0xfb,
-	0x9a, 0xac, 0x89, 0x87, 0xc3, 0x9a, 0x8a, 0xff, 0xf7, 0x3d, 0x00, 0x00, 0xff, 0xff, 0xc0, 0xc1,
-	0xd7, 0x90, 0x63, 0x07, 0x00, 0x00,
+	// 923 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x16, 0x65, 0x4b, 0x16, 0xc7, 0x92, 0xd5, 0x6c, 0x1b, 0x97, 0xa6, 0xd3, 0xc0, 0x65, 0x1a,
+	0x40, 0x27, 0xb9, 0x75, 0x7f, 0x80, 0x1e, 0x7a, 0x70, 0x84, 0x34, 0x70, 0x93, 0x02, 0x2e, 0x99,
+	0x26, 0x40, 0x2f, 0xc2, 0x9a, 0x1c, 0xcb, 0x84, 0x29, 0x2e, 0xb3, 0xbb, 0x74, 0xea, 0x1c, 0x7b,
+	0x2f, 0xd0, 0x47, 0xe8, 0xa9, 0x2f, 0xd0, 0xb7, 0xe8, 0x53, 0x15, 0xbb, 0x4b, 0x4a, 0x2b, 0x3a,
+	0x32, 0x0c, 0x14, 0x3d, 0x91, 0xf3, 0xcd, 0xec, 0xce, 0xb7, 0xdf, 0xcc, 0xec, 0xc2, 0x10, 0xe9,
+	0x8c, 0x4d, 0x25, 0x15, 0x97, 0xe3, 0x82, 0x33, 0xc9, 0x88, 0xab, 0x80, 0xb1, 0x02, 0xfc, 0xfd,
+	0x19, 0x63, 0xb3, 0x0c, 0x0f, 0xb5, 0xe3, 0xac, 0x3c, 0x3f, 0xc4, 0x79, 0x21, 0xaf, 0x4d, 0x9c,
+	0xff, 0xb0, 0xe9, 0x7c, 0xcb, 0x69, 0x51, 0x20, 0x17, 0x95, 0x7f, 0x2f, 0x66, 0xf3, 0x39, 0xcb,
+	0x8d, 0xff, 0xd0, 0x18, 0xc6, 0x15, 0xfc, 0xe1, 0x00, 0x9c, 0xd2, 0x19, 0x26, 0x2f, 0xa9, 0xb8,
+	0x14, 0xe4, 0x31, 0x74, 0x54, 0x3a, 0xe1, 0x39, 0x07, 0x1b, 0xa3, 0xed, 0xa3, 0xe1, 0x78, 0xc1,
+	0x60, 0xac, 0x02, 0x42, 0xe3, 0x25, 0x04, 0x36, 0x0b, 0x3a, 0x43, 0xaf, 0x7d, 0xe0, 0x8c, 0x06,
+	0xa1, 0xfe, 0x27, 0x1f, 0x41, 0x47, 0x7d, 0x85, 0xb7, 0xa1, 0x41, 0x63, 0x90, 0x7d, 0x70, 0xd5,
+	0xcf, 0x54, 0xa4, 0xef, 0xd0, 0xdb, 0xd4, 0x9e, 0x9e, 0x02, 0xa2, 0xf4, 0x9d, 0x5e, 0x22, 0x99,
+	0xa4, 0x99, 0xd7, 0x31, 0x4b, 0xb4, 0x11, 0xfc, 0xe9, 0xc0, 0x8e, 0xa6, 0x14, 0xc5, 0x17, 0x98,
+	0x94, 0x19, 0x0a, 0xf2, 0x05, 0xb8, 0xa2, 0x36, 0x2a, 0x6a, 0x1f, 0x5a, 0xd4, 0xea, 0xc0, 0x70,
+	0x19, 0xf5, 0xff, 0x52, 0xbc, 0x86, 0x4d, 0x25, 0x07, 0xd9, 0x81, 0x76, 0x9a, 0x78, 0x8e, 0x76,
+	0xb5, 0xd3, 0x84, 0xf8, 0xd0, 0x8b, 0x59, 0x82, 0x39, 0x9d, 0x9b, 0xc4, 0x6e, 0xb8, 0xb0, 0xc9,
+	0x23, 0x18, 0x9c, 0x33, 0x3e, 0xa7, 0xd9, 0xb4, 0xa0, 0x9c, 0xce, 0x0d, 0x09, 0x37, 0xec, 0x1b,
+	0xf0, 0x54, 0x63, 0xe4, 0x00, 0xb6, 0x13, 0x14, 0x31, 0x4f, 0x0b, 0x99, 0xb2, 0x5c, 0xb3, 0x71,
+	0x43, 0x1b, 0x0a, 0xfe, 0x76, 0xa0, 0x57, 0x9f, 0xf7, 0x46, 0xfe, 0x47, 0x30, 0x50, 0x82, 0x4c,
+	0x1b, 0x24, 0xfa, 0x0a, 0x9c, 0xd4, 0x44, 0x1e, 0x02, 0xe0, 0xaf, 0x05, 0x47, 0x21, 0x54, 0x0a,
+	0xc3, 0xc2, 0x42, 0x88, 0x07, 0x5b, 0x32, 0x9d, 0x23, 0x2b, 0xa5, 0xce, 0xbf, 0x11, 0xd6, 0x26,
+	0x79, 0x00, 0x2e, 0xe5, 0xb3, 0x72, 0x8e, 0xb9, 0x14, 0x5a, 0x10, 0x37, 0x5c, 0x02, 0xea, 0xf0,
+	0x49, 0x2a, 0xe8, 0x59, 0x86, 0x89, 0xd7, 0x3d, 0x70, 0x46, 0xbd, 0x70, 0x61, 0x07, 0xff, 0x38,
+	0xd0, 0x0d, 0x51, 0x94, 0x99, 0xbc, 0xc9, 0xd1, 0x79, 0x0f, 0xc7, 0x5d, 0xe8, 0x0a, 0x49, 0x65,
+	0x29, 0xf4, 0x09, 0x3a, 0x61, 0x65, 0x29, 0x3c, 0xa6, 0x59, 0x86, 0xbc, 0xe2, 0x5d, 0x59, 0x0a,
+	0x7f, 0xcb, 0xf8, 0x25, 0xf2, 0x4a, 0xb2, 0xca, 0x22, 0x7b, 0xd0, 0x13, 0x92, 0x72, 0x39, 0xa5,
+	0xb2, 0x22, 0xbc, 0xa5, 0xed, 0x63, 0x49, 0xee, 0x43, 0x17, 0xf3, 0x44, 0x39, 0xba, 0xda, 0xd1,
+	0xc1, 0x3c, 0x39, 0x96, 0xe4, 0x53, 0xe8, 0x73, 0x94, 0x25, 0xcf, 0xa7, 0x57, 0x34, 0x2b, 0xd1,
+	0xdb, 0x32, 0x25, 0x30, 0xd8, 0x2b, 0x05, 0x05, 0xbf, 0x39, 0xb0, 0x3d, 0xa1, 0x59, 0xa6, 0x27,
+	0x02, 0xdf, 0xdc, 0xed, 0x44, 0x96, 0xaa, 0xed, 0x55, 0x55, 0xd7, 0x9d, 0x69, 0x45, 0x6d, 0x75,
+	0xac, 0xbe, 0xa5, 0x76, 0xf0, 0x15, 0xf4, 0x55, 0xfe, 0x9f, 0xf3, 0xf4, 0x4d, 0x89, 0x27, 0x09,
+	0xf9, 0x0c, 0x76, 0x34, 0x89, 0x52, 0x03, 0xd3, 0x34, 0xb1, 0x59, 0xd4, 0x51, 0xc1, 0xf7, 0x70,
+	0x2f, 0x42, 0xf9, 0x5a, 0x8b, 0x13, 0x49, 0x2a, 0x51, 0xf1, 0xdf, 0x07, 0xd7, 0xc8, 0xb5, 0x5c,
+	0xd5, 0x33, 0xc0, 0x49, 0xa2, 0x06, 0xc0, 0x08, 0xd1, 0xd6, 0x25, 0x35, 0x46, 0xf0, 0xbb, 0x03,
+	0x24, 0x42, 0x69, 0x4a, 0x1a, 0xe9, 0xda, 0xa8, 0x9d, 0xee, 0x44, 0x62, 0x6d, 0x71, 0x9b, 0xd2,
+	0x6f, 0xe8, 0x33, 0xdb, 0xd2, 0xeb, 0xa5, 0xac, 0xe4, 0x31, 0xd6, 0x75, 0x36, 0x56, 0x10, 0xc2,
+	0x07, 0xc7, 0x45, 0x81, 0xb9, 0xbe, 0xc6, 0x5e, 0xb0, 0xd9, 0xdd, 0xc9, 0x78, 0xb0, 0x15, 0xb3,
+	0x5c, 0x62, 0x2e, 0xab, 0x61, 0xa9, 0xcd, 0xe0, 0x31, 0x0c, 0x22, 0x7e, 0xf5, 0x92, 0x5d, 0x62,
+	0xfe, 0x53, 0x89, 0xfc, 0x7a, 0x29, 0x85, 0xd9, 0xa7, 0x92, 0x62, 0x04, 0x3b, 0xc7, 0x5c, 0xa6,
+	0xe7, 0x34, 0x96, 0xd1, 0x05, 0x3d, 0xfa, 0xfa, 0x1b, 0x4d, 0x52, 0xff, 0x55, 0x81, 0x95, 0x15,
+	0x4c, 0x60, 0x58, 0x47, 0x4e, 0x4c, 0x8e, 0x75, 0xa1, 0x4d, 0x56, 0xfd, 0x05, 0xab, 0xa3, 0xbf,
+	0xba, 0xb0, 0xad, 0x0e, 0x19, 0x21, 0xbf, 0x4a, 0x63, 0x24, 0xdf, 0x41, 0xaf, 0xee, 0x45, 0xb2,
+	0x6b, 0xdd, 0x89, 0x56, 0x83, 0xfa, 0x1f, 0x37, 0xae, 0xf1, 0x45, 0x3b, 0xb4, 0xd4, 0xf2, 0xe7,
+	0x69, 0xb5, 0x7c, 0x5d, 0x98, 0xbf, 0x3b, 0x36, 0x0f, 0xcc, 0xb8, 0x7e, 0x60, 0xc6, 0x4f, 0xd5,
+	0xeb, 0x13, 0xb4, 0xc8, 0xd3, 0xea, 0xaa, 0x7e, 0x91, 0x0a, 0x69, 0x5e, 0x10, 0xdf, 0x6c, 0x52,
+	0x3d, 0x32, 0x5a, 0xb8, 0xd7, 0xa9, 0xbc, 0x50, 0x51, 0xfe, 0x7d, 0x2b, 0xc1, 0xf2, 0xd1, 0x09,
+	0x5a, 0xe4, 0xb9, 0xd5, 0x96, 0x13, 0xc6, 0x13, 0x96, 0x63, 0x42, 0x1e, 0xd8, 0x37, 0x7c, 0xb3,
+	0x69, 0x6f, 0xe1, 0x74, 0x02, 0xc3, 0x45, 0xf8, 0x29, 0x2d, 0xc5, 0x7f, 0xd8, 0xea, 0x07, 0x18,
+	0x36, 0xba, 0x9c, 0x7c, 0xb2, 0xba, 0x55, 0x63, 0x02, 0x6e, 0xd9, 0xeb, 0x5b, 0x70, 0x9f, 0xd5,
+	0xf1, 0xeb, 0xa5, 0xbe, 0x67, 0x39, 0x4c, 0xac, 0xa6, 0x31, 0x58, 0xe9, 0x6e, 0xb2, 0x6f, 0x45,
+	0x35, 0xfb, 0x7e, 0x3d, 0x85, 0x91, 0xf3, 0xb9, 0x43, 0x7e, 0x04, 0xb2, 0xa8, 0xd8, 0xf2, 0x81,
+	0xbd, 0xad, 0x6a, 0x7b, 0xcd, 0xaa, 0x2d, 0x96, 0x19, 0xb1, 0x4f, 0xc4, 0x2b, 0x9a, 0xa5, 0x49,
+	0x3d, 0x2b, 0xc4, 0xb3, 0x15, 0xb2, 0x07, 0xc8, 0xf7, 0x6f, 0x30, 0x7b, 0xc2, 0x58, 0x66, 0x2e,
+	0xd5, 0x96, 0x62, 0xf6, 0x0c, 0x65, 0x73, 0x42, 0xec, 0xec, 0xab, 0x73, 0xe6, 0xfb, 0xef, 0x71,
+	0x55, 0xcb, 0x82, 0xd6, 0x93, 0xde, 0x2f, 0x5d, 0xe5, 0x29, 0xce, 0xce, 0xba, 0x3a, 0xdd, 0x97,
+	0xff, 0x0e, 0x00, 0x2c, 0xd2, 0x24, 0x9b, 0x60, 0x09, 0x00, 0x00,
 }
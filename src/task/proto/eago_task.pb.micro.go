@@ -51,6 +51,10 @@ type TaskService interface {
 	KillTask(ctx context.Context, in *TaskUniqueId, opts ...client.CallOption) (*emptypb.Empty, error)
 	// PagedListTasks 列出所有任务-分页
 	PagedListTasks(ctx context.Context, in *proto1.QueryWithPage, opts ...client.CallOption) (*PagedTasks, error)
+	// SetWorkerCordoned 隔离或取消隔离Worker
+	SetWorkerCordoned(ctx context.Context, in *SetWorkerStateReq, opts ...client.CallOption) (*emptypb.Empty, error)
+	// SetWorkerPaused 暂停或恢复Worker
+	SetWorkerPaused(ctx context.Context, in *SetWorkerStateReq, opts ...client.CallOption) (*emptypb.Empty, error)
 	// SetResultStatus 设置任务结果状态
 	SetResultStatus(ctx context.Context, in *SetResultStatusReq, opts ...client.CallOption) (*emptypb.Empty, error)
 	// GetResult 查看任务结果
@@ -61,6 +65,8 @@ type TaskService interface {
 	PagedListSchedules(ctx context.Context, in *proto1.QueryWithPage, opts ...client.CallOption) (*PagedSchedules, error)
 	// IsValidSrvToken 判断SrvToken是否合法
 	IsValidSrvToken(ctx context.Context, in *SrvTokenQuery, opts ...client.CallOption) (*wrapperspb.BoolValue, error)
+	// GetArtifactContent 按sha256获得制品内容
+	GetArtifactContent(ctx context.Context, in *ArtifactSha256, opts ...client.CallOption) (*ArtifactContent, error)
 }
 
 type taskService struct {
@@ -105,6 +111,26 @@ func (c *taskService) PagedListTasks(ctx context.Context, in *proto1.QueryWithPa
 	return out, nil
 }
 
+func (c *taskService) SetWorkerCordoned(ctx context.Context, in *SetWorkerStateReq, opts ...client.CallOption) (*emptypb.Empty, error) {
+	req := c.c.NewRequest(c.name, "TaskService.SetWorkerCordoned", in)
+	out := new(emptypb.Empty)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskService) SetWorkerPaused(ctx context.Context, in *SetWorkerStateReq, opts ...client.CallOption) (*emptypb.Empty, error) {
+	req := c.c.NewRequest(c.name, "TaskService.SetWorkerPaused", in)
+	out := new(emptypb.Empty)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taskService) SetResultStatus(ctx context.Context, in *SetResultStatusReq, opts ...client.CallOption) (*emptypb.Empty, error) {
 	req := c.c.NewRequest(c.name, "TaskService.SetResultStatus", in)
 	out := new(emptypb.Empty)
@@ -196,6 +222,16 @@ func (c *taskService) IsValidSrvToken(ctx context.Context, in *SrvTokenQuery, op
 	return out, nil
 }
 
+func (c *taskService) GetArtifactContent(ctx context.Context, in *ArtifactSha256, opts ...client.CallOption) (*ArtifactContent, error) {
+	req := c.c.NewRequest(c.name, "TaskService.GetArtifactContent", in)
+	out := new(ArtifactContent)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for TaskService service
 
 type TaskServiceHandler interface {
@@ -205,6 +241,10 @@ type TaskServiceHandler interface {
 	KillTask(context.Context, *TaskUniqueId, *emptypb.Empty) error
 	// PagedListTasks 列出所有任务-分页
 	PagedListTasks(context.Context, *proto1.QueryWithPage, *PagedTasks) error
+	// SetWorkerCordoned 隔离或取消隔离Worker
+	SetWorkerCordoned(context.Context, *SetWorkerStateReq, *emptypb.Empty) error
+	// SetWorkerPaused 暂停或恢复Worker
+	SetWorkerPaused(context.Context, *SetWorkerStateReq, *emptypb.Empty) error
 	// SetResultStatus 设置任务结果状态
 	SetResultStatus(context.Context, *SetResultStatusReq, *emptypb.Empty) error
 	// GetResult 查看任务结果
@@ -215,6 +255,8 @@ type TaskServiceHandler interface {
 	PagedListSchedules(context.Context, *proto1.QueryWithPage, *PagedSchedules) error
 	// IsValidSrvToken 判断SrvToken是否合法
 	IsValidSrvToken(context.Context, *SrvTokenQuery, *wrapperspb.BoolValue) error
+	// GetArtifactContent 按sha256获得制品内容
+	GetArtifactContent(context.Context, *ArtifactSha256, *ArtifactContent) error
 }
 
 func RegisterTaskServiceHandler(s server.Server, hdlr TaskServiceHandler, opts ...server.HandlerOption) error {
@@ -222,11 +264,14 @@ func RegisterTaskServiceHandler(s server.Server, hdlr TaskServiceHandler, opts .
 		CallTask(ctx context.Context, in *CallTaskReq, out *TaskUniqueId) error
 		KillTask(ctx context.Context, in *TaskUniqueId, out *emptypb.Empty) error
 		PagedListTasks(ctx context.Context, in *proto1.QueryWithPage, out *PagedTasks) error
+		SetWorkerCordoned(ctx context.Context, in *SetWorkerStateReq, out *emptypb.Empty) error
+		SetWorkerPaused(ctx context.Context, in *SetWorkerStateReq, out *emptypb.Empty) error
 		SetResultStatus(ctx context.Context, in *SetResultStatusReq, out *emptypb.Empty) error
 		GetResult(ctx context.Context, in *TaskUniqueId, out *Result) error
 		AppendTaskLog(ctx context.Context, stream server.Stream) error
 		PagedListSchedules(ctx context.Context, in *proto1.QueryWithPage, out *PagedSchedules) error
 		IsValidSrvToken(ctx context.Context, in *SrvTokenQuery, out *wrapperspb.BoolValue) error
+		GetArtifactContent(ctx context.Context, in *ArtifactSha256, out *ArtifactContent) error
 	}
 	type TaskService struct {
 		taskService
@@ -251,6 +296,14 @@ func (h *taskServiceHandler) PagedListTasks(ctx context.Context, in *proto1.Quer
 	return h.TaskServiceHandler.PagedListTasks(ctx, in, out)
 }
 
+func (h *taskServiceHandler) SetWorkerCordoned(ctx context.Context, in *SetWorkerStateReq, out *emptypb.Empty) error {
+	return h.TaskServiceHandler.SetWorkerCordoned(ctx, in, out)
+}
+
+func (h *taskServiceHandler) SetWorkerPaused(ctx context.Context, in *SetWorkerStateReq, out *emptypb.Empty) error {
+	return h.TaskServiceHandler.SetWorkerPaused(ctx, in, out)
+}
+
 func (h *taskServiceHandler) SetResultStatus(ctx context.Context, in *SetResultStatusReq, out *emptypb.Empty) error {
 	return h.TaskServiceHandler.SetResultStatus(ctx, in, out)
 }
@@ -311,3 +364,7 @@ func (h *taskServiceHandler) PagedListSchedules(ctx context.Context, in *proto1.
 func (h *taskServiceHandler) IsValidSrvToken(ctx context.Context, in *SrvTokenQuery, out *wrapperspb.BoolValue) error {
 	return h.TaskServiceHandler.IsValidSrvToken(ctx, in, out)
 }
+
+func (h *taskServiceHandler) GetArtifactContent(ctx context.Context, in *ArtifactSha256, out *ArtifactContent) error {
+	return h.TaskServiceHandler.GetArtifactContent(ctx, in, out)
+}
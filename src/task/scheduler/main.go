@@ -27,6 +27,7 @@ func main() {
 		EtcdPassword(schConf.EtcdPassword),
 		RegisterTtl(schConf.SchedulerRegisterTtl),
 		TaskRpcRegisterKey(schConf.Const.RpcRegisterKey),
+		MetricsListen(schConf.SchMetricsListen),
 		Logger(schLg),
 	)
 
@@ -20,6 +20,8 @@ type Options struct {
 
 	RegisterTtl int64
 
+	MetricsListen string
+
 	Logger *logger.Logger
 }
 
@@ -34,6 +36,8 @@ func newOptions(opts ...Option) Options {
 		TaskRpcRetries:     0,
 
 		RegisterTtl: 10,
+
+		MetricsListen: "",
 	}
 
 	for _, o := range opts {
@@ -94,6 +98,13 @@ func RegisterTtl(ttl int64) Option {
 	}
 }
 
+// MetricsListen 设置Prometheus指标监听地址，为空时不启用
+func MetricsListen(addr string) Option {
+	return func(o *Options) {
+		o.MetricsListen = addr
+	}
+}
+
 // Logger 设置Logger
 func Logger(in *logger.Logger) Option {
 	return func(o *Options) {
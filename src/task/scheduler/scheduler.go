@@ -4,6 +4,7 @@ import (
 	"context"
 	"eago/common/global"
 	"eago/common/logger"
+	"eago/common/metrics"
 	commonpb "eago/common/proto"
 	taskpb "eago/task/proto"
 	"encoding/json"
@@ -42,7 +43,7 @@ func NewScheduler(ctx context.Context, options ...Option) *scheduler {
 		registry.Addrs(opts.EtcdAddresses...),
 		etcdv3.Auth(opts.EtcdUsername, opts.EtcdPassword),
 	)
-	cli := micro.NewService(micro.Registry(etcdReg))
+	cli := micro.NewService(micro.Registry(etcdReg), micro.WrapClient(metrics.NewClientWrapper()))
 	etcdCli, err := clientv3.New(clientv3.Config{
 		Endpoints:   opts.EtcdAddresses,
 		DialTimeout: 5 * time.Second,
@@ -75,6 +76,8 @@ func (s *scheduler) Start() error {
 
 	// 循环创建计划任务
 	for _, sch := range s.listScheduleTasks() {
+		// 闭包中使用，需要复制循环变量
+		sch := sch
 		// 创建计划任务
 		err := s.cron.AddFunc(sch.Expression, func() {
 			ctx := context.Background()
@@ -86,6 +89,7 @@ func (s *scheduler) Start() error {
 			}
 			// 调用任务
 			rsp, err := s.taskCli.CallTask(ctx, req)
+			metrics.ObserveSchedulerFire(sch.TaskCodename, err)
 			if err != nil {
 				s.logger.ErrorWithFields(logger.Fields{
 					"task_codename": sch.TaskCodename,
@@ -126,6 +130,16 @@ func (s *scheduler) Start() error {
 	s.register()
 	s.cron.Start()
 
+	// 暴露Prometheus指标
+	go func() {
+		if err := metrics.Serve(s.ctx, s.opts.MetricsListen); err != nil {
+			s.logger.ErrorWithFields(logger.Fields{
+				"address": s.opts.MetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
 	for {
 		select {
 		case <-s.ctx.Done():
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"eago/common/audit"
 	"eago/common/logger"
 	"eago/common/orm"
 	"eago/common/redis"
@@ -17,15 +18,16 @@ import (
 var (
 	task service.EagoSrv
 
-	taskDao   *dao.Dao
-	taskRedis *redis.RedisTool
+	taskDao     *dao.Dao
+	taskAuditor audit.Auditor
+	taskRedis   *redis.RedisTool
 
 	taskConf *conf.Conf
 	taskLg   *logger.Logger
 )
 
 func main() {
-	task = NewTaskSrv(taskDao, taskRedis, taskConf, taskLg)
+	task = NewTaskSrv(taskDao, taskAuditor, taskRedis, taskConf, taskLg)
 
 	e := make(chan error)
 	go func() {
@@ -84,7 +86,7 @@ func init() {
 	}
 	taskLg = lg
 
-	taskDao = dao.NewDao(orm.NewMysqlGorm(
+	db := orm.NewMysqlGorm(
 		taskConf.MysqlAddress,
 		taskConf.MysqlUser,
 		taskConf.MysqlPassword,
@@ -92,7 +94,11 @@ func init() {
 		orm.MysqlMaxIdleConns(taskConf.MysqlMaxIdleConns),
 		orm.MysqlMaxOpenConns(taskConf.MysqlMaxOpenConns),
 		orm.UsingOpentracingPlugin(),
-	), taskConf, taskLg)
+		orm.UsingDbStatsMetrics("mysql"),
+	)
+	taskDao = dao.NewDao(db, taskConf, taskLg)
+	// 审计日志与业务数据使用同一数据库
+	taskAuditor = audit.NewAuditor(db, taskConf.Const.ServiceName, audit.Logger(taskLg))
 
 	taskRedis = redis.NewRedisTool(
 		taskConf.RedisAddress,
@@ -100,5 +106,6 @@ func init() {
 		taskConf.Const.ServiceName,
 		taskConf.RedisDb,
 		redis.UsingOpentracingHook(),
+		redis.UsingPoolStatsMetrics("redis"),
 	)
 }
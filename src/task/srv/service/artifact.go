@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/orm"
+	"eago/task/conf/msg"
+	taskpb "eago/task/proto"
+)
+
+func (taskSrv *TaskService) GetArtifactContent(
+	ctx context.Context, req *taskpb.ArtifactSha256, rsp *taskpb.ArtifactContent,
+) error {
+	taskSrv.logger.InfoWithFields(logger.Fields{
+		"sha256": req.Sha256,
+	}, "taskSrv.GetArtifactContent called.")
+	defer taskSrv.logger.Info("taskSrv.GetArtifactContent end.")
+
+	// 只允许获取已登记的制品，并按其存储方式读取
+	a, err := taskSrv.dao.GetArtifact(ctx, orm.Query{"sha256=?": req.Sha256})
+	if err != nil || a == nil || a.Id == 0 {
+		m := msg.MsgArtifactNotFoundFailed
+		if err != nil {
+			m = msg.MsgTaskDaoErr.SetError(err)
+		}
+		taskSrv.logger.ErrorWithFields(
+			m.ToLoggerFields().Append("sha256", req.Sha256),
+			"An error occurred while dao.GetArtifact in taskSrv.GetArtifactContent.",
+		)
+		return m.ToMicroErr()
+	}
+
+	content, err := taskSrv.dao.GetArtifactContent(ctx, a)
+	if err != nil {
+		m := msg.MsgArtifactStorageErr.SetError(err)
+		taskSrv.logger.ErrorWithFields(
+			m.ToLoggerFields().Append("sha256", req.Sha256),
+			"An error occurred while dao.GetArtifactContent in taskSrv.GetArtifactContent.",
+		)
+		return m.ToMicroErr()
+	}
+
+	rsp.Sha256 = req.Sha256
+	rsp.Content = content
+	return nil
+}
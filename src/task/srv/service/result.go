@@ -6,6 +6,8 @@ import (
 	"eago/task/conf/msg"
 	"eago/task/dto"
 	taskpb "eago/task/proto"
+	"encoding/json"
+	"errors"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -26,51 +28,63 @@ func (taskSrv *TaskService) SetResultStatus(
 		return m.ToMicroErr()
 	}
 
-	// 取数据库中任务结果记录
-	obj, err := taskSrv.dao.GetResult(ctx, part, resId)
-	if err != nil {
-		m := msg.MsgTaskDaoErr.SetError(err)
-		taskSrv.logger.ErrorWithFields(
-			m.ToLoggerFields().Append("query", req),
-			"An error occurred while dao.GetResult in taskSrv.SetResultStatus.",
-		)
-		return m.ToMicroErr()
-	}
-	// 找不到数据的处理
-	if obj == nil || obj.Id < 1 {
-		m := msg.MsgTaskDaoErr.SetDetail("Result object not found.")
+	// 任务返回值必须是合法的JSON
+	if len(req.ReturnValue) > 0 && !json.Valid(req.ReturnValue) {
+		m := msg.MsgSetResultReturnValueInvalidFailed
 		f := m.ToLoggerFields()
 		f["partition"] = part
 		f["result_id"] = resId
-		taskSrv.logger.ErrorWithFields(f, "An error occurred while dao.GetResult in taskSrv.SetResultStatus.")
+		taskSrv.logger.ErrorWithFields(f, "An error occurred while json.Valid in taskSrv.SetResultStatus.")
 		return m.ToMicroErr()
 	}
 
-	// 判断任务记录，无法结束不是在执行的状态，返回错误
-	if obj.Status <= dto.TaskResultStatusSuccessEnd {
-		m := msg.MsgSetResultStatusInvalidStatusFailed
-		f := m.ToLoggerFields()
-		f["partition"] = part
-		f["result_id"] = resId
-		taskSrv.logger.ErrorWithFields(f, "An error occurred while dao.GetResult in taskSrv.SetResultStatus.")
-	}
+	// 按状态机变更状态并记录状态变更，拒绝不合法的状态变更，如已结束的任务再变为运行中
+	// 来源不信任调用方上报的值，由dao取任务结果记录的执行Worker
+	if err = taskSrv.dao.SetResultStatus(ctx, part, resId, req.Status, ""); err != nil {
+		if errors.Is(err, dto.ErrIllegalResultStatusTransition) {
+			m := msg.MsgSetResultStatusIllegalFailed.SetError(err)
+			f := m.ToLoggerFields()
+			f["partition"] = part
+			f["result_id"] = resId
+			f["reported_source"] = req.Source
+			taskSrv.logger.WarnWithFields(f, m.GetMsg())
+			return m.ToMicroErr()
+		}
+		if errors.Is(err, dto.ErrResultPartitionNotFound) {
+			m := msg.MsgSetResultStatusPartNotFoundFailed.SetError(err)
+			f := m.ToLoggerFields()
+			f["partition"] = part
+			f["result_id"] = resId
+			f["reported_source"] = req.Source
+			taskSrv.logger.WarnWithFields(f, m.GetMsg())
+			return m.ToMicroErr()
+		}
 
-	// Status小于等于worker.TaskStatusSuccessEnd，则说明任务已结束
-	end := false
-	if int(req.Status) <= dto.TaskResultStatusSuccessEnd {
-		end = true
-	}
-	if err = taskSrv.dao.SetResultStatus(ctx, part, resId, req.Status, end); err != nil {
 		m := msg.MsgTaskDaoErr.SetError(err)
 		f := m.ToLoggerFields()
 		f["partition"] = part
 		f["result_id"] = resId
+		f["reported_source"] = req.Source
 		taskSrv.logger.ErrorWithFields(
 			f, "An error occurred while dao.SetResultStatus in taskSrv.SetResultStatus.",
 		)
 		return m.ToMicroErr()
 	}
 
+	// 写入任务返回值
+	if len(req.ReturnValue) > 0 {
+		if err = taskSrv.dao.SetResultReturnValue(ctx, part, resId, string(req.ReturnValue)); err != nil {
+			m := msg.MsgTaskDaoErr.SetError(err)
+			f := m.ToLoggerFields()
+			f["partition"] = part
+			f["result_id"] = resId
+			taskSrv.logger.ErrorWithFields(
+				f, "An error occurred while dao.SetResultReturnValue in taskSrv.SetResultStatus.",
+			)
+			return m.ToMicroErr()
+		}
+	}
+
 	taskSrv.logger.DebugWithFields(logger.Fields{
 		"partition": part,
 		"result_id": resId,
@@ -111,6 +125,9 @@ func (taskSrv *TaskService) GetResult(ctx context.Context, req *taskpb.TaskUniqu
 	if obj.EndAt != nil {
 		rsp.EndAt = obj.EndAt.String()
 	}
+	if obj.ReturnValue != nil {
+		rsp.ReturnValue = *obj.ReturnValue
+	}
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"eago/common/audit"
 	"eago/common/logger"
 	"eago/common/redis"
 	"eago/task/biz"
@@ -9,8 +10,9 @@ import (
 )
 
 type TaskService struct {
-	dao   *dao.Dao
-	redis *redis.RedisTool
+	dao     *dao.Dao
+	auditor audit.Auditor
+	redis   *redis.RedisTool
 
 	biz *biz.Biz
 
@@ -20,11 +22,12 @@ type TaskService struct {
 
 // NewTaskService 新建Task服务
 func NewTaskService(
-	dao *dao.Dao, redis *redis.RedisTool, biz *biz.Biz, conf *conf.Conf, logger *logger.Logger,
+	dao *dao.Dao, auditor audit.Auditor, redis *redis.RedisTool, biz *biz.Biz, conf *conf.Conf, logger *logger.Logger,
 ) *TaskService {
 	return &TaskService{
-		dao:   dao,
-		redis: redis,
+		dao:     dao,
+		auditor: auditor,
+		redis:   redis,
 
 		biz: biz,
 
@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"eago/common/audit"
+	"eago/common/json_schema"
 	"eago/common/logger"
 	"eago/common/orm"
 	commonpb "eago/common/proto"
 	"eago/task/conf/msg"
+	"eago/task/dto"
 	"eago/task/model"
 	taskpb "eago/task/proto"
+	"encoding/json"
+	"errors"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -54,8 +59,31 @@ func (taskSrv *TaskService) CallTask(ctx context.Context, req *taskpb.CallTaskRe
 	defer taskSrv.logger.Info("taskSrv.CallTask end.")
 
 	tId, err := taskSrv.biz.CallTask(ctx, req.TaskCodename, string(req.Arguments), req.Caller, req.Timeout)
+	// 记录审计日志，参数为JSON时按对象记录以便脱敏，写入失败时已记录日志
+	var args interface{} = string(req.Arguments)
+	if json.Valid(req.Arguments) {
+		args = json.RawMessage(req.Arguments)
+	}
+	_ = taskSrv.auditor.Record(ctx, &audit.AuditLog{
+		Actor:      req.Caller,
+		Action:     "RPC CallTask",
+		Resource:   "tasks",
+		ResourceId: req.TaskCodename,
+		After: audit.Marshal(map[string]interface{}{
+			"arguments":      args,
+			"timeout":        req.Timeout,
+			"task_unique_id": tId,
+		}),
+		Succeeded: err == nil,
+	})
 	if err != nil {
 		m := msg.MsgCallTaskFailed.SetError(err)
+		// 参数校验未通过时返回字段级别的错误
+		if fe, ok := json_schema.IsFieldErrors(err); ok {
+			m = msg.MsgCallTaskArgumentsFailed.SetError(map[string]string(fe))
+		} else if errors.Is(err, dto.ErrInvalidFormalParams) {
+			m = msg.MsgInvalidFormalParamsFailed.SetError(err)
+		}
 		f := m.ToLoggerFields()
 		f["task_codename"] = req.TaskCodename
 		f["arguments"] = req.Arguments
@@ -79,7 +107,15 @@ func (taskSrv *TaskService) KillTask(ctx context.Context, req *taskpb.TaskUnique
 	}, "taskSrv.KillTask called.")
 	defer taskSrv.logger.Info("taskSrv.KillTask end.")
 
-	if err := taskSrv.biz.KillTask(ctx, req.TaskUniqueId); err != nil {
+	err := taskSrv.biz.KillTask(ctx, req.TaskUniqueId)
+	// 记录审计日志，写入失败时已记录日志
+	_ = taskSrv.auditor.Record(ctx, &audit.AuditLog{
+		Action:     "RPC KillTask",
+		Resource:   "results",
+		ResourceId: req.TaskUniqueId,
+		Succeeded:  err == nil,
+	})
+	if err != nil {
 		m := msg.MsgKillTaskFailed.SetError(err)
 		taskSrv.logger.ErrorWithFields(
 			m.ToLoggerFields().Append("task_unique_id", req.TaskUniqueId),
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/task/biz"
+	"eago/task/conf/msg"
+	taskpb "eago/task/proto"
+	"errors"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (taskSrv *TaskService) SetWorkerCordoned(
+	ctx context.Context, req *taskpb.SetWorkerStateReq, _ *emptypb.Empty,
+) error {
+	taskSrv.logger.InfoWithFields(logger.Fields{
+		"worker_id": req.WorkerId,
+		"cordoned":  req.Value,
+	}, "taskSrv.SetWorkerCordoned called.")
+	defer taskSrv.logger.Info("taskSrv.SetWorkerCordoned end.")
+
+	if err := taskSrv.biz.SetWorkerCordoned(ctx, req.WorkerId, req.Value); err != nil {
+		m := msg.MsgSetWorkerStateFailed.SetError(err)
+		if errors.Is(err, biz.ErrWorkerNotFound) {
+			m = msg.MsgWorkerNotFoundFailed
+		}
+		taskSrv.logger.ErrorWithFields(
+			m.ToLoggerFields().Append("worker_id", req.WorkerId),
+			"An error occurred while biz.SetWorkerCordoned in taskSrv.SetWorkerCordoned.",
+		)
+		return m.ToMicroErr()
+	}
+
+	return nil
+}
+
+func (taskSrv *TaskService) SetWorkerPaused(
+	ctx context.Context, req *taskpb.SetWorkerStateReq, _ *emptypb.Empty,
+) error {
+	taskSrv.logger.InfoWithFields(logger.Fields{
+		"worker_id": req.WorkerId,
+		"paused":    req.Value,
+	}, "taskSrv.SetWorkerPaused called.")
+	defer taskSrv.logger.Info("taskSrv.SetWorkerPaused end.")
+
+	if err := taskSrv.biz.SetWorkerPaused(ctx, req.WorkerId, req.Value); err != nil {
+		m := msg.MsgSetWorkerStateFailed.SetError(err)
+		if errors.Is(err, biz.ErrWorkerNotFound) {
+			m = msg.MsgWorkerNotFoundFailed
+		}
+		taskSrv.logger.ErrorWithFields(
+			m.ToLoggerFields().Append("worker_id", req.WorkerId),
+			"An error occurred while biz.SetWorkerPaused in taskSrv.SetWorkerPaused.",
+		)
+		return m.ToMicroErr()
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"eago/common/audit"
 	"eago/common/logger"
+	"eago/common/metrics"
 	"eago/common/redis"
 	"eago/common/tracer"
 	"eago/task/biz"
@@ -34,7 +36,7 @@ type taskSrv struct {
 	cancelFunc context.CancelFunc
 }
 
-func NewTaskSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) *taskSrv {
+func NewTaskSrv(dao *dao.Dao, auditor audit.Auditor, redis *redis.RedisTool, conf *conf.Conf, logger *logger.Logger) *taskSrv {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// 生成etcdRegistry
@@ -63,12 +65,13 @@ func NewTaskSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 		micro.RegisterInterval(conf.MicroRegisterInterval),
 		micro.Context(ctx),
 		micro.WrapHandler(opentracing.NewHandlerWrapper(_tracer.GetTracer())),
+		micro.WrapHandler(metrics.NewHandlerWrapper()),
 	)
 
 	// 生成Biz
 	_biz := biz.NewBiz(dao, redis, conf, logger)
 
-	_ = taskpb.RegisterTaskServiceHandler(_srv.Server(), service.NewTaskService(dao, redis, _biz, conf, logger))
+	_ = taskpb.RegisterTaskServiceHandler(_srv.Server(), service.NewTaskService(dao, auditor, redis, _biz, conf, logger))
 
 	return &taskSrv{
 		srv: _srv,
@@ -90,6 +93,21 @@ func NewTaskSrv(dao *dao.Dao, redis *redis.RedisTool, conf *conf.Conf, logger *l
 func (ts *taskSrv) Start() error {
 	ts.logger.Info("Starting task srv ...")
 
+	// 暴露Prometheus指标
+	go func() {
+		if err := metrics.Serve(ts.ctx, ts.conf.SrvMetricsListen); err != nil {
+			ts.logger.ErrorWithFields(logger.Fields{
+				"address": ts.conf.SrvMetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
+	// 启动结果分区管理
+	go ts.biz.RunResultPartitionManager(ts.ctx)
+	// 启动任务目录同步
+	go ts.biz.RunTaskCatalogSync(ts.ctx)
+
 	return ts.srv.Run()
 }
 
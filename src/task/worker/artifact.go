@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"eago/common/logger"
+	"eago/common/utils"
+	taskpb "eago/task/proto"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// fetchArtifact 按sha256获得制品内容，优先使用本地缓存，缓存不存在或损坏时从Task模块拉取
+func (wk *worker) fetchArtifact(ctx context.Context, sha256 string) ([]byte, error) {
+	if filepath.Base(sha256) != sha256 {
+		return nil, errors.New("invalid artifact sha256")
+	}
+
+	wk.artifactMu.Lock()
+	defer wk.artifactMu.Unlock()
+
+	cachePath := filepath.Join(wk.opts.ArtifactCacheDir, sha256)
+	if content, err := ioutil.ReadFile(cachePath); err == nil {
+		if utils.GenSha256HashCode(string(content)) == sha256 {
+			return content, nil
+		}
+		wk.logger.WarnWithFields(logger.Fields{
+			"sha256": sha256,
+		}, "Cached artifact checksum mismatch, fetch it again.")
+	}
+
+	rsp, err := wk.taskCli.GetArtifactContent(ctx, &taskpb.ArtifactSha256{Sha256: sha256})
+	if err != nil {
+		wk.logger.ErrorWithFields(logger.Fields{
+			"sha256": sha256,
+			"error":  err,
+		}, "An error occurred while taskServiceClient.GetArtifactContent.")
+		return nil, err
+	}
+	if utils.GenSha256HashCode(string(rsp.Content)) != sha256 {
+		return nil, errors.New("artifact checksum mismatch")
+	}
+
+	// 写入缓存，失败仅记录日志
+	if err = writeArtifactCache(cachePath, rsp.Content); err != nil {
+		wk.logger.WarnWithFields(logger.Fields{
+			"sha256": sha256,
+			"error":  err,
+		}, "An error occurred while writeArtifactCache, skipped it.")
+	}
+
+	return rsp.Content, nil
+}
+
+// writeArtifactCache 先写临时文件再改名，避免其他Worker进程读到写了一半的缓存
+func writeArtifactCache(cachePath string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(cachePath), ".tmp-"+filepath.Base(cachePath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), cachePath)
+}
@@ -7,6 +7,7 @@ import (
 	"eago/task/worker"
 	workerpb "eago/task/worker/proto"
 	"encoding/json"
+	"fmt"
 	"github.com/coreos/etcd/clientv3"
 	"google.golang.org/grpc"
 	"time"
@@ -38,19 +39,28 @@ func NewWorkerCli(etcdAddrs []string, _logger *logger.Logger) *WorkerClient {
 }
 
 // List 列出所有活跃的Worker
-func (wkCli *WorkerClient) List(ctx context.Context) (workers []*dto.WorkerInfo) {
-	resp, err := wkCli.etcdCli.Get(ctx, worker.WorkerRegisterKeyPrefix, clientv3.WithPrefix())
+func (wkCli *WorkerClient) List(ctx context.Context) []*dto.WorkerInfo {
+	workers, err := wkCli.ListWithError(ctx)
 	if err != nil {
 		wkCli.logger.WarnWithFields(logger.Fields{
 			"error": err,
-		}, "An error occurred while etcdCli.GetUser in WorkerClient.List, skipped it.")
-		return
+		}, "An error occurred while etcdCli.Get in WorkerClient.List, skipped it.")
+	}
+
+	return workers
+}
+
+// ListWithError 列出所有活跃的Worker，注册中心不可用时返回错误，便于调用方区分没有Worker和查询失败
+func (wkCli *WorkerClient) ListWithError(ctx context.Context) (workers []*dto.WorkerInfo, err error) {
+	resp, err := wkCli.etcdCli.Get(ctx, worker.WorkerRegisterKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
 	}
 
 	for _, ev := range resp.Kvs {
 		wk := &dto.WorkerInfo{}
 
-		if err = json.Unmarshal(ev.Value, wk); err != nil {
+		if err := json.Unmarshal(ev.Value, wk); err != nil {
 			wkCli.logger.WarnWithFields(logger.Fields{
 				"error": err,
 			}, "An error occurred while json.Unmarshal in WorkerClient.List, skipped it.")
@@ -59,7 +69,7 @@ func (wkCli *WorkerClient) List(ctx context.Context) (workers []*dto.WorkerInfo)
 		workers = append(workers, wk)
 	}
 
-	return
+	return workers, nil
 }
 
 // ListByModular 按模块名列出所有活跃的Worker
@@ -87,18 +97,18 @@ func (wkCli *WorkerClient) GetWorkerById(ctx context.Context, wkId string) *dto.
 	return nil
 }
 
-// CallTask 调用任务
+// CallTask 调用任务，返回Worker的应答
 func (wkCli *WorkerClient) CallTask(
 	ctx context.Context,
 	wk *dto.WorkerInfo,
 	codename, uniqueId, arguments, caller string,
 	timeout int64,
 	startTimestamp int64,
-) error {
+) (*workerpb.CallTaskAck, error) {
 	// 连接Worker并获取worker grpc客户端
 	cli, err := wkCli.getWorkerCli(wk)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := &workerpb.CallTaskReq{
@@ -110,11 +120,16 @@ func (wkCli *WorkerClient) CallTask(
 		Timestamp:    startTimestamp,
 	}
 	// 调用 TaskWorkerService.CallTask
-	if _, err = cli.CallTask(ctx, req); err != nil {
-		return err
+	ack, err := cli.CallTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// 旧版本Worker应答为空，视为已送达
+	if ack.TaskUniqueId != "" && ack.TaskUniqueId != uniqueId {
+		return nil, fmt.Errorf("worker acknowledged unexpected task unique id %s", ack.TaskUniqueId)
 	}
 
-	return nil
+	return ack, nil
 }
 
 // KillTask 调用任务
@@ -137,6 +152,46 @@ func (wkCli *WorkerClient) KillTask(ctx context.Context, wk *dto.WorkerInfo, tas
 	return nil
 }
 
+// SetCordoned 设置Worker隔离状态
+func (wkCli *WorkerClient) SetCordoned(ctx context.Context, wk *dto.WorkerInfo, cordoned bool) error {
+	// 连接Worker并获取worker grpc客户端
+	cli, err := wkCli.getWorkerCli(wk)
+	if err != nil {
+		return err
+	}
+
+	req := &workerpb.SetStateReq{
+		Value:     cordoned,
+		Timestamp: time.Now().Unix(),
+	}
+	// 调用 TaskWorkerService.SetCordoned
+	if _, err = cli.SetCordoned(ctx, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetPaused 设置Worker暂停状态
+func (wkCli *WorkerClient) SetPaused(ctx context.Context, wk *dto.WorkerInfo, paused bool) error {
+	// 连接Worker并获取worker grpc客户端
+	cli, err := wkCli.getWorkerCli(wk)
+	if err != nil {
+		return err
+	}
+
+	req := &workerpb.SetStateReq{
+		Value:     paused,
+		Timestamp: time.Now().Unix(),
+	}
+	// 调用 TaskWorkerService.SetPaused
+	if _, err = cli.SetPaused(ctx, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // getWorkerCli 连接Worker并获取worker grpc客户端
 func (wkCli *WorkerClient) getWorkerCli(wk *dto.WorkerInfo) (workerpb.TaskWorkerServiceClient, error) {
 	conn, err := grpc.Dial(wk.Address, grpc.WithInsecure())
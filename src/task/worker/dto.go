@@ -3,4 +3,5 @@ package worker
 const (
 	defaultWorkerResultLogBufferSize = 500
 	defaultWorkerRegisterTtl         = 10
+	defaultWorkerHeartbeatInterval   = 10
 )
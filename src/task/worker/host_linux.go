@@ -0,0 +1,55 @@
+//go:build linux
+// +build linux
+
+package worker
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// hostLoadAvg 读取/proc/loadavg获得主机1、5、15分钟平均负载
+func hostLoadAvg() (load [3]float64) {
+	b, err := ioutil.ReadFile("/proc/loadavg")
+	if err != nil {
+		return
+	}
+
+	fields := strings.Fields(string(b))
+	for i := 0; i < len(load) && i < len(fields); i++ {
+		load[i], _ = strconv.ParseFloat(fields[i], 64)
+	}
+	return
+}
+
+// hostMemory 读取/proc/meminfo获得主机总内存及可用内存，单位为字节
+func hostMemory() (total, available uint64) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+		case "MemAvailable:":
+			available = kb * 1024
+		}
+	}
+	return
+}
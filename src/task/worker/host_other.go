@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package worker
+
+// hostLoadAvg 非Linux平台暂不支持获取主机负载
+func hostLoadAvg() (load [3]float64) {
+	return
+}
+
+// hostMemory 非Linux平台暂不支持获取主机内存
+func hostMemory() (total, available uint64) {
+	return
+}
@@ -2,7 +2,11 @@ package worker
 
 import (
 	"eago/common/logger"
+	"eago/task/dto"
 	"github.com/go-basic/ipv4"
+	"os"
+	"path/filepath"
+	"runtime/debug"
 )
 
 const WorkerRegisterKeyPrefix = "/td/eago/workers"
@@ -19,11 +23,20 @@ type Options struct {
 
 	MultiInstance bool
 
-	RegisterTtl int64
+	RegisterTtl       int64
+	HeartbeatInterval int64
+	BuildVersion      string
 
 	PrintResultLog      bool
 	ResultLogBufferSize uint
 
+	EnableScriptTask   bool
+	ScriptWorkDir      string
+	ScriptInterpreters map[string]string
+	ArtifactCacheDir   string
+
+	MetricsListen string
+
 	Logger *logger.Logger
 }
 
@@ -40,10 +53,22 @@ func newOptions(opts ...Option) Options {
 
 		MultiInstance: true,
 
-		RegisterTtl: defaultWorkerRegisterTtl,
+		RegisterTtl:       defaultWorkerRegisterTtl,
+		HeartbeatInterval: defaultWorkerHeartbeatInterval,
+		BuildVersion:      defaultBuildVersion(),
 
 		PrintResultLog:      false,
 		ResultLogBufferSize: defaultWorkerResultLogBufferSize,
+
+		EnableScriptTask: false,
+		ScriptWorkDir:    os.TempDir(),
+		ScriptInterpreters: map[string]string{
+			dto.ScriptInterpreterBash:   "bash",
+			dto.ScriptInterpreterPython: "python3",
+		},
+		ArtifactCacheDir: filepath.Join(os.TempDir(), "eago-artifacts"),
+
+		MetricsListen: "",
 	}
 
 	for _, o := range opts {
@@ -57,6 +82,14 @@ func newOptions(opts ...Option) Options {
 	return opt
 }
 
+// defaultBuildVersion 从构建信息中获得主模块版本
+func defaultBuildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+	return ""
+}
+
 type Option func(o *Options)
 
 // EtcdAddresses 设置注册中心地址
@@ -129,6 +162,56 @@ func ResultLogBufferSize(size uint) Option {
 	}
 }
 
+// EnableScriptTask 设置是否启用内置脚本任务，启用后Worker可执行Task模块中存储的Bash/Python脚本
+// 脚本以Worker进程的系统用户运行且不做隔离，需要隔离时应以低权限用户或在容器中运行Worker
+func EnableScriptTask(b bool) Option {
+	return func(o *Options) {
+		o.EnableScriptTask = b
+	}
+}
+
+// ScriptWorkDir 设置内置脚本任务的工作目录，每个任务会在其下创建独立的临时目录，该目录不限制脚本访问其他路径
+func ScriptWorkDir(dir string) Option {
+	return func(o *Options) {
+		o.ScriptWorkDir = dir
+	}
+}
+
+// ScriptInterpreter 设置内置脚本任务指定脚本类型所使用的解释器
+func ScriptInterpreter(scriptType, interpreter string) Option {
+	return func(o *Options) {
+		o.ScriptInterpreters[scriptType] = interpreter
+	}
+}
+
+// ArtifactCacheDir 设置制品缓存目录，制品按sha256缓存
+func ArtifactCacheDir(dir string) Option {
+	return func(o *Options) {
+		o.ArtifactCacheDir = dir
+	}
+}
+
+// HeartbeatInterval 设置Worker心跳间隔(秒)，心跳时更新注册信息中的任务列表及主机状态
+func HeartbeatInterval(secs int64) Option {
+	return func(o *Options) {
+		o.HeartbeatInterval = secs
+	}
+}
+
+// BuildVersion 设置Worker构建版本，默认使用主模块版本
+func BuildVersion(v string) Option {
+	return func(o *Options) {
+		o.BuildVersion = v
+	}
+}
+
+// MetricsListen 设置Prometheus指标监听地址，为空时不启用
+func MetricsListen(addr string) Option {
+	return func(o *Options) {
+		o.MetricsListen = addr
+	}
+}
+
 // Logger 设置Logger
 func Logger(in *logger.Logger) Option {
 	return func(o *Options) {
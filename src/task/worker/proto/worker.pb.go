@@ -102,6 +102,54 @@ func (m *CallTaskReq) GetTimestamp() int64 {
 	return 0
 }
 
+// CallTaskAck Worker接收任务的应答，同一任务唯一ID重复下发时duplicate为true且不会再次运行
+type CallTaskAck struct {
+	TaskUniqueId         string   `protobuf:"bytes,1,opt,name=task_unique_id,json=taskUniqueId,proto3" json:"task_unique_id,omitempty"`
+	Duplicate            bool     `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CallTaskAck) Reset()         { *m = CallTaskAck{} }
+func (m *CallTaskAck) String() string { return proto.CompactTextString(m) }
+func (*CallTaskAck) ProtoMessage()    {}
+func (*CallTaskAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e4ff6184b07e587a, []int{1}
+}
+
+func (m *CallTaskAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallTaskAck.Unmarshal(m, b)
+}
+func (m *CallTaskAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallTaskAck.Marshal(b, m, deterministic)
+}
+func (m *CallTaskAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallTaskAck.Merge(m, src)
+}
+func (m *CallTaskAck) XXX_Size() int {
+	return xxx_messageInfo_CallTaskAck.Size(m)
+}
+func (m *CallTaskAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallTaskAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CallTaskAck proto.InternalMessageInfo
+
+func (m *CallTaskAck) GetTaskUniqueId() string {
+	if m != nil {
+		return m.TaskUniqueId
+	}
+	return ""
+}
+
+func (m *CallTaskAck) GetDuplicate() bool {
+	if m != nil {
+		return m.Duplicate
+	}
+	return false
+}
+
 type KillTaskReq struct {
 	TaskUniqueId         string   `protobuf:"bytes,1,opt,name=task_unique_id,json=taskUniqueId,proto3" json:"task_unique_id,omitempty"`
 	Timestamp            int64    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
@@ -114,7 +162,7 @@ func (m *KillTaskReq) Reset()         { *m = KillTaskReq{} }
 func (m *KillTaskReq) String() string { return proto.CompactTextString(m) }
 func (*KillTaskReq) ProtoMessage()    {}
 func (*KillTaskReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e4ff6184b07e587a, []int{1}
+	return fileDescriptor_e4ff6184b07e587a, []int{2}
 }
 
 func (m *KillTaskReq) XXX_Unmarshal(b []byte) error {
@@ -149,33 +197,87 @@ func (m *KillTaskReq) GetTimestamp() int64 {
 	return 0
 }
 
+type SetStateReq struct {
+	Value                bool     `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp            int64    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetStateReq) Reset()         { *m = SetStateReq{} }
+func (m *SetStateReq) String() string { return proto.CompactTextString(m) }
+func (*SetStateReq) ProtoMessage()    {}
+func (*SetStateReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e4ff6184b07e587a, []int{3}
+}
+
+func (m *SetStateReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetStateReq.Unmarshal(m, b)
+}
+func (m *SetStateReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetStateReq.Marshal(b, m, deterministic)
+}
+func (m *SetStateReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetStateReq.Merge(m, src)
+}
+func (m *SetStateReq) XXX_Size() int {
+	return xxx_messageInfo_SetStateReq.Size(m)
+}
+func (m *SetStateReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetStateReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetStateReq proto.InternalMessageInfo
+
+func (m *SetStateReq) GetValue() bool {
+	if m != nil {
+		return m.Value
+	}
+	return false
+}
+
+func (m *SetStateReq) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*CallTaskReq)(nil), "workerpb.CallTaskReq")
+	proto.RegisterType((*CallTaskAck)(nil), "workerpb.CallTaskAck")
 	proto.RegisterType((*KillTaskReq)(nil), "workerpb.KillTaskReq")
+	proto.RegisterType((*SetStateReq)(nil), "workerpb.SetStateReq")
 }
 
 func init() { proto.RegisterFile("worker.proto", fileDescriptor_e4ff6184b07e587a) }
 
 var fileDescriptor_e4ff6184b07e587a = []byte{
-	// 280 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0x4f, 0x4b, 0xc3, 0x40,
-	0x14, 0xc4, 0x59, 0xab, 0xb5, 0x7d, 0x8d, 0x82, 0x0b, 0x96, 0xa5, 0x7a, 0x08, 0xd5, 0x43, 0x4e,
-	0x5b, 0xd0, 0x93, 0xe7, 0xe2, 0x41, 0x3c, 0x19, 0x15, 0x8f, 0x65, 0x93, 0x3c, 0x43, 0xc8, 0x9f,
-	0x4d, 0x37, 0x1b, 0xc5, 0x8f, 0xe0, 0x17, 0xf3, 0x73, 0xc9, 0x6e, 0xba, 0x24, 0xa0, 0x3d, 0xbe,
-	0x61, 0x66, 0x98, 0xf7, 0x03, 0xef, 0x53, 0xaa, 0x1c, 0x15, 0xaf, 0x95, 0xd4, 0x92, 0x4e, 0xba,
-	0xab, 0x8e, 0x16, 0x17, 0xa9, 0x94, 0x69, 0x81, 0x2b, 0xab, 0x47, 0xed, 0xfb, 0x0a, 0xcb, 0x5a,
-	0x7f, 0x75, 0xb6, 0xe5, 0x0f, 0x81, 0xd9, 0x5a, 0x14, 0xc5, 0x8b, 0x68, 0xf2, 0x10, 0xb7, 0xf4,
-	0x0a, 0x4e, 0xb4, 0x68, 0xf2, 0x4d, 0x2c, 0x13, 0xac, 0x44, 0x89, 0x8c, 0xf8, 0x24, 0x98, 0x86,
-	0x9e, 0x11, 0xd7, 0x3b, 0x8d, 0x5e, 0xc3, 0xa9, 0x35, 0xb5, 0x55, 0xb6, 0x6d, 0x71, 0x93, 0x25,
-	0xec, 0xa0, 0x77, 0xbd, 0x5a, 0xf1, 0x21, 0xa1, 0x97, 0x30, 0x15, 0x2a, 0x6d, 0x4b, 0xac, 0x74,
-	0xc3, 0x46, 0x3e, 0x09, 0xbc, 0xb0, 0x17, 0x28, 0x83, 0x63, 0x9d, 0x95, 0x28, 0x5b, 0xcd, 0x0e,
-	0x7d, 0x12, 0x8c, 0x42, 0x77, 0xd2, 0x39, 0x8c, 0x63, 0x51, 0x14, 0xa8, 0xd8, 0x91, 0x6d, 0xdd,
-	0x5d, 0xa6, 0xcf, 0x58, 0x1a, 0x2d, 0xca, 0x9a, 0x8d, 0x6d, 0xa6, 0x17, 0x96, 0x4f, 0x30, 0x7b,
-	0xcc, 0xfa, 0x3f, 0xfe, 0x4e, 0x24, 0xff, 0x4f, 0xdc, 0x5f, 0x79, 0xf3, 0x4d, 0xe0, 0xcc, 0xf4,
-	0xbd, 0x59, 0x92, 0xcf, 0xa8, 0x3e, 0xb2, 0x18, 0xe9, 0x1d, 0x4c, 0x1c, 0x30, 0x7a, 0xce, 0x1d,
-	0x65, 0x3e, 0x80, 0xb8, 0x98, 0xf3, 0x0e, 0x39, 0x77, 0xc8, 0xf9, 0xbd, 0x41, 0x6e, 0xa2, 0x6e,
-	0xe3, 0x30, 0x3a, 0xd8, 0xbd, 0x2f, 0x1a, 0x8d, 0xed, 0x7d, 0xfb, 0x1b, 0x00, 0x00, 0xff, 0xff,
-	0x6a, 0xbe, 0x24, 0x3a, 0xe5, 0x01, 0x00, 0x00,
+	// 354 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x41, 0x4b, 0xf3, 0x40,
+	0x14, 0x24, 0xed, 0xd7, 0x7e, 0xe9, 0xb6, 0xdf, 0x07, 0x2e, 0x5a, 0x96, 0xea, 0xa1, 0x54, 0x0f,
+	0x3d, 0xa5, 0xa0, 0x17, 0x15, 0x2f, 0xa5, 0x78, 0x90, 0x5e, 0x34, 0x51, 0x3c, 0x96, 0x6d, 0xf2,
+	0x2c, 0x4b, 0x36, 0xd9, 0x74, 0xb3, 0x5b, 0xf1, 0xea, 0x8f, 0xf3, 0x77, 0xc9, 0x6e, 0x1a, 0x12,
+	0xb0, 0x16, 0x3d, 0xce, 0xf0, 0x66, 0x98, 0x79, 0xef, 0xa1, 0xde, 0xab, 0x90, 0x31, 0x48, 0x2f,
+	0x93, 0x42, 0x09, 0xec, 0x16, 0x28, 0x5b, 0x0e, 0x8e, 0x57, 0x42, 0xac, 0x38, 0x4c, 0x2c, 0xbf,
+	0xd4, 0x2f, 0x13, 0x48, 0x32, 0xf5, 0x56, 0x8c, 0x8d, 0x3e, 0x1c, 0xd4, 0x9d, 0x51, 0xce, 0x1f,
+	0x69, 0x1e, 0xfb, 0xb0, 0xc6, 0xa7, 0xe8, 0x9f, 0xa2, 0x79, 0xbc, 0x08, 0x45, 0x04, 0x29, 0x4d,
+	0x80, 0x38, 0x43, 0x67, 0xdc, 0xf1, 0x7b, 0x86, 0x9c, 0x6d, 0x39, 0x7c, 0x86, 0xfe, 0xdb, 0x21,
+	0x9d, 0xb2, 0xb5, 0x86, 0x05, 0x8b, 0x48, 0xa3, 0x9a, 0x7a, 0xb2, 0xe4, 0x5d, 0x84, 0x4f, 0x50,
+	0x87, 0xca, 0x95, 0x4e, 0x20, 0x55, 0x39, 0x69, 0x0e, 0x9d, 0x71, 0xcf, 0xaf, 0x08, 0x4c, 0xd0,
+	0x5f, 0xc5, 0x12, 0x10, 0x5a, 0x91, 0x3f, 0x43, 0x67, 0xdc, 0xf4, 0x4b, 0x88, 0xfb, 0xa8, 0x1d,
+	0x52, 0xce, 0x41, 0x92, 0x96, 0x75, 0xdd, 0x22, 0xe3, 0x67, 0x46, 0x72, 0x45, 0x93, 0x8c, 0xb4,
+	0xad, 0xa6, 0x22, 0x46, 0x0f, 0x55, 0x8f, 0x69, 0x18, 0xef, 0x88, 0xe8, 0xec, 0x8e, 0x18, 0xe9,
+	0x8c, 0xb3, 0x90, 0x2a, 0xb0, 0x1d, 0x5c, 0xbf, 0x22, 0x8c, 0xe5, 0x9c, 0x55, 0xab, 0xf9, 0xb1,
+	0xe5, 0x9e, 0x94, 0x53, 0xd4, 0x0d, 0x40, 0x05, 0x8a, 0x2a, 0x30, 0x96, 0x87, 0xa8, 0xb5, 0xa1,
+	0x5c, 0x17, 0x5b, 0x76, 0xfd, 0x02, 0xec, 0xb7, 0x38, 0x7f, 0x6f, 0xa0, 0x03, 0x13, 0xe9, 0xd9,
+	0xde, 0x37, 0x00, 0xb9, 0x61, 0x21, 0xe0, 0x4b, 0xe4, 0x96, 0xf5, 0xf1, 0x91, 0x57, 0xde, 0xde,
+	0xab, 0x9d, 0x76, 0xb0, 0x83, 0x36, 0x9b, 0xba, 0x42, 0xee, 0x9c, 0x7d, 0x55, 0xd6, 0x9a, 0x0f,
+	0xfa, 0x5e, 0xf1, 0x42, 0x5e, 0xf9, 0x42, 0xde, 0xad, 0x79, 0x21, 0x7c, 0x63, 0xdb, 0xcc, 0x84,
+	0x8c, 0x44, 0x0a, 0x51, 0x5d, 0x5d, 0x2b, 0xf9, 0xad, 0xfa, 0x1a, 0x75, 0x02, 0x50, 0xf7, 0x54,
+	0xe7, 0xbf, 0xd6, 0x2e, 0xdb, 0x16, 0x5f, 0x7c, 0x0e, 0x00, 0x7f, 0x31, 0xdb, 0xca, 0xf4, 0x02,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -191,9 +293,13 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type TaskWorkerServiceClient interface {
 	// CallTask 调用任务
-	CallTask(ctx context.Context, in *CallTaskReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CallTask(ctx context.Context, in *CallTaskReq, opts ...grpc.CallOption) (*CallTaskAck, error)
 	// KillTask 结束任务
 	KillTask(ctx context.Context, in *KillTaskReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SetCordoned 设置Worker隔离状态，隔离后调度中心不再向其下发新任务
+	SetCordoned(ctx context.Context, in *SetStateReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SetPaused 设置Worker暂停状态，暂停后拒绝新任务调用，运行中的任务继续运行至结束
+	SetPaused(ctx context.Context, in *SetStateReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type taskWorkerServiceClient struct {
@@ -204,8 +310,8 @@ func NewTaskWorkerServiceClient(cc *grpc.ClientConn) TaskWorkerServiceClient {
 	return &taskWorkerServiceClient{cc}
 }
 
-func (c *taskWorkerServiceClient) CallTask(ctx context.Context, in *CallTaskReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
+func (c *taskWorkerServiceClient) CallTask(ctx context.Context, in *CallTaskReq, opts ...grpc.CallOption) (*CallTaskAck, error) {
+	out := new(CallTaskAck)
 	err := c.cc.Invoke(ctx, "/workerpb.TaskWorkerService/CallTask", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -222,12 +328,34 @@ func (c *taskWorkerServiceClient) KillTask(ctx context.Context, in *KillTaskReq,
 	return out, nil
 }
 
+func (c *taskWorkerServiceClient) SetCordoned(ctx context.Context, in *SetStateReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/workerpb.TaskWorkerService/SetCordoned", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskWorkerServiceClient) SetPaused(ctx context.Context, in *SetStateReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/workerpb.TaskWorkerService/SetPaused", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskWorkerServiceServer is the server API for TaskWorkerService service.
 type TaskWorkerServiceServer interface {
 	// CallTask 调用任务
-	CallTask(context.Context, *CallTaskReq) (*emptypb.Empty, error)
+	CallTask(context.Context, *CallTaskReq) (*CallTaskAck, error)
 	// KillTask 结束任务
 	KillTask(context.Context, *KillTaskReq) (*emptypb.Empty, error)
+	// SetCordoned 设置Worker隔离状态，隔离后调度中心不再向其下发新任务
+	SetCordoned(context.Context, *SetStateReq) (*emptypb.Empty, error)
+	// SetPaused 设置Worker暂停状态，暂停后拒绝新任务调用，运行中的任务继续运行至结束
+	SetPaused(context.Context, *SetStateReq) (*emptypb.Empty, error)
 }
 
 func RegisterTaskWorkerServiceServer(s *grpc.Server, srv TaskWorkerServiceServer) {
@@ -270,6 +398,42 @@ func _TaskWorkerService_KillTask_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaskWorkerService_SetCordoned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskWorkerServiceServer).SetCordoned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/workerpb.TaskWorkerService/SetCordoned",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskWorkerServiceServer).SetCordoned(ctx, req.(*SetStateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskWorkerService_SetPaused_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskWorkerServiceServer).SetPaused(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/workerpb.TaskWorkerService/SetPaused",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskWorkerServiceServer).SetPaused(ctx, req.(*SetStateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TaskWorkerService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "workerpb.TaskWorkerService",
 	HandlerType: (*TaskWorkerServiceServer)(nil),
@@ -282,6 +446,14 @@ var _TaskWorkerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "KillTask",
 			Handler:    _TaskWorkerService_KillTask_Handler,
 		},
+		{
+			MethodName: "SetCordoned",
+			Handler:    _TaskWorkerService_SetCordoned_Handler,
+		},
+		{
+			MethodName: "SetPaused",
+			Handler:    _TaskWorkerService_SetPaused_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "worker.proto",
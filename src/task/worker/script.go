@@ -0,0 +1,158 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"eago/task/dto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const scriptMaxLineSize = 1024 * 1024
+
+// scriptResult 内置脚本任务的返回值
+type scriptResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// runScript 内置脚本任务，在独立的工作目录中执行Task模块下发的脚本
+// 脚本以Worker进程的系统用户运行，不做任何隔离，可访问该用户能访问的文件和网络
+func (wk *worker) runScript(ctx context.Context, param *Param) error {
+	sArgs := dto.ScriptTaskArguments{}
+	if err := json.Unmarshal([]byte(param.Arguments), &sArgs); err != nil {
+		return fmt.Errorf("invalid script task arguments: %v", err)
+	}
+
+	interpreter, ok := wk.opts.ScriptInterpreters[sArgs.Interpreter]
+	if !ok {
+		return fmt.Errorf("unsupported script interpreter: %s", sArgs.Interpreter)
+	}
+
+	// 每个任务使用独立的临时工作目录，任务结束后删除，仅用于避免任务间文件冲突，并非隔离环境
+	workDir, err := ioutil.TempDir(wk.opts.ScriptWorkDir, "eago-script-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	// 将引用的制品放入工作目录，没有内联脚本时直接执行制品
+	artifactPath := ""
+	if sArgs.Artifact != nil {
+		content, err := wk.fetchArtifact(ctx, sArgs.Artifact.Sha256)
+		if err != nil {
+			return fmt.Errorf("fetch artifact %s:%d failed: %v", sArgs.Artifact.Name, sArgs.Artifact.Version, err)
+		}
+		artifactPath = filepath.Join(workDir, filepath.Base(sArgs.Artifact.Name))
+		if err = ioutil.WriteFile(artifactPath, content, 0700); err != nil {
+			return err
+		}
+		param.Log.Info("Artifact %s:%d(%s) ready.", sArgs.Artifact.Name, sArgs.Artifact.Version, sArgs.Artifact.Sha256)
+	}
+
+	scriptPath := artifactPath
+	if sArgs.Script != "" {
+		scriptPath = filepath.Join(workDir, "script")
+		if err = ioutil.WriteFile(scriptPath, []byte(sArgs.Script), 0700); err != nil {
+			return err
+		}
+	}
+
+	// 脚本的第一个参数为JSON格式的任务参数
+	cmd := exec.Command(interpreter, scriptPath, string(sArgs.Arguments))
+	cmd.Dir = workDir
+	cmd.Env = scriptEnv(param, workDir, sArgs)
+	if artifactPath != "" {
+		cmd.Env = append(cmd.Env, "EAGO_ARTIFACT_PATH="+artifactPath)
+	}
+	setProcessGroup(cmd)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	param.Log.Info("Running %s script in %s.", sArgs.Interpreter, workDir)
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	// 超时或手动结束时杀死脚本及其派生的所有子进程
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+
+	// 按行将stdout和stderr写入任务日志
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go streamLines(&wg, stdout, param.Log.Info)
+	go streamLines(&wg, stderr, param.Log.Warn)
+	wg.Wait()
+
+	err = cmd.Wait()
+	exitCode := cmd.ProcessState.ExitCode()
+	_ = param.SetReturnValue(scriptResult{ExitCode: exitCode})
+
+	// 退出码非0视为任务失败，超时和手动结束由context判断
+	if err != nil {
+		return fmt.Errorf("script exited with code %d: %v", exitCode, err)
+	}
+
+	param.Log.Info("Script exited with code %d.", exitCode)
+	return nil
+}
+
+// scriptEnv 生成脚本的环境变量，仅继承PATH，避免Worker自身的环境变量泄露给脚本
+// 任务定义的环境变量不能覆盖保留变量
+func scriptEnv(param *Param, workDir string, sArgs dto.ScriptTaskArguments) []string {
+	env := make([]string, 0, len(sArgs.Env)+6)
+	for k, v := range sArgs.Env {
+		if k == "" || strings.Contains(k, "=") || dto.IsReservedScriptEnv(k) {
+			continue
+		}
+		env = append(env, k+"="+v)
+	}
+
+	env = append(env,
+		"PATH="+os.Getenv("PATH"),
+		"HOME="+workDir,
+		"TMPDIR="+workDir,
+		"EAGO_TASK_UNIQUE_ID="+param.TaskUniqueId,
+		"EAGO_TASK_CALLER="+param.Caller,
+		"EAGO_TASK_ARGUMENTS="+string(sArgs.Arguments),
+	)
+
+	return env
+}
+
+// streamLines 按行读取输出并写入任务日志
+func streamLines(wg *sync.WaitGroup, r io.Reader, write func(format string, a ...interface{})) {
+	defer wg.Done()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), scriptMaxLineSize)
+	for scanner.Scan() {
+		write("%s", scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		write("Read script output failed: %s", err.Error())
+		// 读取失败后丢弃剩余输出，避免脚本因管道写满而阻塞
+		_, _ = io.Copy(ioutil.Discard, r)
+	}
+}
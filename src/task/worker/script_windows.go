@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package worker
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup Windows下不设置进程组
+func setProcessGroup(_ *exec.Cmd) {}
+
+// killProcessGroup Windows下仅杀死脚本进程
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"eago/common/logger"
 	workerpb "eago/task/worker/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -24,14 +26,22 @@ func NewTaskWorkerService(wk *worker, logger *logger.Logger) *taskWorkerService
 }
 
 // CallTask 调用任务
-func (tws *taskWorkerService) CallTask(_ context.Context, req *workerpb.CallTaskReq) (*emptypb.Empty, error) {
+func (tws *taskWorkerService) CallTask(_ context.Context, req *workerpb.CallTaskReq) (*workerpb.CallTaskAck, error) {
 	tws.logger.InfoWithFields(logger.Fields{
 		"worker_id":      tws.wk.workerId,
 		"task_codename":  req.TaskCodename,
 		"task_unique_id": req.TaskUniqueId,
 		"caller":         req.Caller,
 	}, "Got a call task request.")
-	tws.wk.callTask(
+	// 暂停的Worker拒绝新任务，由调用方处理失败
+	if tws.wk.isPaused() {
+		tws.logger.WarnWithFields(logger.Fields{
+			"worker_id":      tws.wk.workerId,
+			"task_unique_id": req.TaskUniqueId,
+		}, "Worker is paused, call task request rejected.")
+		return nil, status.Errorf(codes.Unavailable, "worker %s is paused", tws.wk.workerId)
+	}
+	dup := tws.wk.callTask(
 		req.TaskCodename,
 		req.TaskUniqueId,
 		string(req.Arguments),
@@ -40,7 +50,8 @@ func (tws *taskWorkerService) CallTask(_ context.Context, req *workerpb.CallTask
 		req.Timestamp,
 	)
 
-	return &emptypb.Empty{}, nil
+	// 应答任务唯一ID，调度中心据此确认任务已送达
+	return &workerpb.CallTaskAck{TaskUniqueId: req.TaskUniqueId, Duplicate: dup}, nil
 }
 
 // KillTask 结束任务
@@ -53,3 +64,37 @@ func (tws *taskWorkerService) KillTask(_ context.Context, req *workerpb.KillTask
 
 	return &emptypb.Empty{}, nil
 }
+
+// SetCordoned 设置Worker隔离状态
+func (tws *taskWorkerService) SetCordoned(_ context.Context, req *workerpb.SetStateReq) (*emptypb.Empty, error) {
+	tws.logger.InfoWithFields(logger.Fields{
+		"worker_id": tws.wk.workerId,
+		"cordoned":  req.Value,
+	}, "Got a set cordoned request.")
+	if err := tws.wk.setCordoned(req.Value); err != nil {
+		tws.logger.ErrorWithFields(logger.Fields{
+			"worker_id": tws.wk.workerId,
+			"error":     err,
+		}, "An error occurred while worker.setCordoned.")
+		return nil, status.Errorf(codes.Internal, "update worker register info failed: %s", err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// SetPaused 设置Worker暂停状态
+func (tws *taskWorkerService) SetPaused(_ context.Context, req *workerpb.SetStateReq) (*emptypb.Empty, error) {
+	tws.logger.InfoWithFields(logger.Fields{
+		"worker_id": tws.wk.workerId,
+		"paused":    req.Value,
+	}, "Got a set paused request.")
+	if err := tws.wk.setPaused(req.Value); err != nil {
+		tws.logger.ErrorWithFields(logger.Fields{
+			"worker_id": tws.wk.workerId,
+			"error":     err,
+		}, "An error occurred while worker.setPaused.")
+		return nil, status.Errorf(codes.Internal, "update worker register info failed: %s", err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
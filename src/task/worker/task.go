@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -17,16 +18,36 @@ type Param struct {
 	LocalStartTime  time.Time
 	RemoteStartTime time.Time
 	Log             ResultLog
+
+	returnValue []byte
+}
+
+// SetReturnValue 设置任务返回值，任务结束时会以JSON格式回传至Task模块
+func (p *Param) SetReturnValue(v interface{}) error {
+	rv, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	p.returnValue = rv
+	return nil
+}
+
+// GetReturnValue 获得任务返回值
+func (p *Param) GetReturnValue() []byte {
+	return p.returnValue
 }
 
 // Task struct
 type Task struct {
-	Codename string
-	Param    *Param
-	Cxt      context.Context
-	Cancel   context.CancelFunc
-	fn       TaskFunc
-	logger   *resultLog
+	Codename     string
+	Description  string
+	FormalParams string
+	Param        *Param
+	Cxt          context.Context
+	Cancel       context.CancelFunc
+	fn           TaskFunc
+	logger       *resultLog
 }
 
 // Run 运行任务
@@ -50,6 +71,23 @@ func (t *Task) Run(callback func(err error)) {
 	callback(err)
 }
 
+// TaskOption 任务注册选项
+type TaskOption func(t *Task)
+
+// TaskDescription 设置任务描述，同步至任务目录
+func TaskDescription(desc string) TaskOption {
+	return func(t *Task) {
+		t.Description = desc
+	}
+}
+
+// TaskFormalParams 设置任务形参(JSON Schema)，同步至任务目录
+func TaskFormalParams(schema string) TaskOption {
+	return func(t *Task) {
+		t.FormalParams = schema
+	}
+}
+
 func (t *Task) Info() string {
 	return fmt.Sprintf("TaskCodename: %s.", t.Codename)
 }
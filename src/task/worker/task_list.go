@@ -1,6 +1,10 @@
 package worker
 
-import "sync"
+import (
+	"eago/task/dto"
+	"sort"
+	"sync"
+)
 
 type taskList struct {
 	mu    sync.RWMutex
@@ -48,8 +52,40 @@ func (t *taskList) List() map[string]*Task {
 	return t.tasks
 }
 
+// Keys 获取全部Key，按字典序排列
+func (t *taskList) Keys() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	keys := make([]string, 0, len(t.tasks))
+	for k := range t.tasks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Metas 获取全部任务的元信息，按任务名排列
+func (t *taskList) Metas() []*dto.WorkerTaskMeta {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	metas := make([]*dto.WorkerTaskMeta, 0, len(t.tasks))
+	for k, task := range t.tasks {
+		metas = append(metas, &dto.WorkerTaskMeta{
+			Codename:     k,
+			Description:  task.Description,
+			FormalParams: task.FormalParams,
+		})
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].Codename < metas[j].Codename })
+	return metas
+}
+
 // Len 长度
 func (t *taskList) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return len(t.tasks)
 }
 
@@ -5,6 +5,8 @@ import (
 	"eago/cli"
 	"eago/common/global"
 	"eago/common/logger"
+	"eago/common/metrics"
+	"eago/task/conf/msg"
 	"eago/task/dto"
 	taskpb "eago/task/proto"
 	workerpb "eago/task/worker/proto"
@@ -12,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/coreos/etcd/clientv3"
+	microErrors "github.com/micro/go-micro/v2/errors"
 	"github.com/satori/go.uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -19,13 +22,15 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"net"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type Worker interface {
-	RegTask(codename string, fn TaskFunc)
+	// RegTask 注册任务，可通过TaskOption设置任务描述和形参
+	RegTask(codename string, fn TaskFunc, opts ...TaskOption)
 
 	// Start 启动Worker服务
 	Start() error
@@ -47,13 +52,21 @@ type worker struct {
 	etcdCli   *clientv3.Client
 	etcdLease clientv3.Lease
 
-	mu sync.RWMutex
+	mu         sync.RWMutex
+	artifactMu sync.Mutex
 
 	logger *logger.Logger
 
 	opts        Options
 	startTime   *time.Time
 	runningFlag int32
+	leaseId     int64
+	// 隔离和暂停状态仅保存在内存中，Worker重启后恢复
+	cordoned int32
+	paused   int32
+
+	ctx        context.Context
+	cancelFunc context.CancelFunc
 }
 
 // NewWorker 创建Worker
@@ -86,7 +99,7 @@ func newWorker(options ...Option) *worker {
 		panic(err)
 	}
 
-	return &worker{
+	wk := &worker{
 		workerId: wkId,
 
 		taskCli: cli.NewTaskClient(opts.EtcdUsername, opts.EtcdPassword, opts.EtcdAddresses),
@@ -100,35 +113,51 @@ func newWorker(options ...Option) *worker {
 
 		opts: opts,
 	}
+
+	// 注册内置脚本任务
+	if opts.EnableScriptTask {
+		wk.taskList.Put(dto.ScriptTaskName, &Task{Codename: dto.ScriptTaskName, fn: wk.runScript})
+	}
+
+	return wk
 }
 
-// callTask 运行任务务
-func (wk *worker) callTask(codename, uniqueId, args string, timeout int64, caller string, ts int64) {
+// callTask 运行任务务，同一任务唯一ID重复下发时不再运行并返回true
+func (wk *worker) callTask(codename, uniqueId, args string, timeout int64, caller string, ts int64) (duplicate bool) {
 	wk.mu.Lock()
 	defer wk.mu.Unlock()
 
 	// 查看当前Worker是否注册了调用的任务
 	if !wk.taskList.Exists(codename) {
+		metrics.ObserveTaskResult(codename, dto.TaskResultStatusWorkerTaskNotFoundErrEnd)
 		if err := wk.setTaskStatus(uniqueId, dto.TaskResultStatusWorkerTaskNotFoundErrEnd); err != nil {
 			wk.logger.ErrorWithFields(logger.Fields{
 				"task_unique_id": uniqueId,
 				"error":          err,
 			}, "An error occurred while taskServiceClient.SetTaskStatus.")
-			return
+			return false
 		}
-		return
+		return false
 	}
 
 	// 查看调用的任务是否在运行
 	if wk.runningList.Exists(uniqueId) {
-		wk.logger.ErrorWithFields(logger.Fields{
+		wk.logger.WarnWithFields(logger.Fields{
 			"task_unique_id": uniqueId,
-		}, "An error occurred while worker.runTask, Task already running.")
-		return
+		}, "Task already running, duplicate call task request ignored.")
+		return true
 	}
 
 	// 设置任务为Pending状态
 	if err := wk.setTaskStatus(uniqueId, dto.TaskResultStatusPending); err != nil {
+		// 任务结果已不允许变更为Pending，说明任务已被接收或已结束，属于重复下发
+		if isIllegalStatusErr(err) {
+			wk.logger.WarnWithFields(logger.Fields{
+				"task_unique_id": uniqueId,
+				"error":          err,
+			}, "Task already accepted or ended, duplicate call task request ignored.")
+			return true
+		}
 		wk.logger.ErrorWithFields(logger.Fields{
 			"task_unique_id": uniqueId,
 			"error":          err,
@@ -171,6 +200,7 @@ func (wk *worker) callTask(codename, uniqueId, args string, timeout int64, calle
 		}()
 
 		// 设置任务为Running状态
+		metrics.WorkerTaskStarted(codename)
 		if err := wk.setTaskStatus(uniqueId, dto.TaskResultStatusRunning); err != nil {
 			wk.logger.ErrorWithFields(logger.Fields{
 				"task_unique_id": uniqueId,
@@ -200,6 +230,8 @@ func (wk *worker) callTask(codename, uniqueId, args string, timeout int64, calle
 
 	// 为新任务开启日志消费者
 	go wk.logConsumer(&task)
+
+	return false
 }
 
 // killTask 杀任务
@@ -208,32 +240,16 @@ func (wk *worker) killTask(taskUniqueId string) {
 	defer wk.mu.Unlock()
 
 	// 查看要杀死的任务是否在运行
-	if wk.runningList.Exists(taskUniqueId) {
+	if !wk.runningList.Exists(taskUniqueId) {
 		wk.logger.ErrorWithFields(logger.Fields{
 			"task_unique_id": taskUniqueId,
 		}, "An error occurred while worker.killTask, Task is not in running state.")
 		return
 	}
 
-	// 结束任务
+	// 通过context结束任务，任务返回后由callback设置手动结束状态、关闭日志通道并删除任务
 	t := wk.runningList.Get(taskUniqueId)
 	t.Cancel()
-
-	// 设置任务为手动结束状态
-	if err := wk.setTaskStatus(taskUniqueId, dto.TaskResultStatusManualEnd); err != nil {
-		wk.logger.ErrorWithFields(logger.Fields{
-			"task_unique_id": taskUniqueId,
-			"error":          err,
-		}, "An error occurred while taskServiceClient.SetTaskStatus.")
-		// 失败仅记录日志，不跳出
-	}
-
-	// 关闭日志通道
-	t.logger.wg.Wait()
-	close(t.logger.logCh)
-
-	// 删除任务
-	wk.runningList.Delete(taskUniqueId)
 }
 
 // callback 回调调度中心
@@ -245,12 +261,14 @@ func (wk *worker) callback(task *Task, status int) {
 		// 删除任务
 		wk.runningList.Delete(task.Param.TaskUniqueId)
 	}()
+	metrics.WorkerTaskFinished(task.Codename, status)
 
 	// 关闭日志通道
 	task.logger.wg.Wait()
 	close(task.logger.logCh)
 
-	if err := wk.setTaskStatus(task.Param.TaskUniqueId, status); err != nil {
+	// 结束任务时同时回传任务返回值
+	if err := wk.setTaskStatus(task.Param.TaskUniqueId, status, task.Param.GetReturnValue()); err != nil {
 		wk.logger.ErrorWithFields(logger.Fields{
 			"task_unique_id": task.Param.TaskUniqueId,
 			"status":         status,
@@ -313,9 +331,12 @@ func (wk *worker) logConsumer(task *Task) {
 	}
 }
 
-// setTaskStatus 设置任务状态
-func (wk *worker) setTaskStatus(taskUniqueId string, status int) error {
+// setTaskStatus 设置任务状态，可选择同时回传任务返回值
+func (wk *worker) setTaskStatus(taskUniqueId string, status int, returnValue ...[]byte) error {
 	req := &taskpb.SetResultStatusReq{TaskUniqueId: taskUniqueId, Status: int32(status)}
+	if len(returnValue) > 0 {
+		req.ReturnValue = returnValue[0]
+	}
 	if _, err := wk.taskCli.SetResultStatus(context.Background(), req); err != nil {
 		return err
 	}
@@ -323,14 +344,22 @@ func (wk *worker) setTaskStatus(taskUniqueId string, status int) error {
 	return nil
 }
 
+// isIllegalStatusErr 判断是否为任务结果状态不允许变更的错误
+func isIllegalStatusErr(err error) bool {
+	return microErrors.FromError(err).Code == int32(msg.MsgSetResultStatusIllegalFailed.GetCode())
+}
+
 // RegTask 注册任务
-func (wk *worker) RegTask(codename string, fn TaskFunc) {
+func (wk *worker) RegTask(codename string, fn TaskFunc, opts ...TaskOption) {
 	wk.mu.Lock()
 	defer wk.mu.Unlock()
 
 	var t = &Task{}
 	t.Codename = codename
 	t.fn = fn
+	for _, o := range opts {
+		o(t)
+	}
 
 	wk.taskList.Put(codename, t)
 }
@@ -355,6 +384,18 @@ func (wk *worker) Start() (err error) {
 	// 注册GRPC服务
 	workerpb.RegisterTaskWorkerServiceServer(wk.workerSrv, NewTaskWorkerService(wk, wk.logger))
 
+	wk.ctx, wk.cancelFunc = context.WithCancel(context.Background())
+
+	// 暴露Prometheus指标
+	go func() {
+		if err := metrics.Serve(wk.ctx, wk.opts.MetricsListen); err != nil {
+			wk.logger.ErrorWithFields(logger.Fields{
+				"address": wk.opts.MetricsListen,
+				"error":   err,
+			}, "An error occurred while metrics.Serve.")
+		}
+	}()
+
 	// 设置当前Worker的IP和端口(endpoint)信息
 	wk.endpoint = wk.listener.Addr().String()
 	wk.logger.Info(fmt.Sprintf("Worker %s listening on %s.", wk.workerId, wk.endpoint))
@@ -367,6 +408,9 @@ func (wk *worker) Start() (err error) {
 		panic(err)
 	}
 
+	// 定期上报心跳
+	go wk.heartbeat()
+
 	return wk.workerSrv.Serve(wk.listener)
 }
 
@@ -383,6 +427,9 @@ func (wk *worker) Stop() {
 	if wk.workerSrv != nil {
 		wk.workerSrv.Stop()
 	}
+	if wk.cancelFunc != nil {
+		wk.cancelFunc()
+	}
 	if wk.etcdCli != nil {
 		_ = wk.etcdCli.Close()
 	}
@@ -436,13 +483,8 @@ func (wk *worker) register() error {
 	}()
 
 	// 生成注册Key和Value
-	regK := fmt.Sprintf("%s/%s/%s", WorkerRegisterKeyPrefix, wk.opts.ServiceName, wk.workerId)
-	regV, _ := json.Marshal(dto.WorkerInfo{
-		Modular:   wk.opts.ServiceName,
-		Address:   wk.endpoint,
-		WorkerId:  wk.workerId,
-		StartTime: time.Now().Format(global.TimestampFormat),
-	})
+	regK := wk.registerKey()
+	regV, _ := json.Marshal(wk.workerInfo())
 
 	txn := clientv3.NewKV(wk.etcdCli).Txn(ctx)
 	// 注册到etcd
@@ -465,19 +507,96 @@ func (wk *worker) register() error {
 		return errors.New("another worker instance is already running using same worker id")
 	}
 
+	// 记录租约用于心跳更新注册信息
+	atomic.StoreInt64(&wk.leaseId, int64(leaseGrantResp.ID))
+
 	return nil
 }
 
+// heartbeat 定期更新注册信息，上报已注册任务及主机状态
+func (wk *worker) heartbeat() {
+	if wk.opts.HeartbeatInterval < 1 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(wk.opts.HeartbeatInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wk.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := wk.refreshRegister(); err != nil {
+				wk.logger.WarnWithFields(logger.Fields{
+					"error": err,
+				}, "An error occurred while worker.refreshRegister in worker.heartbeat, skipped it.")
+			}
+		}
+	}
+}
+
+// refreshRegister 使用当前租约更新注册信息，租约失效时等待重新注册
+func (wk *worker) refreshRegister() error {
+	leaseId := clientv3.LeaseID(atomic.LoadInt64(&wk.leaseId))
+	if leaseId == 0 || !wk.isRunning() {
+		return nil
+	}
+
+	regV, _ := json.Marshal(wk.workerInfo())
+	_, err := wk.etcdCli.Put(wk.ctx, wk.registerKey(), string(regV), clientv3.WithLease(leaseId))
+	return err
+}
+
+// registerKey 获得Worker注册Key
+func (wk *worker) registerKey() string {
+	return fmt.Sprintf("%s/%s/%s", WorkerRegisterKeyPrefix, wk.opts.ServiceName, wk.workerId)
+}
+
+// workerInfo 生成Worker注册信息
+func (wk *worker) workerInfo() *dto.WorkerInfo {
+	now := time.Now()
+	startTime := now
+	if wk.startTime != nil {
+		startTime = *wk.startTime
+	}
+
+	memStats := runtime.MemStats{}
+	runtime.ReadMemStats(&memStats)
+	memTotal, memAvailable := hostMemory()
+
+	return &dto.WorkerInfo{
+		Modular:   wk.opts.ServiceName,
+		Address:   wk.endpoint,
+		WorkerId:  wk.workerId,
+		StartTime: startTime.Format(global.TimestampFormat),
+
+		Codenames:     wk.taskList.Keys(),
+		Tasks:         wk.taskList.Metas(),
+		RunningTasks:  wk.runningList.Len(),
+		LoadAvg:       hostLoadAvg(),
+		MemTotal:      memTotal,
+		MemAvailable:  memAvailable,
+		ProcessMemSys: memStats.Sys,
+		GoVersion:     runtime.Version(),
+		BuildVersion:  wk.opts.BuildVersion,
+		HeartbeatTime: now.Format(global.TimestampFormat),
+
+		Cordoned: wk.isCordoned(),
+		Paused:   wk.isPaused(),
+	}
+}
+
 func (wk *worker) unregister() {
 	wk.logger.Info(fmt.Sprintf("Worker %s unregister called.", wk.workerId))
 	defer wk.logger.Info(fmt.Sprintf("Worker %s unregister end.", wk.workerId))
 
+	atomic.StoreInt64(&wk.leaseId, 0)
 	if wk.etcdLease != nil {
 		_ = wk.etcdLease.Close()
 	}
 
-	k := fmt.Sprintf("/%s/%s/%s", WorkerRegisterKeyPrefix, wk.opts.ServiceName, wk.workerId)
-	_, err := wk.etcdCli.Delete(context.Background(), k)
+	_, err := wk.etcdCli.Delete(context.Background(), wk.registerKey())
 	if err != nil {
 		wk.logger.ErrorWithFields(logger.Fields{
 			"error": err,
@@ -562,3 +681,33 @@ func (wk *worker) setRunningFlag(b bool) {
 func (wk *worker) isRunning() bool {
 	return atomic.LoadInt32(&wk.runningFlag) != 0
 }
+
+// setCordoned 设置Worker隔离状态，并立即更新注册信息使调度中心尽快感知
+func (wk *worker) setCordoned(b bool) error {
+	var v int32
+	if b {
+		v = 1
+	}
+	atomic.StoreInt32(&wk.cordoned, v)
+	return wk.refreshRegister()
+}
+
+// isCordoned 判断worker是否被隔离
+func (wk *worker) isCordoned() bool {
+	return atomic.LoadInt32(&wk.cordoned) != 0
+}
+
+// setPaused 设置Worker暂停状态，并立即更新注册信息使调度中心尽快感知
+func (wk *worker) setPaused(b bool) error {
+	var v int32
+	if b {
+		v = 1
+	}
+	atomic.StoreInt32(&wk.paused, v)
+	return wk.refreshRegister()
+}
+
+// isPaused 判断worker是否被暂停
+func (wk *worker) isPaused() bool {
+	return atomic.LoadInt32(&wk.paused) != 0
+}